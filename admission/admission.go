@@ -0,0 +1,147 @@
+// Package admission bounds how much work a server accepts: transport limits
+// on streams, message sizes and connection lifetime, plus a load shedding
+// interceptor that turns calls away once too many are in flight.
+package admission
+
+import (
+	"context"
+	"flag"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+// Config holds the server limits. Zero values leave the grpc default.
+type Config struct {
+	//MaxConcurrentStreams caps streams per HTTP/2 connection.
+	MaxConcurrentStreams uint
+	MaxRecvMsgSize       int
+	MaxSendMsgSize       int
+
+	//KeepaliveMinTime is the shortest ping interval clients may use;
+	//clients pinging faster are disconnected.
+	KeepaliveMinTime             time.Duration
+	KeepalivePermitWithoutStream bool
+	KeepaliveTime                time.Duration
+	KeepaliveTimeout             time.Duration
+
+	MaxConnectionIdle     time.Duration
+	MaxConnectionAge      time.Duration
+	MaxConnectionAgeGrace time.Duration
+
+	//MaxInFlight is the load shedding threshold across all RPCs.
+	MaxInFlight int
+}
+
+// RegisterFlags defines the admission flags on fs and returns the Config
+// they fill in once fs is parsed.
+func RegisterFlags(fs *flag.FlagSet) *Config {
+	c := &Config{}
+	fs.UintVar(&c.MaxConcurrentStreams, "max-concurrent-streams", 100, "max concurrent streams per connection")
+	fs.IntVar(&c.MaxRecvMsgSize, "max-recv-msg-size", 4<<20, "max size in bytes of a received message")
+	fs.IntVar(&c.MaxSendMsgSize, "max-send-msg-size", 4<<20, "max size in bytes of a sent message")
+	fs.DurationVar(&c.KeepaliveMinTime, "keepalive-min-time", 10*time.Second, "minimum client ping interval")
+	fs.BoolVar(&c.KeepalivePermitWithoutStream, "keepalive-permit-without-stream", false, "allow client pings with no active streams")
+	fs.DurationVar(&c.KeepaliveTime, "keepalive-time", 2*time.Hour, "ping clients after this much idle time")
+	fs.DurationVar(&c.KeepaliveTimeout, "keepalive-timeout", 20*time.Second, "close the connection if a ping is not acked in time")
+	fs.DurationVar(&c.MaxConnectionIdle, "max-connection-idle", 0, "close connections idle for this long, 0 for never")
+	fs.DurationVar(&c.MaxConnectionAge, "max-connection-age", 0, "close connections older than this, 0 for never")
+	fs.DurationVar(&c.MaxConnectionAgeGrace, "max-connection-age-grace", 30*time.Second, "time given to RPCs to finish after max-connection-age")
+	fs.IntVar(&c.MaxInFlight, "max-in-flight", 1000, "reject calls with Unavailable above this many in flight, 0 to disable")
+	return c
+}
+
+// ServerOptions returns the grpc.ServerOptions implementing the transport
+// limits in c.
+func (c *Config) ServerOptions() []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if c.MaxConcurrentStreams > 0 {
+		n := c.MaxConcurrentStreams
+		if n > math.MaxUint32 {
+			n = math.MaxUint32
+		}
+		opts = append(opts, grpc.MaxConcurrentStreams(uint32(n)))
+	}
+	if c.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(c.MaxRecvMsgSize))
+	}
+	if c.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(c.MaxSendMsgSize))
+	}
+	opts = append(opts,
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             c.KeepaliveMinTime,
+			PermitWithoutStream: c.KeepalivePermitWithoutStream,
+		}),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     c.MaxConnectionIdle,
+			MaxConnectionAge:      c.MaxConnectionAge,
+			MaxConnectionAgeGrace: c.MaxConnectionAgeGrace,
+			Time:                  c.KeepaliveTime,
+			Timeout:               c.KeepaliveTimeout,
+		}),
+	)
+	return opts
+}
+
+// Shedder rejects calls once the number in flight reaches a threshold.
+type Shedder struct {
+	max      int64
+	inFlight atomic.Int64
+}
+
+// NewShedder returns a Shedder admitting at most max concurrent calls.
+// A max of zero admits everything.
+func NewShedder(max int) *Shedder {
+	return &Shedder{max: int64(max)}
+}
+
+// InFlight returns the number of calls currently admitted.
+func (s *Shedder) InFlight() int64 {
+	return s.inFlight.Load()
+}
+
+func (s *Shedder) acquire() bool {
+	n := s.inFlight.Add(1)
+	if s.max > 0 && n > s.max {
+		s.inFlight.Add(-1)
+		return false
+	}
+	return true
+}
+
+func (s *Shedder) release() {
+	s.inFlight.Add(-1)
+}
+
+func overloaded() error {
+	return status.Error(codes.Unavailable, "server overloaded, try again later")
+}
+
+// UnaryServerInterceptor sheds unary calls above the threshold.
+func (s *Shedder) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !s.acquire() {
+			return nil, overloaded()
+		}
+		defer s.release()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor sheds streams above the threshold. A stream
+// counts as in flight for as long as it stays open.
+func (s *Shedder) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !s.acquire() {
+			return overloaded()
+		}
+		defer s.release()
+		return handler(srv, ss)
+	}
+}
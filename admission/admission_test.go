@@ -0,0 +1,169 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	unaryInfo  = &grpc.UnaryServerInfo{FullMethod: "/test.Service/Unary"}
+	streamInfo = &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}
+)
+
+// waitInFlight waits for s to admit n calls.
+func waitInFlight(t *testing.T, s *Shedder, n int64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for s.InFlight() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("InFlight = %d, want %d", s.InFlight(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestShedderAtLimit(t *testing.T) {
+	s := NewShedder(2)
+	unary := s.UnaryServerInterceptor()
+	stream := s.StreamServerInterceptor()
+
+	block := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		unary(context.Background(), nil, unaryInfo, func(context.Context, interface{}) (interface{}, error) {
+			<-block
+			return nil, nil
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		stream(nil, nil, streamInfo, func(interface{}, grpc.ServerStream) error {
+			<-block
+			return nil
+		})
+	}()
+	waitInFlight(t, s, 2)
+
+	called := false
+	_, err := unary(context.Background(), nil, unaryInfo, func(context.Context, interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+	if status.Code(err) != codes.Unavailable || called {
+		t.Errorf("unary at the limit: err = %v, handler called = %v", err, called)
+	}
+	err = stream(nil, nil, streamInfo, func(interface{}, grpc.ServerStream) error {
+		called = true
+		return nil
+	})
+	if status.Code(err) != codes.Unavailable || called {
+		t.Errorf("stream at the limit: err = %v, handler called = %v", err, called)
+	}
+	//Shed calls must not hold a slot.
+	if n := s.InFlight(); n != 2 {
+		t.Errorf("InFlight after shedding = %d, want 2", n)
+	}
+
+	close(block)
+	wg.Wait()
+	waitInFlight(t, s, 0)
+	if _, err := unary(context.Background(), nil, unaryInfo, func(context.Context, interface{}) (interface{}, error) {
+		return nil, nil
+	}); err != nil {
+		t.Errorf("unary after release: %v", err)
+	}
+}
+
+func TestShedderReleasesOnError(t *testing.T) {
+	s := NewShedder(1)
+	unary := s.UnaryServerInterceptor()
+	stream := s.StreamServerInterceptor()
+	failed := status.Error(codes.Internal, "boom")
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "unary error", call: func() error {
+			_, err := unary(context.Background(), nil, unaryInfo, func(context.Context, interface{}) (interface{}, error) {
+				return nil, failed
+			})
+			return err
+		}},
+		{name: "stream error", call: func() error {
+			return stream(nil, nil, streamInfo, func(interface{}, grpc.ServerStream) error {
+				return failed
+			})
+		}},
+		{name: "unary panic", call: func() (err error) {
+			//A recovery interceptor further out turns the panic into an
+			//error; the slot must be released on the way.
+			defer func() {
+				if r := recover(); r != nil {
+					err = failed
+				}
+			}()
+			unary(context.Background(), nil, unaryInfo, func(context.Context, interface{}) (interface{}, error) {
+				panic("boom")
+			})
+			return nil
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			//With a limit of one, every call after a leak would be shed.
+			for i := 0; i < 3; i++ {
+				if err := tc.call(); !errors.Is(err, failed) {
+					t.Fatalf("call %d: err = %v, want %v", i, err, failed)
+				}
+			}
+			if n := s.InFlight(); n != 0 {
+				t.Errorf("InFlight = %d, want 0", n)
+			}
+		})
+	}
+}
+
+func TestShedderUnlimited(t *testing.T) {
+	s := NewShedder(0)
+	unary := s.UnaryServerInterceptor()
+	for i := 0; i < 100; i++ {
+		if !s.acquire() {
+			t.Fatalf("call %d shed with no limit", i)
+		}
+	}
+	defer func() {
+		for i := 0; i < 100; i++ {
+			s.release()
+		}
+	}()
+	if _, err := unary(context.Background(), nil, unaryInfo, func(context.Context, interface{}) (interface{}, error) {
+		return nil, nil
+	}); err != nil {
+		t.Errorf("unary with no limit: %v", err)
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	c := RegisterFlags(fs)
+	if err := fs.Parse([]string{"-max-in-flight=5", "-max-recv-msg-size=1024"}); err != nil {
+		t.Fatal(err)
+	}
+	if c.MaxInFlight != 5 || c.MaxRecvMsgSize != 1024 || c.MaxConcurrentStreams != 100 {
+		t.Errorf("Config = %+v", c)
+	}
+	if n := len(c.ServerOptions()); n != 5 {
+		t.Errorf("ServerOptions returned %d options, want 5", n)
+	}
+}
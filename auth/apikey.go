@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// apiKeyFile is the on-disk format of the API key file:
+//
+//	{"keys": [{"key": "dev-secret", "subject": "joe", "roles": ["admin"]}]}
+type apiKeyFile struct {
+	Keys []struct {
+		Key     string   `json:"key"`
+		Subject string   `json:"subject"`
+		Roles   []string `json:"roles"`
+	} `json:"keys"`
+}
+
+// APIKeyAuthenticator accepts a fixed set of static keys.
+type APIKeyAuthenticator struct {
+	keys map[[sha256.Size]byte]*Principal
+}
+
+// LoadAPIKeys reads the API keys in path.
+func LoadAPIKeys(path string) (*APIKeyAuthenticator, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("auth: reading api keys: %v", err)
+	}
+	var f apiKeyFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("auth: parsing api keys %s: %v", path, err)
+	}
+	a := &APIKeyAuthenticator{keys: make(map[[sha256.Size]byte]*Principal)}
+	for i, k := range f.Keys {
+		if k.Key == "" || k.Subject == "" {
+			return nil, fmt.Errorf("auth: api key %d in %s needs a key and subject", i, path)
+		}
+		a.keys[sha256.Sum256([]byte(k.Key))] = &Principal{Subject: k.Subject, Roles: k.Roles}
+	}
+	return a, nil
+}
+
+// Authenticate looks token up by its hash so lookups don't leak timing on
+// the key itself.
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	p, ok := a.keys[sha256.Sum256([]byte(token))]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return p, nil
+}
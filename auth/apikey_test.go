@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAPIKeys(t *testing.T) {
+	a, err := LoadAPIKeys("api_keys.example.json")
+	if err != nil {
+		t.Fatalf("LoadAPIKeys: %v", err)
+	}
+	tests := []struct {
+		token   string
+		subject string
+		admin   bool
+	}{
+		{"dev-admin-key", "admin", true},
+		{"dev-author-key", "Joe Frizzell", false},
+		{"dev-author-key ", "", false},
+		{"DEV-ADMIN-KEY", "", false},
+		{"", "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.token, func(t *testing.T) {
+			p, err := a.Authenticate(context.Background(), tc.token)
+			if tc.subject == "" {
+				if err != ErrInvalidToken {
+					t.Errorf("Authenticate = %+v, %v, want %v", p, err, ErrInvalidToken)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate: %v", err)
+			}
+			if p.Subject != tc.subject || p.HasRole("admin") != tc.admin {
+				t.Errorf("principal = %+v, want subject %q admin=%v", p, tc.subject, tc.admin)
+			}
+		})
+	}
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	tests := []struct {
+		name string
+		path string
+		ok   bool
+	}{
+		{"valid", write("ok.json", `{"keys": [{"key": "k", "subject": "s"}]}`), true},
+		{"empty", write("empty.json", `{"keys": []}`), true},
+		{"missing", filepath.Join(dir, "nope.json"), false},
+		{"not json", write("bad.json", "{"), false},
+		{"no key", write("nokey.json", `{"keys": [{"subject": "s"}]}`), false},
+		{"no subject", write("nosubject.json", `{"keys": [{"key": "k"}]}`), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := LoadAPIKeys(tc.path); (err == nil) != tc.ok {
+				t.Errorf("LoadAPIKeys error = %v, want ok=%v", err, tc.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Package auth authenticates callers from bearer tokens in gRPC metadata.
+// Development setups use static API keys loaded from a file; production
+// setups verify JWTs against a local JWKS file.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Supported authentication modes.
+const (
+	ModeNone   = "none"
+	ModeAPIKey = "apikey"
+	ModeJWT    = "jwt"
+)
+
+// ErrInvalidToken is returned when a token is unknown, malformed or expired.
+var ErrInvalidToken = errors.New("auth: invalid token")
+
+// Principal is the authenticated caller.
+type Principal struct {
+	Subject string
+	Roles   []string
+}
+
+// HasRole reports whether p has been granted role.
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator resolves a bearer token into a Principal.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying p.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx by the interceptors.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// Config selects and configures an Authenticator.
+type Config struct {
+	Mode        string //none, apikey or jwt
+	APIKeysFile string
+	JWKSFile    string
+	Issuer      string
+	Audience    string
+}
+
+// New builds the Authenticator described by cfg. It returns nil for ModeNone.
+func New(cfg Config) (Authenticator, error) {
+	switch cfg.Mode {
+	case "", ModeNone:
+		return nil, nil
+	case ModeAPIKey:
+		//Return a nil interface, not a nil *APIKeyAuthenticator, on error.
+		a, err := LoadAPIKeys(cfg.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		return a, nil
+	case ModeJWT:
+		a, err := LoadJWKS(cfg.JWKSFile, cfg.Issuer, cfg.Audience)
+		if err != nil {
+			return nil, err
+		}
+		return a, nil
+	}
+	return nil, fmt.Errorf("auth: unknown mode %q", cfg.Mode)
+}
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// tokenCredentials attaches a bearer token to every RPC.
+type tokenCredentials struct {
+	token    string
+	insecure bool
+}
+
+// BearerToken returns per-RPC credentials sending token as
+// "authorization: Bearer <token>". grpc only sends them over TLS.
+func BearerToken(token string) credentials.PerRPCCredentials {
+	return tokenCredentials{token: token}
+}
+
+// InsecureBearerToken is BearerToken for plaintext connections. Use it for
+// local development only; the token travels in the clear.
+func InsecureBearerToken(token string) credentials.PerRPCCredentials {
+	return tokenCredentials{token: token, insecure: true}
+}
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return !t.insecure
+}
@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startServer serves the health service behind the auth interceptors. The
+// returned channel receives the subject each handler saw, "" for none.
+func startServer(t *testing.T) (*bufconn.Listener, <-chan string) {
+	t.Helper()
+	a, err := LoadAPIKeys("api_keys.example.json")
+	if err != nil {
+		t.Fatalf("LoadAPIKeys: %v", err)
+	}
+	seen := make(chan string, 16)
+	subject := func(ctx context.Context) {
+		p, _ := FromContext(ctx)
+		if p == nil {
+			seen <- ""
+			return
+		}
+		seen <- p.Subject
+	}
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(a),
+			func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				subject(ctx)
+				return handler(ctx, req)
+			}),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor(a),
+			func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				subject(ss.Context())
+				return handler(srv, ss)
+			}),
+	)
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis, seen
+}
+
+func dialServer(t *testing.T, lis *bufconn.Listener, opts ...grpc.DialOption) healthpb.HealthClient {
+	t.Helper()
+	opts = append(opts,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}))
+	cc, err := grpc.NewClient("passthrough:///bufconn", opts...)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return healthpb.NewHealthClient(cc)
+}
+
+func TestInterceptors(t *testing.T) {
+	lis, seen := startServer(t)
+	anon := dialServer(t, lis)
+	withAuth := func(v string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", v)
+	}
+	list := func(ctx context.Context) error {
+		_, err := anon.List(ctx, &healthpb.HealthListRequest{})
+		return err
+	}
+	watch := func(ctx context.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stream, err := anon.Watch(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			return err
+		}
+		_, err = stream.Recv()
+		return err
+	}
+
+	tests := []struct {
+		name    string
+		call    func(context.Context) error
+		ctx     context.Context
+		code    codes.Code
+		subject string
+	}{
+		{"public unary without token", func(ctx context.Context) error {
+			_, err := anon.Check(ctx, &healthpb.HealthCheckRequest{})
+			return err
+		}, context.Background(), codes.OK, ""},
+		{"public stream without token", watch, context.Background(), codes.OK, ""},
+		{"unary without token", list, context.Background(), codes.Unauthenticated, ""},
+		{"unary with key", list, withAuth("Bearer dev-author-key"), codes.OK, "Joe Frizzell"},
+		{"scheme is case-insensitive", list, withAuth("bearer dev-admin-key"), codes.OK, "admin"},
+		{"unknown key", list, withAuth("Bearer nope"), codes.Unauthenticated, ""},
+		{"other scheme", list, withAuth("Basic dev-admin-key"), codes.Unauthenticated, ""},
+		{"empty token", list, withAuth("Bearer "), codes.Unauthenticated, ""},
+		{"public stream with key", watch, withAuth("Bearer dev-admin-key"), codes.OK, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call(tc.ctx)
+			if status.Code(err) != tc.code {
+				t.Fatalf("call error = %v, want %v", err, tc.code)
+			}
+			if err != nil {
+				return
+			}
+			if got := <-seen; got != tc.subject {
+				t.Errorf("handler saw subject %q, want %q", got, tc.subject)
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	lis, seen := startServer(t)
+
+	c := dialServer(t, lis, grpc.WithPerRPCCredentials(InsecureBearerToken("dev-author-key")))
+	if _, err := c.List(context.Background(), &healthpb.HealthListRequest{}); err != nil {
+		t.Fatalf("List with InsecureBearerToken: %v", err)
+	}
+	if got := <-seen; got != "Joe Frizzell" {
+		t.Errorf("handler saw subject %q, want Joe Frizzell", got)
+	}
+
+	//BearerToken refuses to send the token over plaintext.
+	if BearerToken("k").RequireTransportSecurity() != true || InsecureBearerToken("k").RequireTransportSecurity() != false {
+		t.Error("RequireTransportSecurity does not match the constructor")
+	}
+	if cc, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(BearerToken("dev-author-key"))); err == nil {
+		cc.Close()
+		t.Error("BearerToken was accepted for a plaintext connection")
+	}
+	md, err := BearerToken("k").GetRequestMetadata(context.Background())
+	if err != nil || md["authorization"] != "Bearer k" {
+		t.Errorf("GetRequestMetadata = %v, %v", md, err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		cfg  Config
+		none bool
+		ok   bool
+	}{
+		{Config{}, true, true},
+		{Config{Mode: ModeNone}, true, true},
+		{Config{Mode: ModeAPIKey, APIKeysFile: "api_keys.example.json"}, false, true},
+		{Config{Mode: ModeAPIKey, APIKeysFile: "missing.json"}, true, false},
+		{Config{Mode: ModeJWT, JWKSFile: "missing.json"}, true, false},
+		{Config{Mode: "oauth"}, true, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.cfg.Mode, func(t *testing.T) {
+			a, err := New(tc.cfg)
+			if (err == nil) != tc.ok || (a == nil) != tc.none {
+				t.Errorf("New(%+v) = %v, %v", tc.cfg, a, err)
+			}
+		})
+	}
+}
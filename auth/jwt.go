@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+// signingAlgorithms are the JWS algorithms accepted from token issuers.
+var signingAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+const (
+	//clockSkew is the leeway allowed on exp, nbf and iat.
+	clockSkew = 30 * time.Second
+	//maxTokenLifetime caps how long after it was issued, or after now
+	//when it carries no iat, a token may expire.
+	maxTokenLifetime = 24 * time.Hour
+)
+
+// JWTAuthenticator verifies JWTs against the keys in a JWKS file.
+type JWTAuthenticator struct {
+	keys     jose.JSONWebKeySet
+	issuer   string
+	audience string
+}
+
+// roleClaims are the non-registered claims read from a token.
+type roleClaims struct {
+	Roles []string `json:"roles"`
+}
+
+// LoadJWKS reads a JSON Web Key Set from path. Empty issuer or audience
+// disable the respective check.
+func LoadJWKS(path, issuer, audience string) (*JWTAuthenticator, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("auth: reading jwks: %v", err)
+	}
+	a := &JWTAuthenticator{issuer: issuer, audience: audience}
+	if err := json.Unmarshal(b, &a.keys); err != nil {
+		return nil, fmt.Errorf("auth: parsing jwks %s: %v", path, err)
+	}
+	if len(a.keys.Keys) == 0 {
+		return nil, fmt.Errorf("auth: jwks %s has no keys", path)
+	}
+	return a, nil
+}
+
+// Authenticate verifies the token signature and registered claims. Tokens
+// must carry exp, at most maxTokenLifetime after iat. The subject becomes
+// the principal and the "roles" claim its roles.
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	tok, err := jwt.ParseSigned(token, signingAlgorithms)
+	if err != nil || len(tok.Headers) != 1 {
+		return nil, ErrInvalidToken
+	}
+	keys := a.keys.Key(tok.Headers[0].KeyID)
+	if len(keys) == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	var claims jwt.Claims
+	var extra roleClaims
+	if err := tok.Claims(keys[0].Key, &claims, &extra); err != nil {
+		return nil, ErrInvalidToken
+	}
+	//ValidateWithLeeway only checks exp when it is present, and a token
+	//without one would be valid forever.
+	if claims.Expiry == nil {
+		return nil, ErrInvalidToken
+	}
+	now := time.Now()
+	issued := now
+	if claims.IssuedAt != nil {
+		issued = claims.IssuedAt.Time()
+	}
+	if claims.Expiry.Time().Sub(issued) > maxTokenLifetime {
+		return nil, ErrInvalidToken
+	}
+	expected := jwt.Expected{Issuer: a.issuer, Time: now}
+	if a.audience != "" {
+		expected.AnyAudience = jwt.Audience{a.audience}
+	}
+	if err := claims.ValidateWithLeeway(expected, clockSkew); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	return &Principal{Subject: claims.Subject, Roles: extra.Roles}, nil
+}
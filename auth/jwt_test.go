@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+// testIssuer signs tokens with one of several keys published in a JWKS
+// file.
+type testIssuer struct {
+	keys map[string]interface{} //kid to private key
+	jwks string
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iss := &testIssuer{keys: map[string]interface{}{"ec": ec, "rsa": rs}}
+	set := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: &ec.PublicKey, KeyID: "ec", Algorithm: string(jose.ES256), Use: "sig"},
+		{Key: &rs.PublicKey, KeyID: "rsa", Algorithm: string(jose.RS256), Use: "sig"},
+	}}
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iss.jwks = filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(iss.jwks, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return iss
+}
+
+// sign signs claims with the key kid, publishing it under header kid.
+func (iss *testIssuer) sign(t *testing.T, kid, header string, claims ...interface{}) string {
+	t.Helper()
+	alg := jose.ES256
+	if kid == "rsa" {
+		alg = jose.RS256
+	}
+	var key interface{} = iss.keys[kid]
+	if key == nil {
+		//A key the JWKS does not know.
+		key, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	sig, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: key}, (&jose.SignerOptions{}).WithHeader("kid", header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := jwt.Signed(sig)
+	for _, c := range claims {
+		b = b.Claims(c)
+	}
+	tok, err := b.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok
+}
+
+func TestJWTAuthenticate(t *testing.T) {
+	iss := newTestIssuer(t)
+	a, err := LoadJWKS(iss.jwks, "https://issuer.test", "blog")
+	if err != nil {
+		t.Fatalf("LoadJWKS: %v", err)
+	}
+	now := time.Now()
+	valid := func() jwt.Claims {
+		return jwt.Claims{
+			Subject:  "ann",
+			Issuer:   "https://issuer.test",
+			Audience: jwt.Audience{"blog"},
+			IssuedAt: jwt.NewNumericDate(now),
+			Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+		}
+	}
+	with := func(f func(*jwt.Claims)) jwt.Claims {
+		c := valid()
+		f(&c)
+		return c
+	}
+	roles := map[string]interface{}{"roles": []string{"author", "admin"}}
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"ec key", iss.sign(t, "ec", "ec", valid(), roles), true},
+		{"rsa key by kid", iss.sign(t, "rsa", "rsa", valid()), true},
+		{"kid names another key", iss.sign(t, "ec", "rsa", valid()), false},
+		{"unknown kid", iss.sign(t, "ec", "missing", valid()), false},
+		{"unknown key", iss.sign(t, "other", "ec", valid()), false},
+		{"wrong issuer", iss.sign(t, "ec", "ec", with(func(c *jwt.Claims) { c.Issuer = "https://evil.test" })), false},
+		{"wrong audience", iss.sign(t, "ec", "ec", with(func(c *jwt.Claims) { c.Audience = jwt.Audience{"calculator"} })), false},
+		{"one of several audiences", iss.sign(t, "ec", "ec", with(func(c *jwt.Claims) { c.Audience = jwt.Audience{"calculator", "blog"} })), true},
+		{"expired", iss.sign(t, "ec", "ec", with(func(c *jwt.Claims) { c.Expiry = jwt.NewNumericDate(now.Add(-time.Minute)) })), false},
+		{"expired within skew", iss.sign(t, "ec", "ec", with(func(c *jwt.Claims) { c.Expiry = jwt.NewNumericDate(now.Add(-clockSkew / 2)) })), true},
+		{"not yet valid", iss.sign(t, "ec", "ec", with(func(c *jwt.Claims) { c.NotBefore = jwt.NewNumericDate(now.Add(time.Minute)) })), false},
+		{"no expiry", iss.sign(t, "ec", "ec", with(func(c *jwt.Claims) { c.Expiry = nil })), false},
+		{"lifetime too long", iss.sign(t, "ec", "ec", with(func(c *jwt.Claims) { c.Expiry = jwt.NewNumericDate(now.Add(maxTokenLifetime + time.Hour)) })), false},
+		{"no iat, expiry too far", iss.sign(t, "ec", "ec", with(func(c *jwt.Claims) {
+			c.IssuedAt = nil
+			c.Expiry = jwt.NewNumericDate(now.Add(maxTokenLifetime + time.Hour))
+		})), false},
+		{"no iat", iss.sign(t, "ec", "ec", with(func(c *jwt.Claims) { c.IssuedAt = nil })), true},
+		{"no subject", iss.sign(t, "ec", "ec", with(func(c *jwt.Claims) { c.Subject = "" })), false},
+		{"garbage", "not.a.token", false},
+		{"empty", "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := a.Authenticate(context.Background(), tc.token)
+			if !tc.ok {
+				if err != ErrInvalidToken {
+					t.Errorf("Authenticate = %+v, %v, want %v", p, err, ErrInvalidToken)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate: %v", err)
+			}
+			if p.Subject != "ann" {
+				t.Errorf("subject = %q, want ann", p.Subject)
+			}
+		})
+	}
+
+	p, err := a.Authenticate(context.Background(), iss.sign(t, "ec", "ec", valid(), roles))
+	if err != nil || !p.HasRole("admin") || !p.HasRole("author") || p.HasRole("reader") {
+		t.Errorf("roles = %+v, %v, want author and admin", p, err)
+	}
+}
+
+func TestJWTChecksDisabled(t *testing.T) {
+	iss := newTestIssuer(t)
+	a, err := LoadJWKS(iss.jwks, "", "")
+	if err != nil {
+		t.Fatalf("LoadJWKS: %v", err)
+	}
+	tok := iss.sign(t, "ec", "ec", jwt.Claims{
+		Subject:  "ann",
+		Issuer:   "anyone",
+		Audience: jwt.Audience{"anything"},
+		Expiry:   jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+	if _, err := a.Authenticate(context.Background(), tok); err != nil {
+		t.Errorf("Authenticate without issuer and audience checks: %v", err)
+	}
+}
+
+func TestLoadJWKS(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"missing", filepath.Join(dir, "nope.json")},
+		{"not json", write("bad.json", "{")},
+		{"no keys", write("empty.json", `{"keys": []}`)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := LoadJWKS(tc.path, "", ""); err == nil {
+				t.Error("LoadJWKS succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Package authz enforces a declarative per-method policy on authenticated
+// callers. A policy maps roles to the methods they may call and marks
+// methods that additionally require the caller to own the target resource.
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/auth"
+)
+
+// Policy is the declarative rule set. Method names are full gRPC method
+// names such as "/blog.BlogService/ReadBlog"; "*" matches every method.
+type Policy struct {
+	//Public methods may be called by any caller the auth interceptor let through.
+	Public []string `json:"public"`
+	//Roles maps a role to the methods it may call.
+	Roles map[string][]string `json:"roles"`
+	//OwnerOnly methods also require the caller to own the resource.
+	OwnerOnly []string `json:"owner_only"`
+	//OwnerBypass roles skip the ownership check.
+	OwnerBypass []string `json:"owner_bypass"`
+}
+
+// LoadPolicy reads a JSON encoded Policy from path.
+func LoadPolicy(path string) (*Policy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("authz: reading policy: %v", err)
+	}
+	p := &Policy{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("authz: parsing policy %s: %v", path, err)
+	}
+	return p, nil
+}
+
+// OwnerFunc returns the owner of the resource targeted by req. Returned
+// errors should already be status errors, e.g. NotFound. A nil OwnerFunc
+// means the handler enforces ownership itself, typically by scoping its
+// write to OwnerScope.
+type OwnerFunc func(ctx context.Context, req interface{}) (string, error)
+
+func contains(list []string, method string) bool {
+	for _, m := range list {
+		if m == "*" || m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func denied(format string, a ...interface{}) error {
+	return status.Errorf(codes.PermissionDenied, "permission denied: "+format, a...)
+}
+
+// allowed checks the role grants for method. It returns the principal so
+// callers can go on to check ownership.
+func (p *Policy) allowed(ctx context.Context, method string) (*auth.Principal, bool, error) {
+	if contains(p.Public, method) {
+		return nil, true, nil
+	}
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, false, denied("%s requires an authenticated caller", method)
+	}
+	for _, role := range principal.Roles {
+		if contains(p.Roles[role], method) {
+			return principal, false, nil
+		}
+	}
+	if len(principal.Roles) == 0 {
+		return nil, false, denied("%s has no roles and may not call %s", principal.Subject, method)
+	}
+	return nil, false, denied("roles [%s] may not call %s", strings.Join(principal.Roles, ", "), method)
+}
+
+// bypass reports whether principal holds an OwnerBypass role.
+func (p *Policy) bypass(principal *auth.Principal) bool {
+	for _, role := range p.OwnerBypass {
+		if principal.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// Restricted returns the subject of an authenticated caller that holds no
+// OwnerBypass role. Such a caller may only act as itself, e.g. create
+// resources owned by that subject. A nil policy restricts no one.
+func (p *Policy) Restricted(ctx context.Context) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	principal, ok := auth.FromContext(ctx)
+	if !ok || p.bypass(principal) {
+		return "", false
+	}
+	return principal.Subject, true
+}
+
+// OwnerScope returns the subject whose resources a restricted caller may
+// change through method. It reports false when method is not OwnerOnly or
+// the caller is not restricted, in which case any resource may be changed.
+func (p *Policy) OwnerScope(ctx context.Context, method string) (string, bool) {
+	if p == nil || !contains(p.OwnerOnly, method) {
+		return "", false
+	}
+	return p.Restricted(ctx)
+}
+
+// checkOwner applies the ownership rule to unary calls.
+func (p *Policy) checkOwner(ctx context.Context, principal *auth.Principal, method string, req interface{}, owners map[string]OwnerFunc) error {
+	if !contains(p.OwnerOnly, method) {
+		return nil
+	}
+	if p.bypass(principal) {
+		return nil
+	}
+	owner, ok := owners[method]
+	if !ok {
+		return denied("no ownership rule configured for %s", method)
+	}
+	if owner == nil {
+		return nil
+	}
+	o, err := owner(ctx, req)
+	if err != nil {
+		return err
+	}
+	if o != principal.Subject {
+		return denied("only the author or an admin may call %s", method)
+	}
+	return nil
+}
+
+// UnaryServerInterceptor enforces p on unary calls. owners supplies the
+// resource owner lookup for every OwnerOnly method. It must run after the
+// auth interceptor.
+func UnaryServerInterceptor(p *Policy, owners map[string]OwnerFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		principal, public, err := p.allowed(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		if !public {
+			if err := p.checkOwner(ctx, principal, info.FullMethod, req, owners); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor enforces the role grants of p on streams.
+// Ownership cannot be checked before the first message, so OwnerOnly
+// streaming methods are limited to OwnerBypass roles.
+func StreamServerInterceptor(p *Policy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		principal, public, err := p.allowed(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		if !public && contains(p.OwnerOnly, info.FullMethod) {
+			if !p.bypass(principal) {
+				return denied("only an admin may stream %s", info.FullMethod)
+			}
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,213 @@
+package authz
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/auth"
+)
+
+const (
+	methodCreate = "/blog.BlogService/CreateBlog"
+	methodRead   = "/blog.BlogService/ReadBlog"
+	methodUpdate = "/blog.BlogService/UpdateBlog"
+	methodDelete = "/blog.BlogService/DeleteBlog"
+	methodList   = "/blog.BlogService/ListBlog"
+)
+
+func testPolicy() *Policy {
+	return &Policy{
+		Public: []string{methodRead, methodList},
+		Roles: map[string][]string{
+			"admin":  {"*"},
+			"author": {methodCreate, methodUpdate, methodDelete},
+		},
+		OwnerOnly:   []string{methodUpdate, methodDelete},
+		OwnerBypass: []string{"admin"},
+	}
+}
+
+var (
+	admin    = &auth.Principal{Subject: "root", Roles: []string{"admin"}}
+	author   = &auth.Principal{Subject: "ann", Roles: []string{"author"}}
+	reader   = &auth.Principal{Subject: "rob", Roles: []string{"reader"}}
+	noRoles  = &auth.Principal{Subject: "nia"}
+	twoRoles = &auth.Principal{Subject: "ted", Roles: []string{"reader", "author"}}
+)
+
+func withPrincipal(p *auth.Principal) context.Context {
+	if p == nil {
+		return context.Background()
+	}
+	return auth.NewContext(context.Background(), p)
+}
+
+// ownedBy returns an OwnerFunc reporting owner for every request.
+func ownedBy(owner string) OwnerFunc {
+	return func(context.Context, interface{}) (string, error) {
+		return owner, nil
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	annOwns := map[string]OwnerFunc{methodUpdate: ownedBy("ann"), methodDelete: ownedBy("ann")}
+	bobOwns := map[string]OwnerFunc{methodUpdate: ownedBy("bob"), methodDelete: ownedBy("bob")}
+	handlerScoped := map[string]OwnerFunc{methodUpdate: nil, methodDelete: nil}
+	notFound := map[string]OwnerFunc{methodUpdate: func(context.Context, interface{}) (string, error) {
+		return "", status.Error(codes.NotFound, "no such blog")
+	}}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		method    string
+		owners    map[string]OwnerFunc
+		want      codes.Code
+	}{
+		{name: "public without caller", method: methodRead, want: codes.OK},
+		{name: "public with unlisted role", principal: reader, method: methodRead, want: codes.OK},
+		{name: "unauthenticated", method: methodCreate, want: codes.PermissionDenied},
+		{name: "admin wildcard", principal: admin, method: "/other.Service/Anything", want: codes.OK},
+		{name: "author create", principal: author, method: methodCreate, want: codes.OK},
+		{name: "author unlisted method", principal: author, method: "/other.Service/Anything", want: codes.PermissionDenied},
+		{name: "unlisted role", principal: reader, method: methodCreate, want: codes.PermissionDenied},
+		{name: "no roles", principal: noRoles, method: methodCreate, want: codes.PermissionDenied},
+		{name: "any granting role", principal: twoRoles, method: methodCreate, want: codes.OK},
+		{name: "author owns", principal: author, method: methodUpdate, owners: annOwns, want: codes.OK},
+		{name: "author does not own", principal: author, method: methodDelete, owners: bobOwns, want: codes.PermissionDenied},
+		{name: "admin bypasses owner", principal: admin, method: methodUpdate, owners: bobOwns, want: codes.OK},
+		{name: "handler scoped", principal: author, method: methodUpdate, owners: handlerScoped, want: codes.OK},
+		{name: "no ownership rule", principal: author, method: methodDelete, owners: nil, want: codes.PermissionDenied},
+		{name: "owner lookup error", principal: author, method: methodUpdate, owners: notFound, want: codes.NotFound},
+		{name: "unlisted role before owner", principal: reader, method: methodUpdate, owners: handlerScoped, want: codes.PermissionDenied},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return "ok", nil
+			}
+			interceptor := UnaryServerInterceptor(testPolicy(), tc.owners)
+			_, err := interceptor(withPrincipal(tc.principal), "req", &grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+			if got := status.Code(err); got != tc.want {
+				t.Fatalf("code = %v (%v), want %v", got, err, tc.want)
+			}
+			if called != (tc.want == codes.OK) {
+				t.Errorf("handler called = %v, want %v", called, tc.want == codes.OK)
+			}
+		})
+	}
+}
+
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		method    string
+		want      codes.Code
+	}{
+		{name: "public", method: methodList, want: codes.OK},
+		{name: "unauthenticated", method: methodCreate, want: codes.PermissionDenied},
+		{name: "author", principal: author, method: methodCreate, want: codes.OK},
+		{name: "unlisted role", principal: reader, method: methodCreate, want: codes.PermissionDenied},
+		//Ownership cannot be checked before the first message.
+		{name: "author owner only", principal: author, method: methodUpdate, want: codes.PermissionDenied},
+		{name: "admin owner only", principal: admin, method: methodUpdate, want: codes.OK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			handler := func(srv interface{}, ss grpc.ServerStream) error {
+				called = true
+				return nil
+			}
+			interceptor := StreamServerInterceptor(testPolicy())
+			ss := &testStream{ctx: withPrincipal(tc.principal)}
+			err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: tc.method}, handler)
+			if got := status.Code(err); got != tc.want {
+				t.Fatalf("code = %v (%v), want %v", got, err, tc.want)
+			}
+			if called != (tc.want == codes.OK) {
+				t.Errorf("handler called = %v, want %v", called, tc.want == codes.OK)
+			}
+		})
+	}
+}
+
+func TestOwnerScope(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    *Policy
+		principal *auth.Principal
+		method    string
+		//restricted is the Restricted result, scope the OwnerScope one.
+		restricted string
+		scope      string
+	}{
+		{name: "nil policy", principal: author, method: methodUpdate},
+		{name: "unauthenticated", policy: testPolicy(), method: methodUpdate},
+		{name: "admin update", policy: testPolicy(), principal: admin, method: methodUpdate},
+		{name: "author update", policy: testPolicy(), principal: author, method: methodUpdate, restricted: "ann", scope: "ann"},
+		{name: "author delete", policy: testPolicy(), principal: author, method: methodDelete, restricted: "ann", scope: "ann"},
+		{name: "author create", policy: testPolicy(), principal: author, method: methodCreate, restricted: "ann"},
+		{name: "unlisted role", policy: testPolicy(), principal: reader, method: methodDelete, restricted: "rob", scope: "rob"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := withPrincipal(tc.principal)
+			subject, ok := tc.policy.Restricted(ctx)
+			if subject != tc.restricted || ok != (tc.restricted != "") {
+				t.Errorf("Restricted = %q, %v, want %q", subject, ok, tc.restricted)
+			}
+			subject, ok = tc.policy.OwnerScope(ctx, tc.method)
+			if subject != tc.scope || ok != (tc.scope != "") {
+				t.Errorf("OwnerScope(%s) = %q, %v, want %q", tc.method, subject, ok, tc.scope)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "policy.json")
+	err := os.WriteFile(good, []byte(`{"public":["/a/B"],"roles":{"author":["/a/C"]},"owner_only":["/a/C"],"owner_bypass":["admin"]}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadPolicy(good)
+	if err != nil {
+		t.Fatalf("LoadPolicy: %v", err)
+	}
+	if !contains(p.Public, "/a/B") || !contains(p.Roles["author"], "/a/C") || !contains(p.OwnerOnly, "/a/C") || !contains(p.OwnerBypass, "admin") {
+		t.Errorf("LoadPolicy = %+v", p)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"public":`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{bad, filepath.Join(dir, "missing.json")} {
+		if _, err := LoadPolicy(path); err == nil {
+			t.Errorf("LoadPolicy(%s) succeeded", filepath.Base(path))
+		}
+	}
+}
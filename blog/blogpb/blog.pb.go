@@ -88,7 +88,10 @@ func (m *Blog) GetContent() string {
 }
 
 type CreateBlogRequest struct {
-	Blog                 *Blog    `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	Blog *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	//Optional idempotency key. Retrying with the same request_id returns
+	//the blog created by the first call instead of a duplicate.
+	RequestId            string   `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -126,6 +129,13 @@ func (m *CreateBlogRequest) GetBlog() *Blog {
 	return nil
 }
 
+func (m *CreateBlogRequest) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
 type CreateBlogResponse struct {
 	Blog                 *Blog    `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -244,7 +254,10 @@ func (m *ReadBlogResponse) GetBlog() *Blog {
 }
 
 type UpdateBlogRequest struct {
-	Blog                 *Blog    `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	Blog *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	//Optional. The Blog fields to change, e.g. "title" or "content". Empty
+	//replaces author_id, title and content.
+	UpdateMask           []string `protobuf:"bytes,2,rep,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -282,6 +295,13 @@ func (m *UpdateBlogRequest) GetBlog() *Blog {
 	return nil
 }
 
+func (m *UpdateBlogRequest) GetUpdateMask() []string {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
 type UpdateBlogResponse struct {
 	Blog                 *Blog    `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -400,6 +420,8 @@ func (m *DeleteBlogResponse) GetBlogId() string {
 }
 
 type ListBlogRequest struct {
+	//Optional. Only list blogs by this author.
+	AuthorId             string   `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -430,6 +452,13 @@ func (m *ListBlogRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ListBlogRequest proto.InternalMessageInfo
 
+func (m *ListBlogRequest) GetAuthorId() string {
+	if m != nil {
+		return m.AuthorId
+	}
+	return ""
+}
+
 type ListBlogResponse struct {
 	Blog                 *Blog    `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -486,30 +515,32 @@ func init() {
 func init() { proto.RegisterFile("blog/blogpb/blog.proto", fileDescriptor_a4b0406114889fe6) }
 
 var fileDescriptor_a4b0406114889fe6 = []byte{
-	// 355 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0x41, 0x4f, 0xf2, 0x40,
-	0x14, 0xfc, 0xe8, 0x87, 0x50, 0x86, 0x44, 0xec, 0x46, 0xa1, 0xc1, 0xc4, 0x98, 0x9e, 0x8c, 0x51,
-	0x34, 0xe0, 0xc5, 0x78, 0x20, 0xa2, 0x17, 0x12, 0x4f, 0x18, 0x2f, 0x5e, 0x08, 0xb0, 0x1b, 0xdc,
-	0xa4, 0x61, 0x6b, 0xbb, 0xf8, 0x23, 0xfd, 0x55, 0x66, 0x77, 0x59, 0x5b, 0x5b, 0x88, 0xf5, 0x02,
-	0x7d, 0x6f, 0x67, 0xdf, 0xcc, 0x9b, 0xc9, 0xa2, 0x3d, 0x0f, 0xc5, 0xf2, 0x4a, 0xfd, 0x44, 0x73,
-	0xfd, 0xd7, 0x8b, 0x62, 0x21, 0x05, 0xa9, 0xaa, 0xef, 0x60, 0x81, 0xea, 0x28, 0x14, 0x4b, 0xb2,
-	0x0f, 0x87, 0x53, 0xbf, 0x72, 0x5a, 0x39, 0x6b, 0x4c, 0x1c, 0x4e, 0xc9, 0x31, 0x1a, 0xb3, 0xb5,
-	0x7c, 0x13, 0xf1, 0x94, 0x53, 0xdf, 0xd1, 0x6d, 0xd7, 0x34, 0xc6, 0x94, 0x1c, 0x62, 0x4f, 0x72,
-	0x19, 0x32, 0xff, 0xbf, 0x3e, 0x30, 0x05, 0xf1, 0x51, 0x5f, 0x88, 0x95, 0x64, 0x2b, 0xe9, 0x57,
-	0x75, 0xdf, 0x96, 0xc1, 0x00, 0xde, 0x43, 0xcc, 0x66, 0x92, 0x29, 0xaa, 0x09, 0x7b, 0x5f, 0xb3,
-	0x44, 0x92, 0x13, 0x68, 0x05, 0x9a, 0xb3, 0xd9, 0x47, 0x4f, 0x4b, 0xd3, 0x00, 0xa3, 0xec, 0x06,
-	0x24, 0x7b, 0x29, 0x89, 0xc4, 0x2a, 0x61, 0xbf, 0xde, 0x3a, 0x47, 0x6b, 0xc2, 0x66, 0x34, 0x4b,
-	0xd4, 0x41, 0x5d, 0x1d, 0x4d, 0xbf, 0xf7, 0xab, 0xa9, 0x72, 0x4c, 0x83, 0x3e, 0x0e, 0x52, 0x6c,
-	0xc9, 0xf9, 0x03, 0x78, 0x2f, 0x11, 0xfd, 0xfb, 0x2a, 0xd9, 0x4b, 0x25, 0xa9, 0x2e, 0xe0, 0x3d,
-	0xb2, 0x90, 0xfd, 0xa4, 0xda, 0xb9, 0xcc, 0x25, 0x48, 0x16, 0xbd, 0xe1, 0xd8, 0x09, 0xf7, 0xd0,
-	0x7a, 0xe2, 0x89, 0xcc, 0x8c, 0x56, 0x76, 0xa4, 0xad, 0x72, 0x1a, 0xfb, 0x9f, 0x0e, 0x9a, 0xaa,
-	0x7c, 0x66, 0xf1, 0x07, 0x5f, 0x30, 0x72, 0x0f, 0xa4, 0xa1, 0x91, 0x8e, 0xc1, 0x17, 0xb2, 0xef,
-	0xfa, 0xc5, 0x03, 0x43, 0x18, 0xfc, 0x23, 0xb7, 0x70, 0x6d, 0x2a, 0xe4, 0xc8, 0xe0, 0x72, 0x89,
-	0x76, 0xdb, 0xf9, 0xf6, 0x46, 0xed, 0x10, 0x48, 0x7d, 0xb6, 0xec, 0x85, 0xb8, 0x2c, 0xfb, 0x96,
-	0x48, 0x86, 0x40, 0x6a, 0xa2, 0x1d, 0x50, 0x08, 0xc1, 0x0e, 0xd8, 0xe2, 0xf7, 0x1d, 0x5c, 0xeb,
-	0xa1, 0x15, 0x9f, 0xb3, 0xd9, 0x8a, 0xcf, 0x5b, 0x7d, 0x5d, 0x19, 0xb9, 0xaf, 0x35, 0xf3, 0x4c,
-	0xe7, 0x35, 0xfd, 0x44, 0x07, 0x5f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xc2, 0xf4, 0x66, 0x18, 0xbc,
-	0x03, 0x00, 0x00,
+	// 395 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x41, 0x4f, 0xf2, 0x40,
+	0x10, 0xfd, 0x5a, 0xf8, 0xa0, 0x1d, 0x12, 0x91, 0x8d, 0xc2, 0xa6, 0x46, 0x25, 0x3d, 0x11, 0xa3,
+	0x68, 0xd0, 0x8b, 0xf1, 0x60, 0x44, 0x2f, 0x24, 0x7a, 0xa9, 0x7a, 0xf1, 0x42, 0x0a, 0xbb, 0xc1,
+	0x86, 0xca, 0xd6, 0x76, 0xf1, 0x47, 0xfa, 0xab, 0xcc, 0xee, 0xb6, 0x6e, 0x69, 0x63, 0xac, 0x17,
+	0xe8, 0xbc, 0x79, 0xfb, 0xe6, 0xcd, 0xce, 0xb4, 0xd0, 0x9d, 0x85, 0x6c, 0x71, 0x2a, 0x7e, 0xa2,
+	0x99, 0xfc, 0x1b, 0x46, 0x31, 0xe3, 0x0c, 0xd5, 0xc5, 0xb3, 0x3b, 0x87, 0xfa, 0x38, 0x64, 0x0b,
+	0xb4, 0x05, 0x66, 0x40, 0xb0, 0xd1, 0x37, 0x06, 0xb6, 0x67, 0x06, 0x04, 0xed, 0x81, 0xed, 0xaf,
+	0xf9, 0x2b, 0x8b, 0xa7, 0x01, 0xc1, 0xa6, 0x84, 0x2d, 0x05, 0x4c, 0x08, 0xda, 0x81, 0xff, 0x3c,
+	0xe0, 0x21, 0xc5, 0x35, 0x99, 0x50, 0x01, 0xc2, 0xd0, 0x9c, 0xb3, 0x15, 0xa7, 0x2b, 0x8e, 0xeb,
+	0x12, 0xcf, 0x42, 0xd7, 0x83, 0xce, 0x6d, 0x4c, 0x7d, 0x4e, 0x45, 0x29, 0x8f, 0xbe, 0xaf, 0x69,
+	0xc2, 0xd1, 0x01, 0x48, 0x07, 0xb2, 0x66, 0x6b, 0x04, 0x43, 0x69, 0x4d, 0x12, 0x24, 0x8e, 0xf6,
+	0x01, 0x62, 0x45, 0xd5, 0x16, 0xec, 0x14, 0x99, 0x10, 0xf7, 0x02, 0x50, 0x5e, 0x33, 0x89, 0xd8,
+	0x2a, 0xa1, 0xbf, 0x89, 0xba, 0x47, 0xd0, 0xf6, 0xa8, 0x4f, 0xf2, 0x3e, 0x7a, 0xd0, 0x14, 0xa9,
+	0xe9, 0x77, 0xfb, 0x0d, 0x11, 0x4e, 0x88, 0x3b, 0x82, 0x6d, 0xcd, 0xad, 0xa8, 0xff, 0x04, 0x9d,
+	0xe7, 0x88, 0xfc, 0xb1, 0xd3, 0x43, 0x68, 0xad, 0xe5, 0xa1, 0xe9, 0x9b, 0x9f, 0x2c, 0xb1, 0xd9,
+	0xaf, 0x0d, 0x6c, 0x0f, 0x14, 0xf4, 0xe0, 0x27, 0x4b, 0xd1, 0x6b, 0x5e, 0xb5, 0xa2, 0x97, 0x63,
+	0xe8, 0xdc, 0xd1, 0x90, 0x72, 0x5a, 0xa9, 0xdb, 0x13, 0x40, 0x79, 0x76, 0x5a, 0xe3, 0x47, 0xfa,
+	0x10, 0xda, 0xf7, 0x41, 0xc2, 0xf3, 0xd2, 0x1b, 0x2b, 0x63, 0x6c, 0xae, 0x8c, 0xb8, 0x4c, 0xcd,
+	0xaf, 0xd6, 0xc0, 0xe8, 0xd3, 0x84, 0x96, 0x08, 0x1f, 0x69, 0xfc, 0x11, 0xcc, 0x29, 0xba, 0x01,
+	0xd0, 0x23, 0x47, 0x3d, 0xc5, 0x2f, 0x2d, 0x96, 0x83, 0xcb, 0x09, 0x55, 0xd0, 0xfd, 0x87, 0x2e,
+	0xc1, 0xca, 0x66, 0x8a, 0x76, 0x15, 0xaf, 0xb0, 0x0f, 0x4e, 0xb7, 0x08, 0xa7, 0x6e, 0xaf, 0x01,
+	0xf4, 0x10, 0xb2, 0xea, 0xa5, 0x61, 0x3b, 0xb8, 0x9c, 0xd0, 0x02, 0xfa, 0x86, 0x33, 0x81, 0xd2,
+	0x84, 0x1c, 0x5c, 0x4e, 0xa4, 0x02, 0x57, 0x60, 0x65, 0x77, 0x98, 0x99, 0x2f, 0xcc, 0xc0, 0xe9,
+	0x16, 0x61, 0x75, 0xf4, 0xcc, 0x18, 0x5b, 0x2f, 0x0d, 0xf5, 0x0d, 0x98, 0x35, 0xe4, 0xfb, 0x7f,
+	0xfe, 0x35, 0x00, 0xe8, 0x95, 0x97, 0xa0, 0x19, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -0,0 +1,9 @@
+package blogpb
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI v3 document for the services in blog.proto,
+// written by generate.sh.
+//
+//go:embed blog.openapi.json
+var OpenAPI []byte
@@ -2,26 +2,56 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"time"
 
 	"github.com/jwfrizzell/grpc-go-course/blog/blogpb"
-
-	"google.golang.org/grpc"
+	"github.com/jwfrizzell/grpc-go-course/dial"
+	"github.com/jwfrizzell/grpc-go-course/logging"
 )
 
+// defaultServiceConfig retries the read-only calls and CreateBlog, which
+// carries a request id so a retry cannot create the post twice. Updates and
+// deletes are never retried.
+var defaultServiceConfig = &dial.ServiceConfig{
+	MethodConfig: []dial.MethodConfig{
+		{
+			Name: []dial.Name{
+				{Service: "blog.BlogService", Method: "ReadBlog"},
+				{Service: "blog.BlogService", Method: "ListBlog"},
+				{Service: "blog.BlogService", Method: "CreateBlog"},
+			},
+			Timeout:     dial.Duration(30 * time.Second),
+			RetryPolicy: dial.DefaultRetryPolicy(),
+		},
+		{
+			Name:    []dial.Name{{Service: "blog.BlogService"}},
+			Timeout: dial.Duration(10 * time.Second),
+		},
+	},
+	RetryThrottling: dial.DefaultRetryThrottling(),
+}
+
 func main() {
+	flags := dial.RegisterFlags(flag.CommandLine, dial.Defaults{
+		ServiceName:   "blog-client",
+		ServiceConfig: defaultServiceConfig,
+	})
+	flag.Parse()
+
 	fmt.Println("Staring Blog Client...")
 
 	//Creating Client
 	host := "localhost:50051"
-	cc, err := grpc.Dial(host, grpc.WithInsecure())
-	defer cc.Close()
+	conn, err := dial.Setup(host, flags)
 	if err != nil {
-		log.Fatalf("Unable to Dial Host: %s.\n", host)
-		return
+		log.Fatalf("Unable to Dial Host: %s. Error: %v\n", host, err)
 	}
+	defer conn.Close(context.Background())
+	cc := conn.Conn
 	client := blogpb.NewBlogServiceClient(cc)
 	log.Printf("Client Created...\n")
 
@@ -66,6 +96,9 @@ func createBlog(client blogpb.BlogServiceClient) {
 			Content:  "Personal Blog!",
 			Title:    "Title for my very own blog.",
 		},
+		//Reuse the id when retrying by hand so the server returns the
+		//original blog.
+		RequestId: logging.NewRequestID(),
 	})
 	if err != nil {
 		log.Fatalf("Server Response Error: %v\n", err)
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/jwfrizzell/grpc-go-course/blog/blogpb"
+	"github.com/jwfrizzell/grpc-go-course/logging"
+	"github.com/jwfrizzell/grpc-go-course/ratelimit"
+	"github.com/jwfrizzell/grpc-go-course/web"
+)
+
+// maxBodyBytes caps the size of a JSON request body.
+const maxBodyBytes = 1 << 20
+
+// forwardHeaders maps the HTTP headers passed on to the gRPC call to their
+// metadata keys.
+var forwardHeaders = map[string]string{
+	"Authorization": "authorization",
+	"X-Request-Id":  logging.RequestIDKey,
+}
+
+// The gateway passes the HTTP caller's address and verified client
+// certificate chain to the in-process server under these keys. Only that
+// server trusts them, and forwardHeaders never copies them from a request.
+const (
+	peerAddrKey  = "x-gateway-peer"
+	peerCertsKey = "x-gateway-client-cert-bin"
+)
+
+var (
+	marshaler   = &jsonpb.Marshaler{EmitDefaults: true}
+	unmarshaler = &jsonpb.Unmarshaler{}
+)
+
+// gateway serves BlogService as HTTP/JSON. It calls the gRPC server over
+// an in-process connection, so REST callers pass through the same
+// interceptors as gRPC callers.
+type gateway struct {
+	client blogpb.BlogServiceClient
+	log    *slog.Logger
+}
+
+func newGateway(client blogpb.BlogServiceClient, logger *slog.Logger) http.Handler {
+	g := &gateway{client: client, log: logger}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/blogs", g.createBlog)
+	mux.HandleFunc("GET /v1/blogs", g.listBlogs)
+	mux.HandleFunc("GET /v1/blogs/{id}", g.readBlog)
+	mux.HandleFunc("PATCH /v1/blogs/{id}", g.updateBlog)
+	mux.HandleFunc("DELETE /v1/blogs/{id}", g.deleteBlog)
+	mux.HandleFunc("GET /openapi.json", serveOpenAPI)
+	mux.HandleFunc("GET /docs", serveDocs)
+	return mux
+}
+
+// serveGateway registers srv on an in-process gRPC server built from opts
+// and serves the gateway for it on addr, over TLS when tlsCfg is set. The
+// in-process server sees the HTTP caller as its peer, so rate limits and
+// client certificates apply per caller. The returned func stops both.
+func serveGateway(addr string, srv blogpb.BlogServiceServer, opts []grpc.ServerOption, tlsCfg *tls.Config, logger *slog.Logger) (func(context.Context), error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		lis = tls.NewListener(lis, tlsCfg)
+	}
+
+	buf := bufconn.Listen(1 << 20)
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(trustGatewayPeerUnary),
+		grpc.ChainStreamInterceptor(trustGatewayPeerStream),
+	}, opts...)
+	inproc := grpc.NewServer(opts...)
+	blogpb.RegisterBlogServiceServer(inproc, srv)
+	go inproc.Serve(buf)
+
+	cc, err := grpc.Dial("passthrough:///blog-gateway",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return buf.DialContext(ctx)
+		}),
+	)
+	if err != nil {
+		inproc.Stop()
+		lis.Close()
+		return nil, err
+	}
+
+	hs := &http.Server{
+		Handler:           newGateway(blogpb.NewBlogServiceClient(cc), logger),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		logger.Info("Starting REST Gateway", "addr", lis.Addr().String())
+		if err := hs.Serve(lis); err != nil && err != http.ErrServerClosed {
+			logger.Error("REST Gateway Failure", "error", err)
+		}
+	}()
+
+	return func(ctx context.Context) {
+		hs.Shutdown(ctx)
+		cc.Close()
+		inproc.Stop()
+	}, nil
+}
+
+// outgoing returns the context for the gRPC call made on behalf of r.
+func outgoing(r *http.Request) context.Context {
+	md := metadata.MD{}
+	for h, k := range forwardHeaders {
+		if v := r.Header.Get(h); v != "" {
+			md.Set(k, v)
+		}
+	}
+	md.Set(peerAddrKey, r.RemoteAddr)
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		for _, c := range r.TLS.VerifiedChains[0] {
+			md.Append(peerCertsKey, string(c.Raw))
+		}
+	}
+	return metadata.NewOutgoingContext(r.Context(), md)
+}
+
+// gatewayPeer replaces the bufconn peer of a gateway call with the HTTP
+// caller described by its metadata, and drops those keys.
+func gatewayPeer(ctx context.Context) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+	p := &peer.Peer{}
+	if old, ok := peer.FromContext(ctx); ok {
+		*p = *old
+	}
+	if v := md.Get(peerAddrKey); len(v) > 0 {
+		ap, err := netip.ParseAddrPort(v[0])
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "gateway peer address %q: %v", v[0], err)
+		}
+		p.Addr = net.TCPAddrFromAddrPort(ap)
+	}
+	if v := md.Get(peerCertsKey); len(v) > 0 {
+		chain := make([]*x509.Certificate, len(v))
+		for i, der := range v {
+			c, err := x509.ParseCertificate([]byte(der))
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "gateway client certificate: %v", err)
+			}
+			chain[i] = c
+		}
+		p.AuthInfo = credentials.TLSInfo{
+			State: tls.ConnectionState{
+				PeerCertificates: chain[:1],
+				VerifiedChains:   [][]*x509.Certificate{chain},
+			},
+			CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.PrivacyAndIntegrity},
+		}
+	}
+	md = md.Copy()
+	md.Delete(peerAddrKey)
+	md.Delete(peerCertsKey)
+	return peer.NewContext(metadata.NewIncomingContext(ctx, md), p), nil
+}
+
+func trustGatewayPeerUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := gatewayPeer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func trustGatewayPeerStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := gatewayPeer(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &peerStream{ServerStream: ss, ctx: ctx})
+}
+
+// peerStream overrides the context of a gateway stream.
+type peerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *peerStream) Context() context.Context {
+	return s.ctx
+}
+
+type errorBody struct {
+	Code    codes.Code `json:"code"`
+	Status  string     `json:"status"`
+	Message string     `json:"message"`
+}
+
+func newErrorBody(err error) errorBody {
+	st := status.Convert(err)
+	return errorBody{Code: st.Code(), Status: st.Code().String(), Message: st.Message()}
+}
+
+// writeError writes err as a JSON error body. trailer carries the
+// Retry-After hint of a throttled call.
+func writeError(w http.ResponseWriter, err error, trailer metadata.MD) {
+	if v := trailer.Get(ratelimit.RetryAfterKey); len(v) > 0 {
+		w.Header().Set("Retry-After", v[0])
+	}
+	if a := trailer.Get("www-authenticate"); len(a) > 0 {
+		w.Header().Set("WWW-Authenticate", a[0])
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(web.HTTPStatus(status.Code(err)))
+	json.NewEncoder(w).Encode(newErrorBody(err))
+}
+
+func setRequestID(w http.ResponseWriter, header metadata.MD) {
+	if v := header.Get(logging.RequestIDKey); len(v) > 0 {
+		w.Header().Set("X-Request-Id", v[0])
+	}
+}
+
+// reply writes the result of a unary call.
+func reply(w http.ResponseWriter, resp proto.Message, err error, header, trailer metadata.MD) {
+	setRequestID(w, header)
+	if err != nil {
+		writeError(w, err, trailer)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	marshaler.Marshal(w, resp)
+}
+
+// decodeBody reads a JSON encoded msg from r. It also returns the top level
+// field names present so PATCH can tell unset fields from empty ones.
+func decodeBody(r *http.Request, msg proto.Message) (map[string]json.RawMessage, error) {
+	b, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBodyBytes))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "reading body: %v", err)
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "body must be a JSON object: %v", err)
+	}
+	if err := unmarshaler.Unmarshal(bytes.NewReader(b), msg); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid body: %v", err)
+	}
+	return fields, nil
+}
+
+func (g *gateway) createBlog(w http.ResponseWriter, r *http.Request) {
+	blog := &blogpb.Blog{}
+	if _, err := decodeBody(r, blog); err != nil {
+		writeError(w, err, nil)
+		return
+	}
+	requestID := r.Header.Get("Idempotency-Key")
+	if requestID == "" {
+		requestID = r.URL.Query().Get("request_id")
+	}
+
+	var header, trailer metadata.MD
+	resp, err := g.client.CreateBlog(outgoing(r), &blogpb.CreateBlogRequest{
+		Blog:      blog,
+		RequestId: requestID,
+	}, grpc.Header(&header), grpc.Trailer(&trailer))
+	reply(w, resp, err, header, trailer)
+}
+
+func (g *gateway) readBlog(w http.ResponseWriter, r *http.Request) {
+	var header, trailer metadata.MD
+	resp, err := g.client.ReadBlog(outgoing(r), &blogpb.ReadBlogRequest{
+		BlogId: r.PathValue("id"),
+	}, grpc.Header(&header), grpc.Trailer(&trailer))
+	reply(w, resp, err, header, trailer)
+}
+
+// updateBlog changes the fields present in the body, or those named by the
+// update_mask query parameter, in a single UpdateBlog call.
+func (g *gateway) updateBlog(w http.ResponseWriter, r *http.Request) {
+	patch := &blogpb.Blog{}
+	fields, err := decodeBody(r, patch)
+	if err != nil {
+		writeError(w, err, nil)
+		return
+	}
+	id := r.PathValue("id")
+	if patch.GetId() != "" && patch.GetId() != id {
+		writeError(w, status.Errorf(codes.InvalidArgument, "body id %q does not match path id %q", patch.GetId(), id), nil)
+		return
+	}
+	patch.Id = id
+
+	var mask []string
+	for _, v := range r.URL.Query()["update_mask"] {
+		mask = append(mask, strings.Split(v, ",")...)
+	}
+	if len(mask) == 0 {
+		for _, f := range [][]string{{"author_id", "authorId"}, {"title"}, {"content"}} {
+			if has(fields, f...) {
+				mask = append(mask, f[0])
+			}
+		}
+	}
+
+	var header, trailer metadata.MD
+	if len(mask) == 0 {
+		//Nothing to change. An empty mask would replace every field.
+		resp, err := g.client.ReadBlog(outgoing(r), &blogpb.ReadBlogRequest{BlogId: id}, grpc.Header(&header), grpc.Trailer(&trailer))
+		reply(w, resp, err, header, trailer)
+		return
+	}
+	resp, err := g.client.UpdateBlog(outgoing(r), &blogpb.UpdateBlogRequest{
+		Blog:       patch,
+		UpdateMask: mask,
+	}, grpc.Header(&header), grpc.Trailer(&trailer))
+	reply(w, resp, err, header, trailer)
+}
+
+func has(fields map[string]json.RawMessage, names ...string) bool {
+	for _, n := range names {
+		if _, ok := fields[n]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *gateway) deleteBlog(w http.ResponseWriter, r *http.Request) {
+	var header, trailer metadata.MD
+	resp, err := g.client.DeleteBlog(outgoing(r), &blogpb.DeleteBlogRequest{
+		BlogId: r.PathValue("id"),
+	}, grpc.Header(&header), grpc.Trailer(&trailer))
+	reply(w, resp, err, header, trailer)
+}
+
+// listBlogs streams ListBlog as newline delimited JSON, one
+// ListBlogResponse per line. Errors before the first blog get a normal
+// error response; later ones are written as a final {"error": ...} line.
+func (g *gateway) listBlogs(w http.ResponseWriter, r *http.Request) {
+	stream, err := g.client.ListBlog(outgoing(r), &blogpb.ListBlogRequest{
+		AuthorId: r.URL.Query().Get("author_id"),
+	})
+	if err != nil {
+		writeError(w, err, nil)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	started := false
+	start := func() {
+		header, _ := stream.Header()
+		setRequestID(w, header)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			if !started {
+				start()
+			}
+			return
+		}
+		if err != nil {
+			if !started {
+				header, _ := stream.Header()
+				setRequestID(w, header)
+				writeError(w, err, stream.Trailer())
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]errorBody{"error": newErrorBody(err)})
+			return
+		}
+		if !started {
+			start()
+		}
+		if err := marshaler.Marshal(w, resp); err != nil {
+			g.log.Warn("REST Gateway Write Failure", "error", err)
+			return
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/jwfrizzell/grpc-go-course/authz"
+)
+
+const (
+	methodCreateBlog = "/blog.BlogService/CreateBlog"
+	methodReadBlog   = "/blog.BlogService/ReadBlog"
+	methodUpdateBlog = "/blog.BlogService/UpdateBlog"
+	methodDeleteBlog = "/blog.BlogService/DeleteBlog"
+	methodListBlog   = "/blog.BlogService/ListBlog"
+)
+
+// defaultPolicy lets anyone read, authors write, and restricts changes to
+// existing posts to their author or an admin.
+var defaultPolicy = &authz.Policy{
+	Public: []string{
+		methodReadBlog,
+		methodListBlog,
+		"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+		"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+	},
+	Roles: map[string][]string{
+		"admin":  {"*"},
+		"author": {methodCreateBlog, methodUpdateBlog, methodDeleteBlog},
+	},
+	OwnerOnly:   []string{methodUpdateBlog, methodDeleteBlog},
+	OwnerBypass: []string{"admin"},
+}
+
+// blogOwners leaves the ownership of UpdateBlog and DeleteBlog to the
+// handlers, which match author_id in the same Mongo filter that changes the
+// blog so the check and the write cannot race.
+var blogOwners = map[string]authz.OwnerFunc{
+	methodUpdateBlog: nil,
+	methodDeleteBlog: nil,
+}
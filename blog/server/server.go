@@ -2,29 +2,42 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
-	"net"
+	"log/slog"
 	"os"
 	"os/signal"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson"
-
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/jwfrizzell/grpc-go-course/auth"
+	"github.com/jwfrizzell/grpc-go-course/authz"
 	"github.com/jwfrizzell/grpc-go-course/blog/blogpb"
+	"github.com/jwfrizzell/grpc-go-course/logging"
+	"github.com/jwfrizzell/grpc-go-course/serverutil"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"google.golang.org/grpc/reflection"
-
-	"google.golang.org/grpc"
 )
 
-type server struct{}
+type server struct {
+	store *store
+	log   *slog.Logger
+	//policy decides which callers may only write their own blogs. It is
+	//nil when authentication is disabled.
+	policy *authz.Policy
+}
+
+// logger returns the request scoped logger for ctx.
+func (s *server) logger(ctx context.Context) *slog.Logger {
+	return logging.FromContext(ctx, s.log)
+}
 
 type blogItem struct {
 	ID       primitive.ObjectID `bson:"_id,omitempty"`
@@ -33,49 +46,82 @@ type blogItem struct {
 	Title    string             `bson:"title"`
 }
 
-var collection *mongo.Collection
+var errInvalidOID = errors.New("inserted id is not an ObjectID")
+
+// blogFields are the fields UpdateBlog may change, and what an empty
+// update_mask stands for.
+var blogFields = []string{"author_id", "title", "content"}
 
 //Server Entry Point
 func main() {
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	flags := serverutil.RegisterFlags(flag.CommandLine, serverutil.Defaults{
+		ServiceName: "blog-server",
+		Addr:        "localhost:50051",
+	})
+	policyFile := flag.String("policy", "", "authorization policy file, empty for the built-in policy")
+	httpAddr := flag.String("http-addr", "localhost:8080", "REST/JSON gateway address, empty disables the gateway")
+	idempotencyWindow := flag.Duration("idempotency-window", 24*time.Hour, "how long CreateBlog request ids are remembered")
+	flag.Parse()
+
+	srv, err := serverutil.Setup(flags)
+	if err != nil {
+		log.Fatalf("Server Setup Error: %v\n", err)
+	}
+	logger := srv.Logger
+	fatal := func(msg string, err error) {
+		logger.Error(msg, "error", err)
+		os.Exit(1)
+	}
 
-	log.Println("Starting Mongodb...")
+	logger.Info("Starting Mongodb...")
 	client, err := mongo.NewClient(options.Client().ApplyURI("mongodb://localhost:27017"))
 	if err != nil {
-		log.Fatalf("Mongodb Client Error: %v\n", err)
-		return
+		fatal("Mongodb Client Error", err)
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
 
 	err = client.Connect(ctx)
 	if err != nil {
-		log.Fatalf("Mongodb Connection Error: %v\n", err)
-		return
+		fatal("Mongodb Connection Error", err)
 	}
-	collection = client.Database("blogdb").Collection("blog")
-	log.Println("Mongodb has been successfully started...")
-
-	log.Println("Staring Blog Servcie.")
-	lis, err := net.Listen("tcp", "localhost:50051")
-	if err != nil {
-		log.Fatalf("Server Listen Error: %v\n", err)
-		return
+	st := newStore(client.Database("blogdb"), *idempotencyWindow)
+	if err := st.ensureRequestIndex(ctx); err != nil {
+		fatal("Mongodb Index Error", err)
+	}
+	logger.Info("Mongodb has been successfully started...")
+
+	var policy *authz.Policy
+	var extra serverutil.Interceptors
+	if srv.Authn != nil {
+		policy = defaultPolicy
+		if *policyFile != "" {
+			if policy, err = authz.LoadPolicy(*policyFile); err != nil {
+				fatal("Policy Setup Error", err)
+			}
+		}
+		extra.Unary = append(extra.Unary, authz.UnaryServerInterceptor(policy, blogOwners))
+		extra.Stream = append(extra.Stream, authz.StreamServerInterceptor(policy))
 	}
 
-	opts := []grpc.ServerOption{}
-	s := grpc.NewServer(opts...)
+	s := srv.NewGRPCServer(extra)
+	blogSrv := &server{store: st, log: logger, policy: policy}
+	blogpb.RegisterBlogServiceServer(s, blogSrv)
 
-	blogpb.RegisterBlogServiceServer(s, &server{})
-	reflection.Register(s)
+	stopGateway := func(context.Context) {}
+	if *httpAddr != "" {
+		//The gateway's in-process server shares everything but the credentials.
+		stopGateway, err = serveGateway(*httpAddr, blogSrv, srv.ServerOptions(extra), srv.TLS, logger)
+		if err != nil {
+			fatal("REST Gateway Setup Error", err)
+		}
+	}
 
 	go func() {
-		log.Println("Staring Blog Server.")
-		if err := s.Serve(lis); err != nil {
-			log.Fatal("Unable to serve connections on listener.\n")
-			return
+		logger.Info("Starting Blog Server", "addr", srv.Addr().String())
+		if err := srv.Serve(); err != nil {
+			fatal("Unable to serve connections on listener", err)
 		}
-
 	}()
 
 	//Wait for Control C
@@ -84,18 +130,23 @@ func main() {
 
 	//Block until signal is received.
 	<-ch
-	log.Println("\nDisconnecting Mongodb Client")
-	client.Disconnect(ctx)
-	log.Println("Stopping Blog Server")
-	s.Stop()
-	log.Println("Closing Listener")
-	lis.Close()
+	//The connect deadline above has long passed, so shut down on a fresh one.
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer stopCancel()
+	logger.Info("Stopping REST Gateway")
+	stopGateway(stopCtx)
+	logger.Info("Disconnecting Mongodb Client")
+	client.Disconnect(stopCtx)
+	logger.Info("Stopping Blog Server")
+	srv.Stop()
+	logger.Info("Flushing Traces")
+	srv.Close(context.Background())
 
 }
 
 //Create Unary Blog Request
-func (*server) CreateBlog(ctx context.Context, req *blogpb.CreateBlogRequest) (*blogpb.CreateBlogResponse, error) {
-	log.Println("Starting CreateBlog Server Request...")
+func (s *server) CreateBlog(ctx context.Context, req *blogpb.CreateBlogRequest) (*blogpb.CreateBlogResponse, error) {
+	s.logger(ctx).Debug("Starting CreateBlog Server Request...")
 
 	blog := req.GetBlog()
 
@@ -105,42 +156,68 @@ func (*server) CreateBlog(ctx context.Context, req *blogpb.CreateBlogRequest) (*
 		Content:  blog.GetContent(),
 		Title:    blog.GetTitle(),
 	}
-
-	res, err := collection.InsertOne(context.Background(), data)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Internal Error: %v", err))
+	//Only admins may post on someone else's behalf.
+	if subject, ok := s.policy.Restricted(ctx); ok {
+		if data.AuthorID != "" && data.AuthorID != subject {
+			return nil, status.Errorf(codes.PermissionDenied, "permission denied: %s may not post as %q", subject, data.AuthorID)
+		}
+		data.AuthorID = subject
 	}
 
-	oid, ok := res.InsertedID.(primitive.ObjectID)
-	if !ok {
+	item, replayed, err := s.store.insertOnce(ctx, requestKey(ctx, req.GetRequestId()), &data)
+	if err == errInvalidOID {
 		return nil, status.Errorf(codes.Internal, "Cannot Convert OID")
 	}
+	if err == errRequestInFlight {
+		return nil, status.Errorf(codes.Aborted, "request %q is still being processed, retry later", req.GetRequestId())
+	}
+	if err == errRequestMismatch {
+		return nil, status.Errorf(codes.InvalidArgument, "request %q was already used for a different blog", req.GetRequestId())
+	}
+	if err == errRequestDeleted {
+		return nil, status.Errorf(codes.NotFound, "the blog created by request %q has since been deleted", req.GetRequestId())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Internal Error: %v", err)
+	}
+	if replayed {
+		s.logger(ctx).Info("Replayed CreateBlog request", "request_id", req.GetRequestId(), "blog_id", item.ID.Hex())
+	}
 
 	br := &blogpb.CreateBlogResponse{
 		Blog: &blogpb.Blog{
-			Id:       oid.Hex(),
-			AuthorId: data.AuthorID,
-			Content:  data.Content,
-			Title:    data.Title,
+			Id:       item.ID.Hex(),
+			AuthorId: item.AuthorID,
+			Content:  item.Content,
+			Title:    item.Title,
 		},
 	}
 	return br, nil
 }
 
-func (*server) ReadBlog(ctx context.Context, req *blogpb.ReadBlogRequest) (*blogpb.ReadBlogResponse, error) {
-	log.Println("Starting ReadBlog Server Request...")
+// requestKey scopes a client supplied request id to the caller so two
+// callers cannot replay each other's blogs.
+func requestKey(ctx context.Context, requestID string) string {
+	if requestID == "" {
+		return ""
+	}
+	if p, ok := auth.FromContext(ctx); ok {
+		return p.Subject + "/" + requestID
+	}
+	return requestID
+}
+
+func (s *server) ReadBlog(ctx context.Context, req *blogpb.ReadBlogRequest) (*blogpb.ReadBlogResponse, error) {
+	s.logger(ctx).Debug("Starting ReadBlog Server Request...")
 
 	oid, err := primitive.ObjectIDFromHex(req.GetBlogId())
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "Cannot Parse ID!")
 	}
 
-	//Create empty strucxt
-	data := &blogItem{}
-	filter := bson.M{"_id": oid}
-	sr := collection.FindOne(context.Background(), filter)
-	if err := sr.Decode(data); err != nil {
-		return nil, status.Errorf(codes.NotFound, fmt.Sprintf("Unable to find Blog ID %v", oid))
+	data, err := s.store.find(ctx, oid)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Unable to find Blog ID %v", oid)
 	}
 
 	resp := &blogpb.ReadBlogResponse{
@@ -149,8 +226,8 @@ func (*server) ReadBlog(ctx context.Context, req *blogpb.ReadBlogRequest) (*blog
 	return resp, nil
 }
 
-func (*server) UpdateBlog(ctx context.Context, req *blogpb.UpdateBlogRequest) (*blogpb.UpdateBlogResponse, error) {
-	log.Println("Starting UpdateBlog Server Request...")
+func (s *server) UpdateBlog(ctx context.Context, req *blogpb.UpdateBlogRequest) (*blogpb.UpdateBlogResponse, error) {
+	s.logger(ctx).Debug("Starting UpdateBlog Server Request...")
 
 	blog := req.GetBlog()
 	oid, err := primitive.ObjectIDFromHex(blog.GetId())
@@ -158,23 +235,37 @@ func (*server) UpdateBlog(ctx context.Context, req *blogpb.UpdateBlogRequest) (*
 		return nil, status.Error(codes.InvalidArgument, "Unable to Parse ID.")
 	}
 
-	filter := bson.M{
-		"_id": oid,
+	mask := req.GetUpdateMask()
+	if len(mask) == 0 {
+		mask = blogFields
 	}
-	data := &blogItem{}
-	sr := collection.FindOne(context.Background(), filter)
-	if err := sr.Decode(data); err != nil {
-		return nil, status.Errorf(codes.NotFound, fmt.Sprintf("Unable to find Blog ID %v", oid))
+	var fields bson.D
+	for _, name := range mask {
+		var v string
+		switch name {
+		case "author_id":
+			v = blog.GetAuthorId()
+			//Authors may edit their own blogs but not hand them to someone else.
+			if subject, ok := s.policy.Restricted(ctx); ok {
+				if v != "" && v != subject {
+					return nil, status.Errorf(codes.PermissionDenied, "permission denied: %s may not change the author to %q", subject, v)
+				}
+				continue
+			}
+		case "title":
+			v = blog.GetTitle()
+		case "content":
+			v = blog.GetContent()
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "Unknown update_mask field %q", name)
+		}
+		fields = append(fields, bson.E{Key: name, Value: v})
 	}
+	owner, _ := s.policy.OwnerScope(ctx, methodUpdateBlog)
 
-	//We update our internal struct.
-	data.AuthorID = blog.GetAuthorId()
-	data.Content = blog.GetContent()
-	data.Title = blog.GetTitle()
-
-	_, err = collection.ReplaceOne(context.Background(), filter, data)
+	data, err := s.store.update(ctx, oid, owner, fields)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "Unable to update Blog: %v", oid)
+		return nil, ownedWriteError(err, oid)
 	}
 
 	resp := &blogpb.UpdateBlogResponse{
@@ -183,24 +274,18 @@ func (*server) UpdateBlog(ctx context.Context, req *blogpb.UpdateBlogRequest) (*
 	return resp, nil
 }
 
-func (*server) DeleteBlog(ctx context.Context, req *blogpb.DeleteBlogRequest) (*blogpb.DeleteBlogResponse, error) {
-	log.Println("Starting DeleteBlog Server Request...")
+func (s *server) DeleteBlog(ctx context.Context, req *blogpb.DeleteBlogRequest) (*blogpb.DeleteBlogResponse, error) {
+	s.logger(ctx).Debug("Starting DeleteBlog Server Request...")
 
 	oid, err := primitive.ObjectIDFromHex(req.GetBlogId())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "Error Parsing ID.")
 	}
-	filter := bson.M{
-		"_id": oid,
-	}
-	dr, err := collection.DeleteOne(context.Background(), filter)
-	if err != nil {
-		return nil, status.Error(codes.Internal, fmt.Sprintf("Error Deleting Record: %s", oid))
-	}
-	if dr.DeletedCount == 0 {
-		return nil, status.Error(codes.NotFound, fmt.Sprintf("Record Not Found: %s", oid))
+	owner, _ := s.policy.OwnerScope(ctx, methodDeleteBlog)
+	if err := s.store.delete(ctx, oid, owner); err != nil {
+		return nil, ownedWriteError(err, oid)
 	}
-	log.Printf("Number or records deleted %d", dr.DeletedCount)
+	s.logger(ctx).Info("Blog deleted", "blog_id", oid.Hex())
 
 	res := &blogpb.DeleteBlogResponse{
 		BlogId: oid.Hex(),
@@ -208,30 +293,36 @@ func (*server) DeleteBlog(ctx context.Context, req *blogpb.DeleteBlogRequest) (*
 	return res, nil
 }
 
-func (*server) ListBlog(req *blogpb.ListBlogRequest, stream blogpb.BlogService_ListBlogServer) error {
-	log.Println("Starting ListBlog Server Request...")
+func (s *server) ListBlog(req *blogpb.ListBlogRequest, stream blogpb.BlogService_ListBlogServer) error {
+	s.logger(stream.Context()).Debug("Starting ListBlog Server Request...")
 
-	cur, err := collection.Find(context.TODO(), nil)
-	if err != nil {
-		return status.Error(codes.NotFound, fmt.Sprintf("Unable to get list from MongoDB. Error: %v", err))
-	}
-	defer cur.Close(context.Background())
-	for cur.Next(context.Background()) {
-		data := &blogItem{}
-		if err = cur.Decode(data); err != nil {
-			return status.Error(codes.Internal, "Unable to retrieve data from cursor.")
-		}
-		err = stream.Send(&blogpb.ListBlogResponse{
+	err := s.store.list(stream.Context(), req.GetAuthorId(), func(data *blogItem) error {
+		err := stream.Send(&blogpb.ListBlogResponse{
 			Blog: dataToBlogPB(data),
 		})
 		if err != nil {
 			return status.Error(codes.Internal, fmt.Sprintf("Could create response from cursor. Error: %v", err))
 		}
+		return nil
+	})
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
 	}
-	if err = cur.Err(); err != nil {
-		return status.Error(codes.Unknown, fmt.Sprintf("Error: %v", err))
+	return status.Error(codes.Unknown, fmt.Sprintf("Error: %v", err))
+}
+
+// ownedWriteError maps a failed owner scoped write on blog oid to a status.
+func ownedWriteError(err error, oid primitive.ObjectID) error {
+	switch {
+	case err == mongo.ErrNoDocuments:
+		return status.Errorf(codes.NotFound, "Unable to find Blog ID %v", oid)
+	case err == errNotOwner:
+		return status.Error(codes.PermissionDenied, "permission denied: only the author or an admin may change this blog")
 	}
-	return nil
+	return status.Errorf(codes.Internal, "Unable to write Blog %v: %v", oid, err)
 }
 
 func dataToBlogPB(data *blogItem) *blogpb.Blog {
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/auth"
+	"github.com/jwfrizzell/grpc-go-course/blog/blogpb"
+)
+
+var (
+	testAdmin  = &auth.Principal{Subject: "root", Roles: []string{"admin"}}
+	testAuthor = &auth.Principal{Subject: "ann", Roles: []string{"author"}}
+)
+
+func testServer(mt *mtest.T) *server {
+	return &server{
+		store:  newStore(mt.DB, time.Hour),
+		log:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		policy: defaultPolicy,
+	}
+}
+
+func asCaller(p *auth.Principal) context.Context {
+	if p == nil {
+		return context.Background()
+	}
+	return auth.NewContext(context.Background(), p)
+}
+
+// lookupString returns the string at path in the command, or "" if absent.
+func lookupString(cmd bson.Raw, path ...string) string {
+	v, err := cmd.LookupErr(path...)
+	if err != nil {
+		return ""
+	}
+	s, _ := v.StringValueOK()
+	return s
+}
+
+func TestCreateBlogAuthor(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		authorID  string
+		want      codes.Code
+		//wantAuthor is the author_id inserted.
+		wantAuthor string
+	}{
+		{name: "author defaults to caller", principal: testAuthor, wantAuthor: "ann"},
+		{name: "author as self", principal: testAuthor, authorID: "ann", wantAuthor: "ann"},
+		{name: "author as someone else", principal: testAuthor, authorID: "bob", want: codes.PermissionDenied},
+		{name: "admin on behalf", principal: testAdmin, authorID: "bob", wantAuthor: "bob"},
+		{name: "auth disabled", authorID: "bob", wantAuthor: "bob"},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	for _, tc := range tests {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			s := testServer(mt)
+			if tc.principal == nil {
+				s.policy = nil
+			}
+			mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+			res, err := s.CreateBlog(asCaller(tc.principal), &blogpb.CreateBlogRequest{
+				Blog: &blogpb.Blog{AuthorId: tc.authorID, Title: "Hello", Content: "First post"},
+			})
+			if got := status.Code(err); got != tc.want {
+				mt.Fatalf("CreateBlog code = %v (%v), want %v", got, err, tc.want)
+			}
+			events := mt.GetAllStartedEvents()
+			if tc.want != codes.OK {
+				if len(events) != 0 {
+					mt.Errorf("rejected CreateBlog sent %d commands", len(events))
+				}
+				return
+			}
+			if got := res.GetBlog().GetAuthorId(); got != tc.wantAuthor {
+				mt.Errorf("response author = %q, want %q", got, tc.wantAuthor)
+			}
+			if len(events) != 1 || events[0].CommandName != "insert" {
+				mt.Fatalf("commands = %v, want one insert", events)
+			}
+			if got := lookupString(events[0].Command, "documents", "0", "author_id"); got != tc.wantAuthor {
+				mt.Errorf("inserted author = %q, want %q", got, tc.wantAuthor)
+			}
+		})
+	}
+}
+
+func TestUpdateBlogAuthor(t *testing.T) {
+	oid := primitive.NewObjectID()
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		authorID  string
+		mask      []string
+		want      codes.Code
+		//wantSet is the author_id in $set, "" when it is not set.
+		wantSet string
+		//wantOwner is the author_id the filter is scoped to.
+		wantOwner string
+	}{
+		{name: "author keeps author", principal: testAuthor, authorID: "ann", mask: []string{"author_id", "title"}, wantOwner: "ann"},
+		{name: "author empty author", principal: testAuthor, wantOwner: "ann"},
+		{name: "author hands over", principal: testAuthor, authorID: "bob", want: codes.PermissionDenied},
+		{name: "author hands over by mask", principal: testAuthor, authorID: "bob", mask: []string{"author_id"}, want: codes.PermissionDenied},
+		{name: "author title only", principal: testAuthor, authorID: "bob", mask: []string{"title"}, wantOwner: "ann"},
+		{name: "admin hands over", principal: testAdmin, authorID: "bob", wantSet: "bob"},
+		{name: "unknown field", principal: testAdmin, mask: []string{"id"}, want: codes.InvalidArgument},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	for _, tc := range tests {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			s := testServer(mt)
+			mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{
+				{Key: "_id", Value: oid},
+				{Key: "author_id", Value: "ann"},
+				{Key: "title", Value: "Hello"},
+			}}))
+
+			_, err := s.UpdateBlog(asCaller(tc.principal), &blogpb.UpdateBlogRequest{
+				Blog:       &blogpb.Blog{Id: oid.Hex(), AuthorId: tc.authorID, Title: "Hello"},
+				UpdateMask: tc.mask,
+			})
+			if got := status.Code(err); got != tc.want {
+				mt.Fatalf("UpdateBlog code = %v (%v), want %v", got, err, tc.want)
+			}
+			events := mt.GetAllStartedEvents()
+			if tc.want != codes.OK {
+				if len(events) != 0 {
+					mt.Errorf("rejected UpdateBlog sent %d commands", len(events))
+				}
+				return
+			}
+			if len(events) != 1 || events[0].CommandName != "findAndModify" {
+				mt.Fatalf("commands = %v, want one findAndModify", events)
+			}
+			cmd := events[0].Command
+			if got := lookupString(cmd, "update", "$set", "author_id"); got != tc.wantSet {
+				mt.Errorf("$set author_id = %q, want %q", got, tc.wantSet)
+			}
+			if got := lookupString(cmd, "query", "author_id"); got != tc.wantOwner {
+				mt.Errorf("filter author_id = %q, want %q", got, tc.wantOwner)
+			}
+		})
+	}
+}
+
+func TestDeleteBlogOwner(t *testing.T) {
+	oid := primitive.NewObjectID()
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		wantOwner string
+	}{
+		{name: "author", principal: testAuthor, wantOwner: "ann"},
+		{name: "admin", principal: testAdmin},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	for _, tc := range tests {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			s := testServer(mt)
+			mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+
+			if _, err := s.DeleteBlog(asCaller(tc.principal), &blogpb.DeleteBlogRequest{BlogId: oid.Hex()}); err != nil {
+				mt.Fatalf("DeleteBlog: %v", err)
+			}
+			events := mt.GetAllStartedEvents()
+			if len(events) != 1 || events[0].CommandName != "delete" {
+				mt.Fatalf("commands = %v, want one delete", events)
+			}
+			if got := lookupString(events[0].Command, "deletes", "0", "q", "author_id"); got != tc.wantOwner {
+				mt.Errorf("filter author_id = %q, want %q", got, tc.wantOwner)
+			}
+		})
+	}
+}
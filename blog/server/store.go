@@ -0,0 +1,407 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jwfrizzell/grpc-go-course/tracing"
+)
+
+// errNotOwner means the blog exists but belongs to another author.
+var errNotOwner = errors.New("blog belongs to another author")
+
+// errRequestInFlight means another call holding the same request id has not
+// finished creating its blog yet.
+var errRequestInFlight = errors.New("request is still being processed")
+
+// errRequestMismatch means the request id was already used for a different
+// blog.
+var errRequestMismatch = errors.New("request id was used with a different body")
+
+// errRequestDeleted means the blog created for the request id has since been
+// deleted.
+var errRequestDeleted = errors.New("blog created by this request has been deleted")
+
+// claimTimeout is how long a claimed request id may go without its blog
+// being created before another call with the id takes the claim over.
+const claimTimeout = time.Minute
+
+// store wraps the blog collection and the table of recent CreateBlog
+// request ids. Every call is recorded as a span.
+type store struct {
+	collection *mongo.Collection
+	requests   *mongo.Collection
+	//window is how long a request id is remembered.
+	window time.Duration
+	tracer trace.Tracer
+}
+
+// requestItem records the blog created for a request id. A TTL index on
+// CreatedAt lets Mongo expire it once the window has passed. ClaimedAt is
+// when the current creator took the id and CompletedAt is set once its blog
+// exists, so a missing blog can be told apart as not created yet or created
+// and since deleted. BodyHash identifies the blog the id was used for.
+type requestItem struct {
+	ID          string             `bson:"_id"`
+	BlogID      primitive.ObjectID `bson:"blog_id"`
+	BodyHash    string             `bson:"body_hash,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	ClaimedAt   time.Time          `bson:"claimed_at,omitempty"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty"`
+}
+
+// claimed returns when the current claim was taken. Records written before
+// claims were tracked only have CreatedAt.
+func (ri *requestItem) claimed() time.Time {
+	if ri.ClaimedAt.IsZero() {
+		return ri.CreatedAt
+	}
+	return ri.ClaimedAt
+}
+
+// bodyHash identifies the blog a CreateBlog request asked for, after the
+// server has filled in the author.
+func bodyHash(data *blogItem) string {
+	h := sha256.New()
+	for _, f := range []string{data.AuthorID, data.Title, data.Content} {
+		fmt.Fprintf(h, "%d:%s;", len(f), f)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func newStore(db *mongo.Database, window time.Duration) *store {
+	return &store{
+		collection: db.Collection("blog"),
+		requests:   db.Collection("blog_requests"),
+		window:     window,
+		tracer:     tracing.Tracer("github.com/jwfrizzell/grpc-go-course/blog/server"),
+	}
+}
+
+// startSpan opens a client span describing a single Mongo operation on c.
+func (st *store) startSpan(ctx context.Context, c *mongo.Collection, op string) (context.Context, trace.Span) {
+	return st.tracer.Start(ctx, "mongo."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mongodb"),
+			attribute.String("db.name", c.Database().Name()),
+			attribute.String("db.mongodb.collection", c.Name()),
+			attribute.String("db.operation", op),
+		),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (st *store) insert(ctx context.Context, data *blogItem) (id primitive.ObjectID, err error) {
+	ctx, span := st.startSpan(ctx, st.collection, "insertOne")
+	defer func() { endSpan(span, err) }()
+
+	res, err := st.collection.InsertOne(ctx, data)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return primitive.NilObjectID, errInvalidOID
+	}
+	return oid, nil
+}
+
+func (st *store) find(ctx context.Context, oid primitive.ObjectID) (data *blogItem, err error) {
+	ctx, span := st.startSpan(ctx, st.collection, "findOne")
+	defer func() { endSpan(span, err) }()
+
+	data = &blogItem{}
+	if err = st.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ownedBy builds the filter matching blog oid, and only when it belongs to
+// authorID if that is set.
+func ownedBy(oid primitive.ObjectID, authorID string) bson.D {
+	filter := bson.D{{Key: "_id", Value: oid}}
+	if authorID != "" {
+		filter = append(filter, bson.E{Key: "author_id", Value: authorID})
+	}
+	return filter
+}
+
+// missing explains why a write scoped to authorID matched nothing: the blog
+// does not exist, or it belongs to someone else.
+func (st *store) missing(ctx context.Context, oid primitive.ObjectID, authorID string) error {
+	if authorID == "" {
+		return mongo.ErrNoDocuments
+	}
+	if _, err := st.find(ctx, oid); err != nil {
+		return err
+	}
+	return errNotOwner
+}
+
+// update sets fields on blog oid in a single operation, restricted to
+// authorID's blogs when it is set, and returns the updated blog.
+func (st *store) update(ctx context.Context, oid primitive.ObjectID, authorID string, fields bson.D) (data *blogItem, err error) {
+	ctx, span := st.startSpan(ctx, st.collection, "findOneAndUpdate")
+	defer func() { endSpan(span, err) }()
+
+	data = &blogItem{}
+	if len(fields) == 0 {
+		//Mongo rejects an empty $set, and there is nothing to change.
+		err = st.collection.FindOne(ctx, ownedBy(oid, authorID)).Decode(data)
+	} else {
+		err = st.collection.FindOneAndUpdate(ctx, ownedBy(oid, authorID),
+			bson.D{{Key: "$set", Value: fields}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(data)
+	}
+	if err == mongo.ErrNoDocuments {
+		return nil, st.missing(ctx, oid, authorID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// delete removes blog oid, restricted to authorID's blogs when it is set.
+func (st *store) delete(ctx context.Context, oid primitive.ObjectID, authorID string) (err error) {
+	ctx, span := st.startSpan(ctx, st.collection, "deleteOne")
+	defer func() { endSpan(span, err) }()
+
+	dr, err := st.collection.DeleteOne(ctx, ownedBy(oid, authorID))
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(attribute.Int64("db.mongodb.deleted_count", dr.DeletedCount))
+	if dr.DeletedCount == 0 {
+		return st.missing(ctx, oid, authorID)
+	}
+	return nil
+}
+
+// list calls fn for every blog in the collection, or only those by authorID
+// when it is set, stopping at the first error.
+func (st *store) list(ctx context.Context, authorID string, fn func(*blogItem) error) (err error) {
+	ctx, span := st.startSpan(ctx, st.collection, "find")
+	defer func() { endSpan(span, err) }()
+
+	filter := bson.D{}
+	if authorID != "" {
+		filter = bson.D{{Key: "author_id", Value: authorID}}
+	}
+	cur, err := st.collection.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		data := &blogItem{}
+		if err = cur.Decode(data); err != nil {
+			return err
+		}
+		if err = fn(data); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+// indexTTLConflict is the server error code for an index that already
+// exists with different options.
+const indexTTLConflict = 85
+
+// ensureRequestIndex creates the TTL index that expires request ids after
+// the window, updating its expiry if the window has changed.
+func (st *store) ensureRequestIndex(ctx context.Context) (err error) {
+	ctx, span := st.startSpan(ctx, st.requests, "createIndex")
+	defer func() { endSpan(span, err) }()
+
+	ttl := int32(st.window / time.Second)
+	_, err = st.requests.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(ttl),
+	})
+	var ce mongo.CommandError
+	if errors.As(err, &ce) && ce.Code == indexTTLConflict {
+		err = st.requests.Database().RunCommand(ctx, bson.D{
+			{Key: "collMod", Value: st.requests.Name()},
+			{Key: "index", Value: bson.D{
+				{Key: "keyPattern", Value: bson.D{{Key: "created_at", Value: 1}}},
+				{Key: "expireAfterSeconds", Value: ttl},
+			}},
+		}).Err()
+	}
+	return err
+}
+
+// claimRequest records that requestID will create blogID from a body with
+// hash. It returns the existing record instead when the id is already
+// taken within the window, or errRequestMismatch if that was for another
+// body.
+func (st *store) claimRequest(ctx context.Context, requestID, hash string, blogID primitive.ObjectID) (prev *requestItem, err error) {
+	ctx, span := st.startSpan(ctx, st.requests, "insertOne")
+	defer func() { endSpan(span, err) }()
+
+	now := time.Now()
+	for {
+		_, err = st.requests.InsertOne(ctx, &requestItem{ID: requestID, BlogID: blogID, BodyHash: hash, CreatedAt: now, ClaimedAt: now})
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+		prev = &requestItem{}
+		if err = st.requests.FindOne(ctx, bson.M{"_id": requestID}).Decode(prev); err == mongo.ErrNoDocuments {
+			//Expired between the insert and the lookup.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if now.Sub(prev.CreatedAt) < st.window {
+			if prev.BodyHash != "" && prev.BodyHash != hash {
+				return nil, errRequestMismatch
+			}
+			span.SetAttributes(attribute.Bool("blog.request_replayed", true))
+			return prev, nil
+		}
+		//Mongo only sweeps TTL indexes once a minute, so drop a stale
+		//record ourselves and try again.
+		if _, err = st.requests.DeleteOne(ctx, bson.M{"_id": requestID, "created_at": prev.CreatedAt}); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// takeOverRequest moves the abandoned claim prev over to blogID. It reports
+// false when someone else completed or took over the claim first.
+func (st *store) takeOverRequest(ctx context.Context, prev *requestItem, blogID primitive.ObjectID) (ok bool, err error) {
+	ctx, span := st.startSpan(ctx, st.requests, "updateOne")
+	defer func() { endSpan(span, err) }()
+
+	res, err := st.requests.UpdateOne(ctx,
+		bson.M{"_id": prev.ID, "blog_id": prev.BlogID, "completed_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"blog_id": blogID, "claimed_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// completeRequest records that the blog for requestID exists. It reports
+// false when the claim was taken over in the meantime.
+func (st *store) completeRequest(ctx context.Context, requestID string, blogID primitive.ObjectID) (ok bool, err error) {
+	ctx, span := st.startSpan(ctx, st.requests, "updateOne")
+	defer func() { endSpan(span, err) }()
+
+	res, err := st.requests.UpdateOne(ctx,
+		bson.M{"_id": requestID, "blog_id": blogID},
+		bson.M{"$set": bson.M{"completed_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// releaseRequest forgets requestID so a retry after a failed insert can try
+// again.
+func (st *store) releaseRequest(ctx context.Context, requestID string, blogID primitive.ObjectID) (err error) {
+	ctx, span := st.startSpan(ctx, st.requests, "deleteOne")
+	defer func() { endSpan(span, err) }()
+
+	_, err = st.requests.DeleteOne(ctx, bson.M{"_id": requestID, "blog_id": blogID})
+	return err
+}
+
+// insertOnce inserts data unless requestID was already used within the
+// window, in which case it returns the blog created the first time and
+// replayed is true. It fails with errRequestMismatch when the id was used
+// for a different blog, errRequestDeleted when its blog has since been
+// deleted and errRequestInFlight while another call is still creating it.
+// An empty requestID always inserts.
+func (st *store) insertOnce(ctx context.Context, requestID string, data *blogItem) (item *blogItem, replayed bool, err error) {
+	if requestID == "" {
+		oid, err := st.insert(ctx, data)
+		if err != nil {
+			return nil, false, err
+		}
+		data.ID = oid
+		return data, false, nil
+	}
+
+	data.ID = primitive.NewObjectID()
+	prev, err := st.claimRequest(ctx, requestID, bodyHash(data), data.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if prev != nil {
+		item, err = st.find(ctx, prev.BlogID)
+		if err == nil {
+			return item, true, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return nil, false, err
+		}
+		switch {
+		case prev.CompletedAt != nil:
+			return nil, false, errRequestDeleted
+		case time.Since(prev.claimed()) < claimTimeout:
+			return nil, false, errRequestInFlight
+		}
+		//The call holding the claim gave up before creating its blog.
+		ok, err := st.takeOverRequest(ctx, prev, data.ID)
+		if err != nil {
+			return nil, false, err
+		}
+		if !ok {
+			return nil, false, errRequestInFlight
+		}
+	}
+
+	//Use a fresh context for cleanup so a cancelled call still undoes its
+	//work.
+	cleanup := func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	}
+	if _, err = st.insert(ctx, data); err != nil {
+		ctx, cancel := cleanup()
+		defer cancel()
+		st.releaseRequest(ctx, requestID, data.ID)
+		return nil, false, err
+	}
+	ok, err := st.completeRequest(ctx, requestID, data.ID)
+	if err != nil {
+		//The blog exists, so a retry finds it through the claim.
+		return data, false, nil
+	}
+	if !ok {
+		//Our claim was taken over while we were slow; the new holder's
+		//blog wins.
+		ctx, cancel := cleanup()
+		defer cancel()
+		st.delete(ctx, data.ID, "")
+		return nil, false, errRequestInFlight
+	}
+	return data, false, nil
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoURIEnv names a MongoDB to run TestInsertOnceMongo against, e.g.
+// mongodb://localhost:27017. It creates and drops its own database.
+const mongoURIEnv = "BLOG_TEST_MONGODB_URI"
+
+func testBlog() *blogItem {
+	return &blogItem{AuthorID: "ann", Title: "Hello", Content: "First post"}
+}
+
+func requestDoc(r requestItem) bson.D {
+	b, err := bson.Marshal(r)
+	if err != nil {
+		panic(err)
+	}
+	var d bson.D
+	if err := bson.Unmarshal(b, &d); err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestInsertOnce(t *testing.T) {
+	const reqNS = "test.blog_requests"
+	const blogNS = "test.blog"
+	hash := bodyHash(testBlog())
+	prevBlog := primitive.NewObjectID()
+	now := time.Now()
+	old := now.Add(-2 * claimTimeout)
+	dup := mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 11000, Message: "duplicate key"})
+	updated := func(n int) bson.D {
+		return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+	}
+	noBlog := mtest.CreateCursorResponse(0, blogNS, mtest.FirstBatch)
+
+	tests := []struct {
+		name      string
+		responses []bson.D
+		wantErr   error
+		replayed  bool
+		//wantCmds lists the commands sent, in order.
+		wantCmds []string
+	}{
+		{
+			name:      "first call",
+			responses: []bson.D{mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse(), updated(1)},
+			wantCmds:  []string{"insert", "insert", "update"},
+		},
+		{
+			name: "replay",
+			responses: []bson.D{
+				dup,
+				mtest.CreateCursorResponse(0, reqNS, mtest.FirstBatch, requestDoc(requestItem{ID: "r", BlogID: prevBlog, BodyHash: hash, CreatedAt: now, ClaimedAt: now, CompletedAt: &now})),
+				mtest.CreateCursorResponse(0, blogNS, mtest.FirstBatch, bson.D{{Key: "_id", Value: prevBlog}, {Key: "author_id", Value: "ann"}, {Key: "title", Value: "Hello"}}),
+			},
+			replayed: true,
+			wantCmds: []string{"insert", "find", "find"},
+		},
+		{
+			name: "different body",
+			responses: []bson.D{
+				dup,
+				mtest.CreateCursorResponse(0, reqNS, mtest.FirstBatch, requestDoc(requestItem{ID: "r", BlogID: prevBlog, BodyHash: "other", CreatedAt: now, ClaimedAt: now})),
+			},
+			wantErr:  errRequestMismatch,
+			wantCmds: []string{"insert", "find"},
+		},
+		{
+			name: "completed then deleted",
+			responses: []bson.D{
+				dup,
+				mtest.CreateCursorResponse(0, reqNS, mtest.FirstBatch, requestDoc(requestItem{ID: "r", BlogID: prevBlog, BodyHash: hash, CreatedAt: old, ClaimedAt: old, CompletedAt: &old})),
+				noBlog,
+			},
+			wantErr:  errRequestDeleted,
+			wantCmds: []string{"insert", "find", "find"},
+		},
+		{
+			name: "in flight",
+			responses: []bson.D{
+				dup,
+				mtest.CreateCursorResponse(0, reqNS, mtest.FirstBatch, requestDoc(requestItem{ID: "r", BlogID: prevBlog, BodyHash: hash, CreatedAt: now, ClaimedAt: now})),
+				noBlog,
+			},
+			wantErr:  errRequestInFlight,
+			wantCmds: []string{"insert", "find", "find"},
+		},
+		{
+			name: "abandoned claim taken over",
+			responses: []bson.D{
+				dup,
+				mtest.CreateCursorResponse(0, reqNS, mtest.FirstBatch, requestDoc(requestItem{ID: "r", BlogID: prevBlog, BodyHash: hash, CreatedAt: old, ClaimedAt: old})),
+				noBlog,
+				updated(1),
+				mtest.CreateSuccessResponse(),
+				updated(1),
+			},
+			wantCmds: []string{"insert", "find", "find", "update", "insert", "update"},
+		},
+		{
+			name: "abandoned claim taken by another call",
+			responses: []bson.D{
+				dup,
+				mtest.CreateCursorResponse(0, reqNS, mtest.FirstBatch, requestDoc(requestItem{ID: "r", BlogID: prevBlog, BodyHash: hash, CreatedAt: old, ClaimedAt: old})),
+				noBlog,
+				updated(0),
+			},
+			wantErr:  errRequestInFlight,
+			wantCmds: []string{"insert", "find", "find", "update"},
+		},
+		{
+			name: "claim lost while inserting",
+			responses: []bson.D{
+				mtest.CreateSuccessResponse(),
+				mtest.CreateSuccessResponse(),
+				updated(0),
+				mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			},
+			wantErr:  errRequestInFlight,
+			wantCmds: []string{"insert", "insert", "update", "delete"},
+		},
+		{
+			name: "failed insert releases the claim",
+			responses: []bson.D{
+				mtest.CreateSuccessResponse(),
+				mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 2, Message: "bad value"}),
+				mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			},
+			wantErr:  mongo.WriteException{},
+			wantCmds: []string{"insert", "insert", "delete"},
+		},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	for _, tc := range tests {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			st := newStore(mt.DB, time.Hour)
+			mt.AddMockResponses(tc.responses...)
+
+			item, replayed, err := st.insertOnce(context.Background(), "r", testBlog())
+			switch want := tc.wantErr.(type) {
+			case nil:
+				if err != nil {
+					mt.Fatalf("insertOnce: %v", err)
+				}
+				if item == nil || item.ID.IsZero() {
+					mt.Fatalf("insertOnce returned blog %+v", item)
+				}
+			case mongo.WriteException:
+				if _, ok := err.(mongo.WriteException); !ok {
+					mt.Fatalf("insertOnce error = %v, want a write error", err)
+				}
+			default:
+				if err != want {
+					mt.Fatalf("insertOnce error = %v, want %v", err, want)
+				}
+			}
+			if replayed != tc.replayed {
+				mt.Errorf("replayed = %v, want %v", replayed, tc.replayed)
+			}
+			if tc.replayed && item.ID != prevBlog {
+				mt.Errorf("replayed blog %v, want %v", item.ID.Hex(), prevBlog.Hex())
+			}
+
+			var cmds []string
+			for _, e := range mt.GetAllStartedEvents() {
+				cmds = append(cmds, e.CommandName)
+			}
+			if fmt.Sprint(cmds) != fmt.Sprint(tc.wantCmds) {
+				mt.Errorf("commands = %v, want %v", cmds, tc.wantCmds)
+			}
+		})
+	}
+}
+
+func TestBodyHash(t *testing.T) {
+	base := testBlog()
+	same := testBlog()
+	if bodyHash(base) != bodyHash(same) {
+		t.Fatal("equal bodies hash differently")
+	}
+	for _, b := range []*blogItem{
+		{AuthorID: "bob", Title: base.Title, Content: base.Content},
+		{AuthorID: base.AuthorID, Title: "Hello!", Content: base.Content},
+		{AuthorID: base.AuthorID, Title: base.Title, Content: ""},
+		//Field boundaries must not be ambiguous.
+		{AuthorID: base.AuthorID, Title: base.Title + base.Content, Content: ""},
+	} {
+		if bodyHash(b) == bodyHash(base) {
+			t.Errorf("%+v hashes like %+v", b, base)
+		}
+	}
+}
+
+// TestInsertOnceMongo runs the idempotency scenarios against a real server.
+func TestInsertOnceMongo(t *testing.T) {
+	uri := os.Getenv(mongoURIEnv)
+	if uri == "" {
+		t.Skipf("set %s to run against MongoDB", mongoURIEnv)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+	db := client.Database(fmt.Sprintf("blogtest_%d", time.Now().UnixNano()))
+	defer db.Drop(context.Background())
+
+	st := newStore(db, time.Hour)
+	if err := st.ensureRequestIndex(ctx); err != nil {
+		t.Fatalf("ensureRequestIndex: %v", err)
+	}
+
+	first, replayed, err := st.insertOnce(ctx, "a", testBlog())
+	if err != nil || replayed {
+		t.Fatalf("first call: replayed=%v err=%v", replayed, err)
+	}
+	again, replayed, err := st.insertOnce(ctx, "a", testBlog())
+	if err != nil || !replayed || again.ID != first.ID {
+		t.Fatalf("replay: blog=%v replayed=%v err=%v, want %v", again, replayed, err, first.ID.Hex())
+	}
+
+	other := testBlog()
+	other.Title = "Something else"
+	if _, _, err := st.insertOnce(ctx, "a", other); err != errRequestMismatch {
+		t.Errorf("different body: err=%v, want %v", err, errRequestMismatch)
+	}
+
+	if err := st.delete(ctx, first.ID, ""); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, _, err := st.insertOnce(ctx, "a", testBlog()); err != errRequestDeleted {
+		t.Errorf("after delete: err=%v, want %v", err, errRequestDeleted)
+	}
+
+	hash := bodyHash(testBlog())
+	now := time.Now()
+	old := now.Add(-2 * claimTimeout)
+	if _, err := st.requests.InsertMany(ctx, []interface{}{
+		&requestItem{ID: "busy", BlogID: primitive.NewObjectID(), BodyHash: hash, CreatedAt: now, ClaimedAt: now},
+		&requestItem{ID: "abandoned", BlogID: primitive.NewObjectID(), BodyHash: hash, CreatedAt: old, ClaimedAt: old},
+	}); err != nil {
+		t.Fatalf("seeding claims: %v", err)
+	}
+	if _, _, err := st.insertOnce(ctx, "busy", testBlog()); err != errRequestInFlight {
+		t.Errorf("claim in flight: err=%v, want %v", err, errRequestInFlight)
+	}
+	taken, replayed, err := st.insertOnce(ctx, "abandoned", testBlog())
+	if err != nil || replayed {
+		t.Fatalf("abandoned claim: replayed=%v err=%v", replayed, err)
+	}
+	rec := &requestItem{}
+	if err := st.requests.FindOne(ctx, bson.M{"_id": "abandoned"}).Decode(rec); err != nil {
+		t.Fatalf("reading claim: %v", err)
+	}
+	if rec.BlogID != taken.ID || rec.CompletedAt == nil {
+		t.Errorf("taken over claim = %+v, want blog %v completed", rec, taken.ID.Hex())
+	}
+
+	a, _, err := st.insertOnce(ctx, "", testBlog())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _, err := st.insertOnce(ctx, "", testBlog())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID == b.ID {
+		t.Error("calls without a request id created the same blog")
+	}
+}
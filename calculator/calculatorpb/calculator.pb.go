@@ -24,6 +24,94 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type Operation int32
+
+const (
+	Operation_OPERATION_UNSPECIFIED Operation = 0
+	Operation_ADD                   Operation = 1
+	Operation_SUBTRACT              Operation = 2
+	Operation_MULTIPLY              Operation = 3
+	Operation_DIVIDE                Operation = 4
+	Operation_MODULO                Operation = 5
+	Operation_POWER                 Operation = 6
+	Operation_MIN                   Operation = 7
+	Operation_MAX                   Operation = 8
+	Operation_AVERAGE               Operation = 9
+)
+
+var Operation_name = map[int32]string{
+	0: "OPERATION_UNSPECIFIED",
+	1: "ADD",
+	2: "SUBTRACT",
+	3: "MULTIPLY",
+	4: "DIVIDE",
+	5: "MODULO",
+	6: "POWER",
+	7: "MIN",
+	8: "MAX",
+	9: "AVERAGE",
+}
+
+var Operation_value = map[string]int32{
+	"OPERATION_UNSPECIFIED": 0,
+	"ADD":                   1,
+	"SUBTRACT":              2,
+	"MULTIPLY":              3,
+	"DIVIDE":                4,
+	"MODULO":                5,
+	"POWER":                 6,
+	"MIN":                   7,
+	"MAX":                   8,
+	"AVERAGE":               9,
+}
+
+func (x Operation) String() string {
+	return proto.EnumName(Operation_name, int32(x))
+}
+
+func (Operation) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{0}
+}
+
+type Aggregate int32
+
+const (
+	Aggregate_AGGREGATE_UNSPECIFIED Aggregate = 0
+	Aggregate_AGGREGATE_MAX         Aggregate = 1
+	Aggregate_AGGREGATE_MIN         Aggregate = 2
+	Aggregate_AGGREGATE_MEAN        Aggregate = 3
+	Aggregate_AGGREGATE_SUM         Aggregate = 4
+	//Exponentially weighted moving average over the whole stream; it
+	//ignores the window.
+	Aggregate_AGGREGATE_EWMA Aggregate = 5
+)
+
+var Aggregate_name = map[int32]string{
+	0: "AGGREGATE_UNSPECIFIED",
+	1: "AGGREGATE_MAX",
+	2: "AGGREGATE_MIN",
+	3: "AGGREGATE_MEAN",
+	4: "AGGREGATE_SUM",
+	5: "AGGREGATE_EWMA",
+}
+
+var Aggregate_value = map[string]int32{
+	"AGGREGATE_UNSPECIFIED": 0,
+	"AGGREGATE_MAX":         1,
+	"AGGREGATE_MIN":         2,
+	"AGGREGATE_MEAN":        3,
+	"AGGREGATE_SUM":         4,
+	"AGGREGATE_EWMA":        5,
+}
+
+func (x Aggregate) String() string {
+	return proto.EnumName(Aggregate_name, int32(x))
+}
+
+func (Aggregate) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{1}
+}
+
 type Calculator struct {
 	FirstNumber          int64    `protobuf:"varint,1,opt,name=first_number,json=firstNumber,proto3" json:"first_number,omitempty"`
 	LastNumber           int64    `protobuf:"varint,2,opt,name=last_number,json=lastNumber,proto3" json:"last_number,omitempty"`
@@ -228,7 +316,10 @@ func (m *CalculatorResponse) GetResult() int64 {
 }
 
 type PrimeRequest struct {
-	Prime                *Prime   `protobuf:"bytes,1,opt,name=prime,proto3" json:"prime,omitempty"`
+	Prime *Prime `protobuf:"bytes,1,opt,name=prime,proto3" json:"prime,omitempty"`
+	//Decimal number to factor when it does not fit in an int64. Set this
+	//or prime, not both.
+	BigNumber            string   `protobuf:"bytes,2,opt,name=big_number,json=bigNumber,proto3" json:"big_number,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -266,6 +357,13 @@ func (m *PrimeRequest) GetPrime() *Prime {
 	return nil
 }
 
+func (m *PrimeRequest) GetBigNumber() string {
+	if m != nil {
+		return m.BigNumber
+	}
+	return ""
+}
+
 type FindMaxRequest struct {
 	Number               float64  `protobuf:"fixed64,1,opt,name=number,proto3" json:"number,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -345,7 +443,10 @@ func (m *SquareRootRequest) GetNumber() float64 {
 }
 
 type PrimeResponse struct {
-	Number               int64    `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	//Zero when the factor does not fit in an int64.
+	Number int64 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	//The factor in decimal, set when the request used big_number.
+	BigNumber            string   `protobuf:"bytes,2,opt,name=big_number,json=bigNumber,proto3" json:"big_number,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -383,6 +484,13 @@ func (m *PrimeResponse) GetNumber() int64 {
 	return 0
 }
 
+func (m *PrimeResponse) GetBigNumber() string {
+	if m != nil {
+		return m.BigNumber
+	}
+	return ""
+}
+
 type AverageResponse struct {
 	Number               float64  `protobuf:"fixed64,1,opt,name=number,proto3" json:"number,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -500,353 +608,3545 @@ func (m *SquareRootResponse) GetNumber() float64 {
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*Calculator)(nil), "calculator.Calculator")
-	proto.RegisterType((*Prime)(nil), "calculator.Prime")
-	proto.RegisterType((*AverageRequest)(nil), "calculator.AverageRequest")
-	proto.RegisterType((*CalculatorRequest)(nil), "calculator.CalculatorRequest")
-	proto.RegisterType((*CalculatorResponse)(nil), "calculator.CalculatorResponse")
-	proto.RegisterType((*PrimeRequest)(nil), "calculator.PrimeRequest")
-	proto.RegisterType((*FindMaxRequest)(nil), "calculator.FindMaxRequest")
-	proto.RegisterType((*SquareRootRequest)(nil), "calculator.SquareRootRequest")
-	proto.RegisterType((*PrimeResponse)(nil), "calculator.PrimeResponse")
-	proto.RegisterType((*AverageResponse)(nil), "calculator.AverageResponse")
-	proto.RegisterType((*FindMaxResponse)(nil), "calculator.FindMaxResponse")
-	proto.RegisterType((*SquareRootResponse)(nil), "calculator.SquareRootResponse")
+type Operand struct {
+	// Types that are valid to be assigned to Value:
+	//	*Operand_IntValue
+	//	*Operand_DoubleValue
+	Value                isOperand_Value `protobuf_oneof:"value"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-func init() {
-	proto.RegisterFile("calculator/calculatorpb/calculator.proto", fileDescriptor_7f42938f8c8365cf)
+func (m *Operand) Reset()         { *m = Operand{} }
+func (m *Operand) String() string { return proto.CompactTextString(m) }
+func (*Operand) ProtoMessage()    {}
+func (*Operand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{12}
 }
 
-var fileDescriptor_7f42938f8c8365cf = []byte{
-	// 405 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0x5d, 0x4f, 0xea, 0x40,
-	0x10, 0xa5, 0x97, 0xc0, 0x4d, 0x86, 0x5e, 0xee, 0x65, 0x1e, 0x08, 0x17, 0xa2, 0x68, 0x5f, 0xa8,
-	0x91, 0x20, 0xc1, 0x44, 0x9f, 0xfd, 0x88, 0x31, 0x31, 0x08, 0x81, 0xc4, 0x07, 0x5f, 0x4c, 0xa9,
-	0xab, 0x69, 0xd2, 0x76, 0xcb, 0x76, 0x4b, 0xfc, 0x45, 0xfe, 0x4e, 0xd3, 0xed, 0xd7, 0xae, 0x14,
-	0x79, 0xeb, 0xcc, 0x9c, 0x3d, 0x73, 0xe6, 0xcc, 0xa4, 0x60, 0xda, 0x96, 0x6b, 0x47, 0xae, 0xc5,
-	0x29, 0x3b, 0x2b, 0x3e, 0x83, 0x95, 0x14, 0x8c, 0x02, 0x46, 0x39, 0x45, 0x28, 0x32, 0xc6, 0x1c,
-	0xe0, 0x26, 0x8f, 0xf0, 0x18, 0xf4, 0x37, 0x87, 0x85, 0xfc, 0xc5, 0x8f, 0xbc, 0x15, 0x61, 0x1d,
-	0xed, 0x48, 0x33, 0xab, 0x8b, 0x86, 0xc8, 0x3d, 0x8a, 0x14, 0xf6, 0xa1, 0xe1, 0x5a, 0x05, 0xe2,
-	0x97, 0x40, 0x40, 0x9c, 0x4a, 0x00, 0x46, 0x1f, 0x6a, 0x73, 0xe6, 0x78, 0x04, 0xdb, 0x50, 0x57,
-	0x68, 0xd2, 0xc8, 0x30, 0xa1, 0x79, 0xb5, 0x21, 0xcc, 0x7a, 0x27, 0x0b, 0xb2, 0x8e, 0x48, 0xc8,
-	0x77, 0x22, 0x1f, 0xa0, 0x55, 0x88, 0xcb, 0xc0, 0x17, 0x20, 0xe9, 0x17, 0x0f, 0x1a, 0x93, 0xf6,
-	0x48, 0x1a, 0x52, 0x7a, 0x22, 0x4f, 0x3a, 0x04, 0x94, 0xc9, 0xc2, 0x80, 0xfa, 0xa1, 0x10, 0xc9,
-	0x48, 0x18, 0xb9, 0x3c, 0x6b, 0x9d, 0x44, 0xc6, 0x25, 0xe8, 0x62, 0x8a, 0xac, 0xeb, 0x00, 0x6a,
-	0x41, 0x1c, 0xa7, 0x0d, 0x5b, 0x72, 0xc3, 0x04, 0x98, 0xd4, 0xe3, 0xe9, 0xee, 0x1c, 0xff, 0x75,
-	0x6a, 0x7d, 0x94, 0x4f, 0xa7, 0xe5, 0xd3, 0x9d, 0x42, 0x6b, 0xb9, 0x8e, 0x2c, 0x46, 0x16, 0x94,
-	0xf2, 0x7d, 0xe0, 0x01, 0xfc, 0x49, 0xf5, 0x14, 0xc2, 0x4b, 0x3d, 0x3b, 0x81, 0xbf, 0xb9, 0xbb,
-	0xa5, 0x50, 0x4d, 0x86, 0xe6, 0x52, 0xf7, 0x40, 0x87, 0x80, 0xb2, 0xd6, 0x9f, 0xd1, 0x93, 0xcf,
-	0xaa, 0xbc, 0xb8, 0x25, 0x61, 0x1b, 0xc7, 0x26, 0x38, 0x03, 0x3d, 0x4b, 0x92, 0x65, 0xe4, 0xe1,
-	0xc1, 0x8e, 0xa5, 0x25, 0x4e, 0x74, 0x0f, 0x77, 0x95, 0x93, 0xe6, 0x46, 0x05, 0x9f, 0xa0, 0x97,
-	0x13, 0x0a, 0x73, 0x6e, 0x89, 0x4d, 0xbd, 0x80, 0x86, 0x0e, 0x77, 0xa8, 0x8f, 0x9d, 0xed, 0x1d,
-	0xa5, 0xd4, 0xff, 0x4b, 0x2a, 0x19, 0xeb, 0x58, 0xc3, 0x19, 0xfc, 0xcb, 0x79, 0x53, 0x2f, 0xb1,
-	0x2b, 0x3f, 0x51, 0xcf, 0xb7, 0xdb, 0x2b, 0xad, 0x65, 0x84, 0xa6, 0x86, 0xf7, 0xf0, 0x3b, 0x35,
-	0x5a, 0xe5, 0x51, 0x0f, 0x45, 0xe5, 0xf9, 0xb6, 0x99, 0x98, 0x67, 0xac, 0xe1, 0x14, 0xa0, 0xd8,
-	0x83, 0xea, 0xe0, 0xd6, 0x2d, 0xa9, 0x0e, 0x6e, 0xaf, 0xcf, 0xa8, 0x5c, 0x37, 0x9f, 0x75, 0xf9,
-	0x57, 0xb1, 0xaa, 0x8b, 0x1f, 0xc4, 0xf9, 0x57, 0x00, 0x00, 0x00, 0xff, 0xff, 0x26, 0xcc, 0x62,
-	0xde, 0x4c, 0x04, 0x00, 0x00,
+func (m *Operand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Operand.Unmarshal(m, b)
+}
+func (m *Operand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Operand.Marshal(b, m, deterministic)
+}
+func (m *Operand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Operand.Merge(m, src)
+}
+func (m *Operand) XXX_Size() int {
+	return xxx_messageInfo_Operand.Size(m)
+}
+func (m *Operand) XXX_DiscardUnknown() {
+	xxx_messageInfo_Operand.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_Operand proto.InternalMessageInfo
 
-// CalculatorServiceClient is the client API for CalculatorService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type CalculatorServiceClient interface {
-	//Unary
-	CalculateSum(ctx context.Context, in *CalculatorRequest, opts ...grpc.CallOption) (*CalculatorResponse, error)
-	//Streaming Request
-	CalculatePrimeDecomposition(ctx context.Context, in *PrimeRequest, opts ...grpc.CallOption) (CalculatorService_CalculatePrimeDecompositionClient, error)
-	//Client side streaming
-	CalculateAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_CalculateAverageClient, error)
-	//Bi Directional Streaming
-	FindMax(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_FindMaxClient, error)
-	//Unary Square Root
-	//Error handling
-	//This RPC will throw an exception is the number sent is less
-	//than 0.
-	//The error sent is type INVALID_ARGUMENT
-	SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error)
+type isOperand_Value interface {
+	isOperand_Value()
 }
 
-type calculatorServiceClient struct {
-	cc *grpc.ClientConn
+type Operand_IntValue struct {
+	IntValue int64 `protobuf:"varint,1,opt,name=int_value,json=intValue,proto3,oneof"`
 }
 
-func NewCalculatorServiceClient(cc *grpc.ClientConn) CalculatorServiceClient {
-	return &calculatorServiceClient{cc}
+type Operand_DoubleValue struct {
+	DoubleValue float64 `protobuf:"fixed64,2,opt,name=double_value,json=doubleValue,proto3,oneof"`
 }
 
-func (c *calculatorServiceClient) CalculateSum(ctx context.Context, in *CalculatorRequest, opts ...grpc.CallOption) (*CalculatorResponse, error) {
-	out := new(CalculatorResponse)
-	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/CalculateSum", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
+func (*Operand_IntValue) isOperand_Value() {}
 
-func (c *calculatorServiceClient) CalculatePrimeDecomposition(ctx context.Context, in *PrimeRequest, opts ...grpc.CallOption) (CalculatorService_CalculatePrimeDecompositionClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[0], "/calculator.CalculatorService/CalculatePrimeDecomposition", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &calculatorServiceCalculatePrimeDecompositionClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
-}
+func (*Operand_DoubleValue) isOperand_Value() {}
 
-type CalculatorService_CalculatePrimeDecompositionClient interface {
-	Recv() (*PrimeResponse, error)
-	grpc.ClientStream
+func (m *Operand) GetValue() isOperand_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
 }
 
-type calculatorServiceCalculatePrimeDecompositionClient struct {
-	grpc.ClientStream
+func (m *Operand) GetIntValue() int64 {
+	if x, ok := m.GetValue().(*Operand_IntValue); ok {
+		return x.IntValue
+	}
+	return 0
 }
 
-func (x *calculatorServiceCalculatePrimeDecompositionClient) Recv() (*PrimeResponse, error) {
-	m := new(PrimeResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *Operand) GetDoubleValue() float64 {
+	if x, ok := m.GetValue().(*Operand_DoubleValue); ok {
+		return x.DoubleValue
 	}
-	return m, nil
+	return 0
 }
 
-func (c *calculatorServiceClient) CalculateAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_CalculateAverageClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[1], "/calculator.CalculatorService/CalculateAverage", opts...)
-	if err != nil {
-		return nil, err
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Operand) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Operand_IntValue)(nil),
+		(*Operand_DoubleValue)(nil),
 	}
-	x := &calculatorServiceCalculateAverageClient{stream}
-	return x, nil
 }
 
-type CalculatorService_CalculateAverageClient interface {
-	Send(*AverageRequest) error
-	CloseAndRecv() (*AverageResponse, error)
-	grpc.ClientStream
+type CalculateRequest struct {
+	Operation            Operation `protobuf:"varint,1,opt,name=operation,proto3,enum=calculator.Operation" json:"operation,omitempty"`
+	Left                 *Operand  `protobuf:"bytes,2,opt,name=left,proto3" json:"left,omitempty"`
+	Right                *Operand  `protobuf:"bytes,3,opt,name=right,proto3" json:"right,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
-type calculatorServiceCalculateAverageClient struct {
-	grpc.ClientStream
+func (m *CalculateRequest) Reset()         { *m = CalculateRequest{} }
+func (m *CalculateRequest) String() string { return proto.CompactTextString(m) }
+func (*CalculateRequest) ProtoMessage()    {}
+func (*CalculateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{13}
 }
 
-func (x *calculatorServiceCalculateAverageClient) Send(m *AverageRequest) error {
-	return x.ClientStream.SendMsg(m)
+func (m *CalculateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CalculateRequest.Unmarshal(m, b)
+}
+func (m *CalculateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CalculateRequest.Marshal(b, m, deterministic)
+}
+func (m *CalculateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CalculateRequest.Merge(m, src)
+}
+func (m *CalculateRequest) XXX_Size() int {
+	return xxx_messageInfo_CalculateRequest.Size(m)
+}
+func (m *CalculateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CalculateRequest.DiscardUnknown(m)
 }
 
-func (x *calculatorServiceCalculateAverageClient) CloseAndRecv() (*AverageResponse, error) {
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	m := new(AverageResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+var xxx_messageInfo_CalculateRequest proto.InternalMessageInfo
+
+func (m *CalculateRequest) GetOperation() Operation {
+	if m != nil {
+		return m.Operation
 	}
-	return m, nil
+	return Operation_OPERATION_UNSPECIFIED
 }
 
-func (c *calculatorServiceClient) FindMax(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_FindMaxClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[2], "/calculator.CalculatorService/FindMax", opts...)
-	if err != nil {
-		return nil, err
+func (m *CalculateRequest) GetLeft() *Operand {
+	if m != nil {
+		return m.Left
 	}
-	x := &calculatorServiceFindMaxClient{stream}
-	return x, nil
+	return nil
 }
 
-type CalculatorService_FindMaxClient interface {
-	Send(*FindMaxRequest) error
-	Recv() (*FindMaxResponse, error)
-	grpc.ClientStream
+func (m *CalculateRequest) GetRight() *Operand {
+	if m != nil {
+		return m.Right
+	}
+	return nil
 }
 
-type calculatorServiceFindMaxClient struct {
-	grpc.ClientStream
+type CalculateResponse struct {
+	Result               *Operand `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (x *calculatorServiceFindMaxClient) Send(m *FindMaxRequest) error {
-	return x.ClientStream.SendMsg(m)
+func (m *CalculateResponse) Reset()         { *m = CalculateResponse{} }
+func (m *CalculateResponse) String() string { return proto.CompactTextString(m) }
+func (*CalculateResponse) ProtoMessage()    {}
+func (*CalculateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{14}
 }
 
-func (x *calculatorServiceFindMaxClient) Recv() (*FindMaxResponse, error) {
-	m := new(FindMaxResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+func (m *CalculateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CalculateResponse.Unmarshal(m, b)
+}
+func (m *CalculateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CalculateResponse.Marshal(b, m, deterministic)
+}
+func (m *CalculateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CalculateResponse.Merge(m, src)
+}
+func (m *CalculateResponse) XXX_Size() int {
+	return xxx_messageInfo_CalculateResponse.Size(m)
+}
+func (m *CalculateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CalculateResponse.DiscardUnknown(m)
 }
 
-func (c *calculatorServiceClient) SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error) {
-	out := new(SquareRootResponse)
-	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/SquareRoot", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_CalculateResponse proto.InternalMessageInfo
+
+func (m *CalculateResponse) GetResult() *Operand {
+	if m != nil {
+		return m.Result
 	}
-	return out, nil
+	return nil
 }
 
-// CalculatorServiceServer is the server API for CalculatorService service.
-type CalculatorServiceServer interface {
-	//Unary
-	CalculateSum(context.Context, *CalculatorRequest) (*CalculatorResponse, error)
-	//Streaming Request
-	CalculatePrimeDecomposition(*PrimeRequest, CalculatorService_CalculatePrimeDecompositionServer) error
-	//Client side streaming
-	CalculateAverage(CalculatorService_CalculateAverageServer) error
-	//Bi Directional Streaming
-	FindMax(CalculatorService_FindMaxServer) error
-	//Unary Square Root
-	//Error handling
-	//This RPC will throw an exception is the number sent is less
-	//than 0.
-	//The error sent is type INVALID_ARGUMENT
-	SquareRoot(context.Context, *SquareRootRequest) (*SquareRootResponse, error)
+type CalculateBigRequest struct {
+	Operation Operation `protobuf:"varint,1,opt,name=operation,proto3,enum=calculator.Operation" json:"operation,omitempty"`
+	//Decimal strings such as "-12", "3.25" or "1e40".
+	Operands []string `protobuf:"bytes,2,rep,name=operands,proto3" json:"operands,omitempty"`
+	//Digits after the decimal point for results that are not integers.
+	//Zero means 20.
+	Scale                uint32   `protobuf:"varint,3,opt,name=scale,proto3" json:"scale,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-// UnimplementedCalculatorServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedCalculatorServiceServer struct {
+func (m *CalculateBigRequest) Reset()         { *m = CalculateBigRequest{} }
+func (m *CalculateBigRequest) String() string { return proto.CompactTextString(m) }
+func (*CalculateBigRequest) ProtoMessage()    {}
+func (*CalculateBigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{15}
 }
 
-func (*UnimplementedCalculatorServiceServer) CalculateSum(ctx context.Context, req *CalculatorRequest) (*CalculatorResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CalculateSum not implemented")
+func (m *CalculateBigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CalculateBigRequest.Unmarshal(m, b)
 }
-func (*UnimplementedCalculatorServiceServer) CalculatePrimeDecomposition(req *PrimeRequest, srv CalculatorService_CalculatePrimeDecompositionServer) error {
-	return status.Errorf(codes.Unimplemented, "method CalculatePrimeDecomposition not implemented")
+func (m *CalculateBigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CalculateBigRequest.Marshal(b, m, deterministic)
 }
-func (*UnimplementedCalculatorServiceServer) CalculateAverage(srv CalculatorService_CalculateAverageServer) error {
-	return status.Errorf(codes.Unimplemented, "method CalculateAverage not implemented")
+func (m *CalculateBigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CalculateBigRequest.Merge(m, src)
 }
-func (*UnimplementedCalculatorServiceServer) FindMax(srv CalculatorService_FindMaxServer) error {
-	return status.Errorf(codes.Unimplemented, "method FindMax not implemented")
+func (m *CalculateBigRequest) XXX_Size() int {
+	return xxx_messageInfo_CalculateBigRequest.Size(m)
 }
-func (*UnimplementedCalculatorServiceServer) SquareRoot(ctx context.Context, req *SquareRootRequest) (*SquareRootResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SquareRoot not implemented")
+func (m *CalculateBigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CalculateBigRequest.DiscardUnknown(m)
 }
 
-func RegisterCalculatorServiceServer(s *grpc.Server, srv CalculatorServiceServer) {
-	s.RegisterService(&_CalculatorService_serviceDesc, srv)
-}
+var xxx_messageInfo_CalculateBigRequest proto.InternalMessageInfo
 
-func _CalculatorService_CalculateSum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CalculatorRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(CalculatorServiceServer).CalculateSum(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/calculator.CalculatorService/CalculateSum",
+func (m *CalculateBigRequest) GetOperation() Operation {
+	if m != nil {
+		return m.Operation
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CalculatorServiceServer).CalculateSum(ctx, req.(*CalculatorRequest))
+	return Operation_OPERATION_UNSPECIFIED
+}
+
+func (m *CalculateBigRequest) GetOperands() []string {
+	if m != nil {
+		return m.Operands
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _CalculatorService_CalculatePrimeDecomposition_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(PrimeRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func (m *CalculateBigRequest) GetScale() uint32 {
+	if m != nil {
+		return m.Scale
 	}
-	return srv.(CalculatorServiceServer).CalculatePrimeDecomposition(m, &calculatorServiceCalculatePrimeDecompositionServer{stream})
+	return 0
 }
 
-type CalculatorService_CalculatePrimeDecompositionServer interface {
-	Send(*PrimeResponse) error
-	grpc.ServerStream
+type CalculateBigResponse struct {
+	Result string `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	//False when result was rounded to scale digits.
+	Exact                bool     `protobuf:"varint,2,opt,name=exact,proto3" json:"exact,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type calculatorServiceCalculatePrimeDecompositionServer struct {
-	grpc.ServerStream
+func (m *CalculateBigResponse) Reset()         { *m = CalculateBigResponse{} }
+func (m *CalculateBigResponse) String() string { return proto.CompactTextString(m) }
+func (*CalculateBigResponse) ProtoMessage()    {}
+func (*CalculateBigResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{16}
+}
+
+func (m *CalculateBigResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CalculateBigResponse.Unmarshal(m, b)
+}
+func (m *CalculateBigResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CalculateBigResponse.Marshal(b, m, deterministic)
+}
+func (m *CalculateBigResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CalculateBigResponse.Merge(m, src)
+}
+func (m *CalculateBigResponse) XXX_Size() int {
+	return xxx_messageInfo_CalculateBigResponse.Size(m)
+}
+func (m *CalculateBigResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CalculateBigResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CalculateBigResponse proto.InternalMessageInfo
+
+func (m *CalculateBigResponse) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+func (m *CalculateBigResponse) GetExact() bool {
+	if m != nil {
+		return m.Exact
+	}
+	return false
+}
+
+type IsPrimeRequest struct {
+	Number               uint64   `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IsPrimeRequest) Reset()         { *m = IsPrimeRequest{} }
+func (m *IsPrimeRequest) String() string { return proto.CompactTextString(m) }
+func (*IsPrimeRequest) ProtoMessage()    {}
+func (*IsPrimeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{17}
+}
+
+func (m *IsPrimeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IsPrimeRequest.Unmarshal(m, b)
+}
+func (m *IsPrimeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IsPrimeRequest.Marshal(b, m, deterministic)
+}
+func (m *IsPrimeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IsPrimeRequest.Merge(m, src)
+}
+func (m *IsPrimeRequest) XXX_Size() int {
+	return xxx_messageInfo_IsPrimeRequest.Size(m)
+}
+func (m *IsPrimeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IsPrimeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IsPrimeRequest proto.InternalMessageInfo
+
+func (m *IsPrimeRequest) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+type IsPrimeResponse struct {
+	IsPrime              bool     `protobuf:"varint,1,opt,name=is_prime,json=isPrime,proto3" json:"is_prime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IsPrimeResponse) Reset()         { *m = IsPrimeResponse{} }
+func (m *IsPrimeResponse) String() string { return proto.CompactTextString(m) }
+func (*IsPrimeResponse) ProtoMessage()    {}
+func (*IsPrimeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{18}
+}
+
+func (m *IsPrimeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IsPrimeResponse.Unmarshal(m, b)
+}
+func (m *IsPrimeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IsPrimeResponse.Marshal(b, m, deterministic)
+}
+func (m *IsPrimeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IsPrimeResponse.Merge(m, src)
+}
+func (m *IsPrimeResponse) XXX_Size() int {
+	return xxx_messageInfo_IsPrimeResponse.Size(m)
+}
+func (m *IsPrimeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_IsPrimeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IsPrimeResponse proto.InternalMessageInfo
+
+func (m *IsPrimeResponse) GetIsPrime() bool {
+	if m != nil {
+		return m.IsPrime
+	}
+	return false
+}
+
+type NextPrimeRequest struct {
+	Number               uint64   `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NextPrimeRequest) Reset()         { *m = NextPrimeRequest{} }
+func (m *NextPrimeRequest) String() string { return proto.CompactTextString(m) }
+func (*NextPrimeRequest) ProtoMessage()    {}
+func (*NextPrimeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{19}
+}
+
+func (m *NextPrimeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NextPrimeRequest.Unmarshal(m, b)
+}
+func (m *NextPrimeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NextPrimeRequest.Marshal(b, m, deterministic)
+}
+func (m *NextPrimeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NextPrimeRequest.Merge(m, src)
+}
+func (m *NextPrimeRequest) XXX_Size() int {
+	return xxx_messageInfo_NextPrimeRequest.Size(m)
+}
+func (m *NextPrimeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NextPrimeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NextPrimeRequest proto.InternalMessageInfo
+
+func (m *NextPrimeRequest) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+type NextPrimeResponse struct {
+	Prime                uint64   `protobuf:"varint,1,opt,name=prime,proto3" json:"prime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NextPrimeResponse) Reset()         { *m = NextPrimeResponse{} }
+func (m *NextPrimeResponse) String() string { return proto.CompactTextString(m) }
+func (*NextPrimeResponse) ProtoMessage()    {}
+func (*NextPrimeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{20}
+}
+
+func (m *NextPrimeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NextPrimeResponse.Unmarshal(m, b)
+}
+func (m *NextPrimeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NextPrimeResponse.Marshal(b, m, deterministic)
+}
+func (m *NextPrimeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NextPrimeResponse.Merge(m, src)
+}
+func (m *NextPrimeResponse) XXX_Size() int {
+	return xxx_messageInfo_NextPrimeResponse.Size(m)
+}
+func (m *NextPrimeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NextPrimeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NextPrimeResponse proto.InternalMessageInfo
+
+func (m *NextPrimeResponse) GetPrime() uint64 {
+	if m != nil {
+		return m.Prime
+	}
+	return 0
+}
+
+type GeneratePrimesRequest struct {
+	//Inclusive bounds.
+	From                 uint64   `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   uint64   `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GeneratePrimesRequest) Reset()         { *m = GeneratePrimesRequest{} }
+func (m *GeneratePrimesRequest) String() string { return proto.CompactTextString(m) }
+func (*GeneratePrimesRequest) ProtoMessage()    {}
+func (*GeneratePrimesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{21}
+}
+
+func (m *GeneratePrimesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GeneratePrimesRequest.Unmarshal(m, b)
+}
+func (m *GeneratePrimesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GeneratePrimesRequest.Marshal(b, m, deterministic)
+}
+func (m *GeneratePrimesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GeneratePrimesRequest.Merge(m, src)
+}
+func (m *GeneratePrimesRequest) XXX_Size() int {
+	return xxx_messageInfo_GeneratePrimesRequest.Size(m)
+}
+func (m *GeneratePrimesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GeneratePrimesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GeneratePrimesRequest proto.InternalMessageInfo
+
+func (m *GeneratePrimesRequest) GetFrom() uint64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *GeneratePrimesRequest) GetTo() uint64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+type GeneratePrimesResponse struct {
+	//The next primes of the range in ascending order.
+	Primes               []uint64 `protobuf:"varint,1,rep,packed,name=primes,proto3" json:"primes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GeneratePrimesResponse) Reset()         { *m = GeneratePrimesResponse{} }
+func (m *GeneratePrimesResponse) String() string { return proto.CompactTextString(m) }
+func (*GeneratePrimesResponse) ProtoMessage()    {}
+func (*GeneratePrimesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{22}
+}
+
+func (m *GeneratePrimesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GeneratePrimesResponse.Unmarshal(m, b)
+}
+func (m *GeneratePrimesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GeneratePrimesResponse.Marshal(b, m, deterministic)
+}
+func (m *GeneratePrimesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GeneratePrimesResponse.Merge(m, src)
+}
+func (m *GeneratePrimesResponse) XXX_Size() int {
+	return xxx_messageInfo_GeneratePrimesResponse.Size(m)
+}
+func (m *GeneratePrimesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GeneratePrimesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GeneratePrimesResponse proto.InternalMessageInfo
+
+func (m *GeneratePrimesResponse) GetPrimes() []uint64 {
+	if m != nil {
+		return m.Primes
+	}
+	return nil
+}
+
+type StatisticsRequest struct {
+	Numbers []float64 `protobuf:"fixed64,1,rep,packed,name=numbers,proto3" json:"numbers,omitempty"`
+	//Percentiles to report, each between 0 and 100. Only read from the
+	//first message of the stream.
+	Percentiles          []float64 `protobuf:"fixed64,2,rep,packed,name=percentiles,proto3" json:"percentiles,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *StatisticsRequest) Reset()         { *m = StatisticsRequest{} }
+func (m *StatisticsRequest) String() string { return proto.CompactTextString(m) }
+func (*StatisticsRequest) ProtoMessage()    {}
+func (*StatisticsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{23}
+}
+
+func (m *StatisticsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatisticsRequest.Unmarshal(m, b)
+}
+func (m *StatisticsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatisticsRequest.Marshal(b, m, deterministic)
+}
+func (m *StatisticsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatisticsRequest.Merge(m, src)
+}
+func (m *StatisticsRequest) XXX_Size() int {
+	return xxx_messageInfo_StatisticsRequest.Size(m)
+}
+func (m *StatisticsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatisticsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatisticsRequest proto.InternalMessageInfo
+
+func (m *StatisticsRequest) GetNumbers() []float64 {
+	if m != nil {
+		return m.Numbers
+	}
+	return nil
+}
+
+func (m *StatisticsRequest) GetPercentiles() []float64 {
+	if m != nil {
+		return m.Percentiles
+	}
+	return nil
+}
+
+type Percentile struct {
+	Percentile           float64  `protobuf:"fixed64,1,opt,name=percentile,proto3" json:"percentile,omitempty"`
+	Value                float64  `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Percentile) Reset()         { *m = Percentile{} }
+func (m *Percentile) String() string { return proto.CompactTextString(m) }
+func (*Percentile) ProtoMessage()    {}
+func (*Percentile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{24}
+}
+
+func (m *Percentile) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Percentile.Unmarshal(m, b)
+}
+func (m *Percentile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Percentile.Marshal(b, m, deterministic)
+}
+func (m *Percentile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Percentile.Merge(m, src)
+}
+func (m *Percentile) XXX_Size() int {
+	return xxx_messageInfo_Percentile.Size(m)
+}
+func (m *Percentile) XXX_DiscardUnknown() {
+	xxx_messageInfo_Percentile.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Percentile proto.InternalMessageInfo
+
+func (m *Percentile) GetPercentile() float64 {
+	if m != nil {
+		return m.Percentile
+	}
+	return 0
+}
+
+func (m *Percentile) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type StatisticsResponse struct {
+	Count int64   `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Sum   float64 `protobuf:"fixed64,2,opt,name=sum,proto3" json:"sum,omitempty"`
+	Mean  float64 `protobuf:"fixed64,3,opt,name=mean,proto3" json:"mean,omitempty"`
+	//Population variance and standard deviation.
+	Variance float64 `protobuf:"fixed64,4,opt,name=variance,proto3" json:"variance,omitempty"`
+	Stddev   float64 `protobuf:"fixed64,5,opt,name=stddev,proto3" json:"stddev,omitempty"`
+	//Sample (n-1) variance and standard deviation, zero for one number.
+	SampleVariance float64       `protobuf:"fixed64,6,opt,name=sample_variance,json=sampleVariance,proto3" json:"sample_variance,omitempty"`
+	SampleStddev   float64       `protobuf:"fixed64,7,opt,name=sample_stddev,json=sampleStddev,proto3" json:"sample_stddev,omitempty"`
+	Min            float64       `protobuf:"fixed64,8,opt,name=min,proto3" json:"min,omitempty"`
+	Max            float64       `protobuf:"fixed64,9,opt,name=max,proto3" json:"max,omitempty"`
+	Median         float64       `protobuf:"fixed64,10,opt,name=median,proto3" json:"median,omitempty"`
+	Percentiles    []*Percentile `protobuf:"bytes,11,rep,name=percentiles,proto3" json:"percentiles,omitempty"`
+	//True when median and percentiles are t-digest estimates rather than
+	//exact, which happens on long streams.
+	Approximate          bool     `protobuf:"varint,12,opt,name=approximate,proto3" json:"approximate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatisticsResponse) Reset()         { *m = StatisticsResponse{} }
+func (m *StatisticsResponse) String() string { return proto.CompactTextString(m) }
+func (*StatisticsResponse) ProtoMessage()    {}
+func (*StatisticsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{25}
+}
+
+func (m *StatisticsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatisticsResponse.Unmarshal(m, b)
+}
+func (m *StatisticsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatisticsResponse.Marshal(b, m, deterministic)
+}
+func (m *StatisticsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatisticsResponse.Merge(m, src)
+}
+func (m *StatisticsResponse) XXX_Size() int {
+	return xxx_messageInfo_StatisticsResponse.Size(m)
+}
+func (m *StatisticsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatisticsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatisticsResponse proto.InternalMessageInfo
+
+func (m *StatisticsResponse) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *StatisticsResponse) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *StatisticsResponse) GetMean() float64 {
+	if m != nil {
+		return m.Mean
+	}
+	return 0
+}
+
+func (m *StatisticsResponse) GetVariance() float64 {
+	if m != nil {
+		return m.Variance
+	}
+	return 0
+}
+
+func (m *StatisticsResponse) GetStddev() float64 {
+	if m != nil {
+		return m.Stddev
+	}
+	return 0
+}
+
+func (m *StatisticsResponse) GetSampleVariance() float64 {
+	if m != nil {
+		return m.SampleVariance
+	}
+	return 0
+}
+
+func (m *StatisticsResponse) GetSampleStddev() float64 {
+	if m != nil {
+		return m.SampleStddev
+	}
+	return 0
+}
+
+func (m *StatisticsResponse) GetMin() float64 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+func (m *StatisticsResponse) GetMax() float64 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+func (m *StatisticsResponse) GetMedian() float64 {
+	if m != nil {
+		return m.Median
+	}
+	return 0
+}
+
+func (m *StatisticsResponse) GetPercentiles() []*Percentile {
+	if m != nil {
+		return m.Percentiles
+	}
+	return nil
+}
+
+func (m *StatisticsResponse) GetApproximate() bool {
+	if m != nil {
+		return m.Approximate
+	}
+	return false
+}
+
+type RunningStatsConfig struct {
+	Aggregates []Aggregate `protobuf:"varint,1,rep,packed,name=aggregates,proto3,enum=calculator.Aggregate" json:"aggregates,omitempty"`
+	//Aggregate over the last window_size numbers, or over the numbers of
+	//the last window_ms milliseconds. Set at most one; with neither the
+	//window is the whole stream.
+	WindowSize uint32 `protobuf:"varint,2,opt,name=window_size,json=windowSize,proto3" json:"window_size,omitempty"`
+	WindowMs   uint64 `protobuf:"varint,3,opt,name=window_ms,json=windowMs,proto3" json:"window_ms,omitempty"`
+	//EWMA smoothing factor in (0, 1]. Zero means 0.5.
+	EwmaAlpha float64 `protobuf:"fixed64,4,opt,name=ewma_alpha,json=ewmaAlpha,proto3" json:"ewma_alpha,omitempty"`
+	//Emit every tick_ms milliseconds instead of after every number.
+	TickMs               uint64   `protobuf:"varint,5,opt,name=tick_ms,json=tickMs,proto3" json:"tick_ms,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RunningStatsConfig) Reset()         { *m = RunningStatsConfig{} }
+func (m *RunningStatsConfig) String() string { return proto.CompactTextString(m) }
+func (*RunningStatsConfig) ProtoMessage()    {}
+func (*RunningStatsConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{26}
+}
+
+func (m *RunningStatsConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RunningStatsConfig.Unmarshal(m, b)
+}
+func (m *RunningStatsConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RunningStatsConfig.Marshal(b, m, deterministic)
+}
+func (m *RunningStatsConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RunningStatsConfig.Merge(m, src)
+}
+func (m *RunningStatsConfig) XXX_Size() int {
+	return xxx_messageInfo_RunningStatsConfig.Size(m)
+}
+func (m *RunningStatsConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_RunningStatsConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RunningStatsConfig proto.InternalMessageInfo
+
+func (m *RunningStatsConfig) GetAggregates() []Aggregate {
+	if m != nil {
+		return m.Aggregates
+	}
+	return nil
+}
+
+func (m *RunningStatsConfig) GetWindowSize() uint32 {
+	if m != nil {
+		return m.WindowSize
+	}
+	return 0
+}
+
+func (m *RunningStatsConfig) GetWindowMs() uint64 {
+	if m != nil {
+		return m.WindowMs
+	}
+	return 0
+}
+
+func (m *RunningStatsConfig) GetEwmaAlpha() float64 {
+	if m != nil {
+		return m.EwmaAlpha
+	}
+	return 0
+}
+
+func (m *RunningStatsConfig) GetTickMs() uint64 {
+	if m != nil {
+		return m.TickMs
+	}
+	return 0
+}
+
+type RunningStatsRequest struct {
+	//The first message must be a config; every later one a number.
+	//
+	// Types that are valid to be assigned to Item:
+	//	*RunningStatsRequest_Config
+	//	*RunningStatsRequest_Number
+	Item                 isRunningStatsRequest_Item `protobuf_oneof:"item"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *RunningStatsRequest) Reset()         { *m = RunningStatsRequest{} }
+func (m *RunningStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*RunningStatsRequest) ProtoMessage()    {}
+func (*RunningStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{27}
+}
+
+func (m *RunningStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RunningStatsRequest.Unmarshal(m, b)
+}
+func (m *RunningStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RunningStatsRequest.Marshal(b, m, deterministic)
+}
+func (m *RunningStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RunningStatsRequest.Merge(m, src)
+}
+func (m *RunningStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_RunningStatsRequest.Size(m)
+}
+func (m *RunningStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RunningStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RunningStatsRequest proto.InternalMessageInfo
+
+type isRunningStatsRequest_Item interface {
+	isRunningStatsRequest_Item()
+}
+
+type RunningStatsRequest_Config struct {
+	Config *RunningStatsConfig `protobuf:"bytes,1,opt,name=config,proto3,oneof"`
+}
+
+type RunningStatsRequest_Number struct {
+	Number float64 `protobuf:"fixed64,2,opt,name=number,proto3,oneof"`
+}
+
+func (*RunningStatsRequest_Config) isRunningStatsRequest_Item() {}
+
+func (*RunningStatsRequest_Number) isRunningStatsRequest_Item() {}
+
+func (m *RunningStatsRequest) GetItem() isRunningStatsRequest_Item {
+	if m != nil {
+		return m.Item
+	}
+	return nil
+}
+
+func (m *RunningStatsRequest) GetConfig() *RunningStatsConfig {
+	if x, ok := m.GetItem().(*RunningStatsRequest_Config); ok {
+		return x.Config
+	}
+	return nil
+}
+
+func (m *RunningStatsRequest) GetNumber() float64 {
+	if x, ok := m.GetItem().(*RunningStatsRequest_Number); ok {
+		return x.Number
+	}
+	return 0
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*RunningStatsRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*RunningStatsRequest_Config)(nil),
+		(*RunningStatsRequest_Number)(nil),
+	}
+}
+
+type AggregateValue struct {
+	Aggregate            Aggregate `protobuf:"varint,1,opt,name=aggregate,proto3,enum=calculator.Aggregate" json:"aggregate,omitempty"`
+	Value                float64   `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *AggregateValue) Reset()         { *m = AggregateValue{} }
+func (m *AggregateValue) String() string { return proto.CompactTextString(m) }
+func (*AggregateValue) ProtoMessage()    {}
+func (*AggregateValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{28}
+}
+
+func (m *AggregateValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AggregateValue.Unmarshal(m, b)
+}
+func (m *AggregateValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AggregateValue.Marshal(b, m, deterministic)
+}
+func (m *AggregateValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AggregateValue.Merge(m, src)
+}
+func (m *AggregateValue) XXX_Size() int {
+	return xxx_messageInfo_AggregateValue.Size(m)
+}
+func (m *AggregateValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_AggregateValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AggregateValue proto.InternalMessageInfo
+
+func (m *AggregateValue) GetAggregate() Aggregate {
+	if m != nil {
+		return m.Aggregate
+	}
+	return Aggregate_AGGREGATE_UNSPECIFIED
+}
+
+func (m *AggregateValue) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type RunningStatsResponse struct {
+	//Numbers in the window. When zero, values is empty.
+	Count uint64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	//One value per configured aggregate, in the configured order.
+	Values               []*AggregateValue `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *RunningStatsResponse) Reset()         { *m = RunningStatsResponse{} }
+func (m *RunningStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*RunningStatsResponse) ProtoMessage()    {}
+func (*RunningStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{29}
+}
+
+func (m *RunningStatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RunningStatsResponse.Unmarshal(m, b)
+}
+func (m *RunningStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RunningStatsResponse.Marshal(b, m, deterministic)
+}
+func (m *RunningStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RunningStatsResponse.Merge(m, src)
+}
+func (m *RunningStatsResponse) XXX_Size() int {
+	return xxx_messageInfo_RunningStatsResponse.Size(m)
+}
+func (m *RunningStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RunningStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RunningStatsResponse proto.InternalMessageInfo
+
+func (m *RunningStatsResponse) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *RunningStatsResponse) GetValues() []*AggregateValue {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type Vector struct {
+	Values               []float64 `protobuf:"fixed64,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Vector) Reset()         { *m = Vector{} }
+func (m *Vector) String() string { return proto.CompactTextString(m) }
+func (*Vector) ProtoMessage()    {}
+func (*Vector) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{30}
+}
+
+func (m *Vector) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Vector.Unmarshal(m, b)
+}
+func (m *Vector) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Vector.Marshal(b, m, deterministic)
+}
+func (m *Vector) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Vector.Merge(m, src)
+}
+func (m *Vector) XXX_Size() int {
+	return xxx_messageInfo_Vector.Size(m)
+}
+func (m *Vector) XXX_DiscardUnknown() {
+	xxx_messageInfo_Vector.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Vector proto.InternalMessageInfo
+
+func (m *Vector) GetValues() []float64 {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+// A dense matrix; values holds rows*cols numbers, row by row.
+type Matrix struct {
+	Rows                 uint32    `protobuf:"varint,1,opt,name=rows,proto3" json:"rows,omitempty"`
+	Cols                 uint32    `protobuf:"varint,2,opt,name=cols,proto3" json:"cols,omitempty"`
+	Values               []float64 `protobuf:"fixed64,3,rep,packed,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Matrix) Reset()         { *m = Matrix{} }
+func (m *Matrix) String() string { return proto.CompactTextString(m) }
+func (*Matrix) ProtoMessage()    {}
+func (*Matrix) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{31}
+}
+
+func (m *Matrix) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Matrix.Unmarshal(m, b)
+}
+func (m *Matrix) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Matrix.Marshal(b, m, deterministic)
+}
+func (m *Matrix) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Matrix.Merge(m, src)
+}
+func (m *Matrix) XXX_Size() int {
+	return xxx_messageInfo_Matrix.Size(m)
+}
+func (m *Matrix) XXX_DiscardUnknown() {
+	xxx_messageInfo_Matrix.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Matrix proto.InternalMessageInfo
+
+func (m *Matrix) GetRows() uint32 {
+	if m != nil {
+		return m.Rows
+	}
+	return 0
+}
+
+func (m *Matrix) GetCols() uint32 {
+	if m != nil {
+		return m.Cols
+	}
+	return 0
+}
+
+func (m *Matrix) GetValues() []float64 {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type DotProductRequest struct {
+	A                    *Vector  `protobuf:"bytes,1,opt,name=a,proto3" json:"a,omitempty"`
+	B                    *Vector  `protobuf:"bytes,2,opt,name=b,proto3" json:"b,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DotProductRequest) Reset()         { *m = DotProductRequest{} }
+func (m *DotProductRequest) String() string { return proto.CompactTextString(m) }
+func (*DotProductRequest) ProtoMessage()    {}
+func (*DotProductRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{32}
+}
+
+func (m *DotProductRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DotProductRequest.Unmarshal(m, b)
+}
+func (m *DotProductRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DotProductRequest.Marshal(b, m, deterministic)
+}
+func (m *DotProductRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DotProductRequest.Merge(m, src)
+}
+func (m *DotProductRequest) XXX_Size() int {
+	return xxx_messageInfo_DotProductRequest.Size(m)
+}
+func (m *DotProductRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DotProductRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DotProductRequest proto.InternalMessageInfo
+
+func (m *DotProductRequest) GetA() *Vector {
+	if m != nil {
+		return m.A
+	}
+	return nil
+}
+
+func (m *DotProductRequest) GetB() *Vector {
+	if m != nil {
+		return m.B
+	}
+	return nil
+}
+
+type DotProductResponse struct {
+	Result               float64  `protobuf:"fixed64,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DotProductResponse) Reset()         { *m = DotProductResponse{} }
+func (m *DotProductResponse) String() string { return proto.CompactTextString(m) }
+func (*DotProductResponse) ProtoMessage()    {}
+func (*DotProductResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{33}
+}
+
+func (m *DotProductResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DotProductResponse.Unmarshal(m, b)
+}
+func (m *DotProductResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DotProductResponse.Marshal(b, m, deterministic)
+}
+func (m *DotProductResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DotProductResponse.Merge(m, src)
+}
+func (m *DotProductResponse) XXX_Size() int {
+	return xxx_messageInfo_DotProductResponse.Size(m)
+}
+func (m *DotProductResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DotProductResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DotProductResponse proto.InternalMessageInfo
+
+func (m *DotProductResponse) GetResult() float64 {
+	if m != nil {
+		return m.Result
+	}
+	return 0
+}
+
+type MatrixMultiplyRequest struct {
+	A                    *Matrix  `protobuf:"bytes,1,opt,name=a,proto3" json:"a,omitempty"`
+	B                    *Matrix  `protobuf:"bytes,2,opt,name=b,proto3" json:"b,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MatrixMultiplyRequest) Reset()         { *m = MatrixMultiplyRequest{} }
+func (m *MatrixMultiplyRequest) String() string { return proto.CompactTextString(m) }
+func (*MatrixMultiplyRequest) ProtoMessage()    {}
+func (*MatrixMultiplyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{34}
+}
+
+func (m *MatrixMultiplyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MatrixMultiplyRequest.Unmarshal(m, b)
+}
+func (m *MatrixMultiplyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MatrixMultiplyRequest.Marshal(b, m, deterministic)
+}
+func (m *MatrixMultiplyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MatrixMultiplyRequest.Merge(m, src)
+}
+func (m *MatrixMultiplyRequest) XXX_Size() int {
+	return xxx_messageInfo_MatrixMultiplyRequest.Size(m)
+}
+func (m *MatrixMultiplyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MatrixMultiplyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MatrixMultiplyRequest proto.InternalMessageInfo
+
+func (m *MatrixMultiplyRequest) GetA() *Matrix {
+	if m != nil {
+		return m.A
+	}
+	return nil
+}
+
+func (m *MatrixMultiplyRequest) GetB() *Matrix {
+	if m != nil {
+		return m.B
+	}
+	return nil
+}
+
+type MatrixRequest struct {
+	Matrix               *Matrix  `protobuf:"bytes,1,opt,name=matrix,proto3" json:"matrix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MatrixRequest) Reset()         { *m = MatrixRequest{} }
+func (m *MatrixRequest) String() string { return proto.CompactTextString(m) }
+func (*MatrixRequest) ProtoMessage()    {}
+func (*MatrixRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{35}
+}
+
+func (m *MatrixRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MatrixRequest.Unmarshal(m, b)
+}
+func (m *MatrixRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MatrixRequest.Marshal(b, m, deterministic)
+}
+func (m *MatrixRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MatrixRequest.Merge(m, src)
+}
+func (m *MatrixRequest) XXX_Size() int {
+	return xxx_messageInfo_MatrixRequest.Size(m)
+}
+func (m *MatrixRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MatrixRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MatrixRequest proto.InternalMessageInfo
+
+func (m *MatrixRequest) GetMatrix() *Matrix {
+	if m != nil {
+		return m.Matrix
+	}
+	return nil
+}
+
+type MatrixResponse struct {
+	Result               *Matrix  `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MatrixResponse) Reset()         { *m = MatrixResponse{} }
+func (m *MatrixResponse) String() string { return proto.CompactTextString(m) }
+func (*MatrixResponse) ProtoMessage()    {}
+func (*MatrixResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{36}
+}
+
+func (m *MatrixResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MatrixResponse.Unmarshal(m, b)
+}
+func (m *MatrixResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MatrixResponse.Marshal(b, m, deterministic)
+}
+func (m *MatrixResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MatrixResponse.Merge(m, src)
+}
+func (m *MatrixResponse) XXX_Size() int {
+	return xxx_messageInfo_MatrixResponse.Size(m)
+}
+func (m *MatrixResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MatrixResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MatrixResponse proto.InternalMessageInfo
+
+func (m *MatrixResponse) GetResult() *Matrix {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type DeterminantResponse struct {
+	Determinant          float64  `protobuf:"fixed64,1,opt,name=determinant,proto3" json:"determinant,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeterminantResponse) Reset()         { *m = DeterminantResponse{} }
+func (m *DeterminantResponse) String() string { return proto.CompactTextString(m) }
+func (*DeterminantResponse) ProtoMessage()    {}
+func (*DeterminantResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{37}
+}
+
+func (m *DeterminantResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeterminantResponse.Unmarshal(m, b)
+}
+func (m *DeterminantResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeterminantResponse.Marshal(b, m, deterministic)
+}
+func (m *DeterminantResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeterminantResponse.Merge(m, src)
+}
+func (m *DeterminantResponse) XXX_Size() int {
+	return xxx_messageInfo_DeterminantResponse.Size(m)
+}
+func (m *DeterminantResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeterminantResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeterminantResponse proto.InternalMessageInfo
+
+func (m *DeterminantResponse) GetDeterminant() float64 {
+	if m != nil {
+		return m.Determinant
+	}
+	return 0
+}
+
+type SolveRequest struct {
+	A                    *Matrix  `protobuf:"bytes,1,opt,name=a,proto3" json:"a,omitempty"`
+	B                    *Vector  `protobuf:"bytes,2,opt,name=b,proto3" json:"b,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SolveRequest) Reset()         { *m = SolveRequest{} }
+func (m *SolveRequest) String() string { return proto.CompactTextString(m) }
+func (*SolveRequest) ProtoMessage()    {}
+func (*SolveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{38}
+}
+
+func (m *SolveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SolveRequest.Unmarshal(m, b)
+}
+func (m *SolveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SolveRequest.Marshal(b, m, deterministic)
+}
+func (m *SolveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SolveRequest.Merge(m, src)
+}
+func (m *SolveRequest) XXX_Size() int {
+	return xxx_messageInfo_SolveRequest.Size(m)
+}
+func (m *SolveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SolveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SolveRequest proto.InternalMessageInfo
+
+func (m *SolveRequest) GetA() *Matrix {
+	if m != nil {
+		return m.A
+	}
+	return nil
+}
+
+func (m *SolveRequest) GetB() *Vector {
+	if m != nil {
+		return m.B
+	}
+	return nil
+}
+
+type SolveResponse struct {
+	X                    *Vector  `protobuf:"bytes,1,opt,name=x,proto3" json:"x,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SolveResponse) Reset()         { *m = SolveResponse{} }
+func (m *SolveResponse) String() string { return proto.CompactTextString(m) }
+func (*SolveResponse) ProtoMessage()    {}
+func (*SolveResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{39}
+}
+
+func (m *SolveResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SolveResponse.Unmarshal(m, b)
+}
+func (m *SolveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SolveResponse.Marshal(b, m, deterministic)
+}
+func (m *SolveResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SolveResponse.Merge(m, src)
+}
+func (m *SolveResponse) XXX_Size() int {
+	return xxx_messageInfo_SolveResponse.Size(m)
+}
+func (m *SolveResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SolveResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SolveResponse proto.InternalMessageInfo
+
+func (m *SolveResponse) GetX() *Vector {
+	if m != nil {
+		return m.X
+	}
+	return nil
+}
+
+// An integer given either as an int64 or, when it may not fit, as a
+// decimal string.
+type Integer struct {
+	// Types that are valid to be assigned to Value:
+	//	*Integer_IntValue
+	//	*Integer_BigValue
+	Value                isInteger_Value `protobuf_oneof:"value"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *Integer) Reset()         { *m = Integer{} }
+func (m *Integer) String() string { return proto.CompactTextString(m) }
+func (*Integer) ProtoMessage()    {}
+func (*Integer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{40}
+}
+
+func (m *Integer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Integer.Unmarshal(m, b)
+}
+func (m *Integer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Integer.Marshal(b, m, deterministic)
+}
+func (m *Integer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Integer.Merge(m, src)
+}
+func (m *Integer) XXX_Size() int {
+	return xxx_messageInfo_Integer.Size(m)
+}
+func (m *Integer) XXX_DiscardUnknown() {
+	xxx_messageInfo_Integer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Integer proto.InternalMessageInfo
+
+type isInteger_Value interface {
+	isInteger_Value()
+}
+
+type Integer_IntValue struct {
+	IntValue int64 `protobuf:"varint,1,opt,name=int_value,json=intValue,proto3,oneof"`
+}
+
+type Integer_BigValue struct {
+	BigValue string `protobuf:"bytes,2,opt,name=big_value,json=bigValue,proto3,oneof"`
+}
+
+func (*Integer_IntValue) isInteger_Value() {}
+
+func (*Integer_BigValue) isInteger_Value() {}
+
+func (m *Integer) GetValue() isInteger_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *Integer) GetIntValue() int64 {
+	if x, ok := m.GetValue().(*Integer_IntValue); ok {
+		return x.IntValue
+	}
+	return 0
+}
+
+func (m *Integer) GetBigValue() string {
+	if x, ok := m.GetValue().(*Integer_BigValue); ok {
+		return x.BigValue
+	}
+	return ""
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Integer) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Integer_IntValue)(nil),
+		(*Integer_BigValue)(nil),
+	}
+}
+
+type IntegerListRequest struct {
+	Numbers              []*Integer `protobuf:"bytes,1,rep,name=numbers,proto3" json:"numbers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *IntegerListRequest) Reset()         { *m = IntegerListRequest{} }
+func (m *IntegerListRequest) String() string { return proto.CompactTextString(m) }
+func (*IntegerListRequest) ProtoMessage()    {}
+func (*IntegerListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{41}
+}
+
+func (m *IntegerListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IntegerListRequest.Unmarshal(m, b)
+}
+func (m *IntegerListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IntegerListRequest.Marshal(b, m, deterministic)
+}
+func (m *IntegerListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IntegerListRequest.Merge(m, src)
+}
+func (m *IntegerListRequest) XXX_Size() int {
+	return xxx_messageInfo_IntegerListRequest.Size(m)
+}
+func (m *IntegerListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IntegerListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IntegerListRequest proto.InternalMessageInfo
+
+func (m *IntegerListRequest) GetNumbers() []*Integer {
+	if m != nil {
+		return m.Numbers
+	}
+	return nil
+}
+
+type IntegerResponse struct {
+	Result               *Integer `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IntegerResponse) Reset()         { *m = IntegerResponse{} }
+func (m *IntegerResponse) String() string { return proto.CompactTextString(m) }
+func (*IntegerResponse) ProtoMessage()    {}
+func (*IntegerResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{42}
+}
+
+func (m *IntegerResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IntegerResponse.Unmarshal(m, b)
+}
+func (m *IntegerResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IntegerResponse.Marshal(b, m, deterministic)
+}
+func (m *IntegerResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IntegerResponse.Merge(m, src)
+}
+func (m *IntegerResponse) XXX_Size() int {
+	return xxx_messageInfo_IntegerResponse.Size(m)
+}
+func (m *IntegerResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_IntegerResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IntegerResponse proto.InternalMessageInfo
+
+func (m *IntegerResponse) GetResult() *Integer {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type ModPowRequest struct {
+	Base                 *Integer `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Exponent             *Integer `protobuf:"bytes,2,opt,name=exponent,proto3" json:"exponent,omitempty"`
+	Modulus              *Integer `protobuf:"bytes,3,opt,name=modulus,proto3" json:"modulus,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ModPowRequest) Reset()         { *m = ModPowRequest{} }
+func (m *ModPowRequest) String() string { return proto.CompactTextString(m) }
+func (*ModPowRequest) ProtoMessage()    {}
+func (*ModPowRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{43}
+}
+
+func (m *ModPowRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ModPowRequest.Unmarshal(m, b)
+}
+func (m *ModPowRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ModPowRequest.Marshal(b, m, deterministic)
+}
+func (m *ModPowRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ModPowRequest.Merge(m, src)
+}
+func (m *ModPowRequest) XXX_Size() int {
+	return xxx_messageInfo_ModPowRequest.Size(m)
+}
+func (m *ModPowRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ModPowRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ModPowRequest proto.InternalMessageInfo
+
+func (m *ModPowRequest) GetBase() *Integer {
+	if m != nil {
+		return m.Base
+	}
+	return nil
+}
+
+func (m *ModPowRequest) GetExponent() *Integer {
+	if m != nil {
+		return m.Exponent
+	}
+	return nil
+}
+
+func (m *ModPowRequest) GetModulus() *Integer {
+	if m != nil {
+		return m.Modulus
+	}
+	return nil
+}
+
+type ModInverseRequest struct {
+	Number               *Integer `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	Modulus              *Integer `protobuf:"bytes,2,opt,name=modulus,proto3" json:"modulus,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ModInverseRequest) Reset()         { *m = ModInverseRequest{} }
+func (m *ModInverseRequest) String() string { return proto.CompactTextString(m) }
+func (*ModInverseRequest) ProtoMessage()    {}
+func (*ModInverseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{44}
+}
+
+func (m *ModInverseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ModInverseRequest.Unmarshal(m, b)
+}
+func (m *ModInverseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ModInverseRequest.Marshal(b, m, deterministic)
+}
+func (m *ModInverseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ModInverseRequest.Merge(m, src)
+}
+func (m *ModInverseRequest) XXX_Size() int {
+	return xxx_messageInfo_ModInverseRequest.Size(m)
+}
+func (m *ModInverseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ModInverseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ModInverseRequest proto.InternalMessageInfo
+
+func (m *ModInverseRequest) GetNumber() *Integer {
+	if m != nil {
+		return m.Number
+	}
+	return nil
+}
+
+func (m *ModInverseRequest) GetModulus() *Integer {
+	if m != nil {
+		return m.Modulus
+	}
+	return nil
+}
+
+type ExtendedGCDRequest struct {
+	A                    *Integer `protobuf:"bytes,1,opt,name=a,proto3" json:"a,omitempty"`
+	B                    *Integer `protobuf:"bytes,2,opt,name=b,proto3" json:"b,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExtendedGCDRequest) Reset()         { *m = ExtendedGCDRequest{} }
+func (m *ExtendedGCDRequest) String() string { return proto.CompactTextString(m) }
+func (*ExtendedGCDRequest) ProtoMessage()    {}
+func (*ExtendedGCDRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{45}
+}
+
+func (m *ExtendedGCDRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExtendedGCDRequest.Unmarshal(m, b)
+}
+func (m *ExtendedGCDRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExtendedGCDRequest.Marshal(b, m, deterministic)
+}
+func (m *ExtendedGCDRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExtendedGCDRequest.Merge(m, src)
+}
+func (m *ExtendedGCDRequest) XXX_Size() int {
+	return xxx_messageInfo_ExtendedGCDRequest.Size(m)
+}
+func (m *ExtendedGCDRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExtendedGCDRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExtendedGCDRequest proto.InternalMessageInfo
+
+func (m *ExtendedGCDRequest) GetA() *Integer {
+	if m != nil {
+		return m.A
+	}
+	return nil
+}
+
+func (m *ExtendedGCDRequest) GetB() *Integer {
+	if m != nil {
+		return m.B
+	}
+	return nil
+}
+
+// gcd = a*x + b*y.
+type ExtendedGCDResponse struct {
+	Gcd                  *Integer `protobuf:"bytes,1,opt,name=gcd,proto3" json:"gcd,omitempty"`
+	X                    *Integer `protobuf:"bytes,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y                    *Integer `protobuf:"bytes,3,opt,name=y,proto3" json:"y,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExtendedGCDResponse) Reset()         { *m = ExtendedGCDResponse{} }
+func (m *ExtendedGCDResponse) String() string { return proto.CompactTextString(m) }
+func (*ExtendedGCDResponse) ProtoMessage()    {}
+func (*ExtendedGCDResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{46}
+}
+
+func (m *ExtendedGCDResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExtendedGCDResponse.Unmarshal(m, b)
+}
+func (m *ExtendedGCDResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExtendedGCDResponse.Marshal(b, m, deterministic)
+}
+func (m *ExtendedGCDResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExtendedGCDResponse.Merge(m, src)
+}
+func (m *ExtendedGCDResponse) XXX_Size() int {
+	return xxx_messageInfo_ExtendedGCDResponse.Size(m)
+}
+func (m *ExtendedGCDResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExtendedGCDResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExtendedGCDResponse proto.InternalMessageInfo
+
+func (m *ExtendedGCDResponse) GetGcd() *Integer {
+	if m != nil {
+		return m.Gcd
+	}
+	return nil
+}
+
+func (m *ExtendedGCDResponse) GetX() *Integer {
+	if m != nil {
+		return m.X
+	}
+	return nil
+}
+
+func (m *ExtendedGCDResponse) GetY() *Integer {
+	if m != nil {
+		return m.Y
+	}
+	return nil
+}
+
+// A unit converts to its dimension's base unit as
+// base = value * factor + offset.
+type Unit struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Symbol               string   `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Dimension            string   `protobuf:"bytes,3,opt,name=dimension,proto3" json:"dimension,omitempty"`
+	Aliases              []string `protobuf:"bytes,4,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	Factor               float64  `protobuf:"fixed64,5,opt,name=factor,proto3" json:"factor,omitempty"`
+	Offset               float64  `protobuf:"fixed64,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Unit) Reset()         { *m = Unit{} }
+func (m *Unit) String() string { return proto.CompactTextString(m) }
+func (*Unit) ProtoMessage()    {}
+func (*Unit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{47}
+}
+
+func (m *Unit) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Unit.Unmarshal(m, b)
+}
+func (m *Unit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Unit.Marshal(b, m, deterministic)
+}
+func (m *Unit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Unit.Merge(m, src)
+}
+func (m *Unit) XXX_Size() int {
+	return xxx_messageInfo_Unit.Size(m)
+}
+func (m *Unit) XXX_DiscardUnknown() {
+	xxx_messageInfo_Unit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Unit proto.InternalMessageInfo
+
+func (m *Unit) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Unit) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+func (m *Unit) GetDimension() string {
+	if m != nil {
+		return m.Dimension
+	}
+	return ""
+}
+
+func (m *Unit) GetAliases() []string {
+	if m != nil {
+		return m.Aliases
+	}
+	return nil
+}
+
+func (m *Unit) GetFactor() float64 {
+	if m != nil {
+		return m.Factor
+	}
+	return 0
+}
+
+func (m *Unit) GetOffset() float64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+// from and to accept a unit's name, symbol or any alias.
+type ConvertRequest struct {
+	Value                float64  `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	From                 string   `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string   `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConvertRequest) Reset()         { *m = ConvertRequest{} }
+func (m *ConvertRequest) String() string { return proto.CompactTextString(m) }
+func (*ConvertRequest) ProtoMessage()    {}
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{48}
+}
+
+func (m *ConvertRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConvertRequest.Unmarshal(m, b)
+}
+func (m *ConvertRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConvertRequest.Marshal(b, m, deterministic)
+}
+func (m *ConvertRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConvertRequest.Merge(m, src)
+}
+func (m *ConvertRequest) XXX_Size() int {
+	return xxx_messageInfo_ConvertRequest.Size(m)
+}
+func (m *ConvertRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConvertRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConvertRequest proto.InternalMessageInfo
+
+func (m *ConvertRequest) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *ConvertRequest) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *ConvertRequest) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+type ConvertResponse struct {
+	Value                float64  `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	From                 *Unit    `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To                   *Unit    `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConvertResponse) Reset()         { *m = ConvertResponse{} }
+func (m *ConvertResponse) String() string { return proto.CompactTextString(m) }
+func (*ConvertResponse) ProtoMessage()    {}
+func (*ConvertResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{49}
+}
+
+func (m *ConvertResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConvertResponse.Unmarshal(m, b)
+}
+func (m *ConvertResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConvertResponse.Marshal(b, m, deterministic)
+}
+func (m *ConvertResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConvertResponse.Merge(m, src)
+}
+func (m *ConvertResponse) XXX_Size() int {
+	return xxx_messageInfo_ConvertResponse.Size(m)
+}
+func (m *ConvertResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConvertResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConvertResponse proto.InternalMessageInfo
+
+func (m *ConvertResponse) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *ConvertResponse) GetFrom() *Unit {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *ConvertResponse) GetTo() *Unit {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+// An empty dimension lists every unit.
+type ListUnitsRequest struct {
+	Dimension            string   `protobuf:"bytes,1,opt,name=dimension,proto3" json:"dimension,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListUnitsRequest) Reset()         { *m = ListUnitsRequest{} }
+func (m *ListUnitsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListUnitsRequest) ProtoMessage()    {}
+func (*ListUnitsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{50}
+}
+
+func (m *ListUnitsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListUnitsRequest.Unmarshal(m, b)
+}
+func (m *ListUnitsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListUnitsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListUnitsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListUnitsRequest.Merge(m, src)
+}
+func (m *ListUnitsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListUnitsRequest.Size(m)
+}
+func (m *ListUnitsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListUnitsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListUnitsRequest proto.InternalMessageInfo
+
+func (m *ListUnitsRequest) GetDimension() string {
+	if m != nil {
+		return m.Dimension
+	}
+	return ""
+}
+
+type ListUnitsResponse struct {
+	Units                []*Unit  `protobuf:"bytes,1,rep,name=units,proto3" json:"units,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListUnitsResponse) Reset()         { *m = ListUnitsResponse{} }
+func (m *ListUnitsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListUnitsResponse) ProtoMessage()    {}
+func (*ListUnitsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{51}
+}
+
+func (m *ListUnitsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListUnitsResponse.Unmarshal(m, b)
+}
+func (m *ListUnitsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListUnitsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListUnitsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListUnitsResponse.Merge(m, src)
+}
+func (m *ListUnitsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListUnitsResponse.Size(m)
+}
+func (m *ListUnitsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListUnitsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListUnitsResponse proto.InternalMessageInfo
+
+func (m *ListUnitsResponse) GetUnits() []*Unit {
+	if m != nil {
+		return m.Units
+	}
+	return nil
+}
+
+type EvaluateRequest struct {
+	Expression           string             `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	Variables            map[string]float64 `protobuf:"bytes,2,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *EvaluateRequest) Reset()         { *m = EvaluateRequest{} }
+func (m *EvaluateRequest) String() string { return proto.CompactTextString(m) }
+func (*EvaluateRequest) ProtoMessage()    {}
+func (*EvaluateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{52}
+}
+
+func (m *EvaluateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EvaluateRequest.Unmarshal(m, b)
+}
+func (m *EvaluateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EvaluateRequest.Marshal(b, m, deterministic)
+}
+func (m *EvaluateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EvaluateRequest.Merge(m, src)
+}
+func (m *EvaluateRequest) XXX_Size() int {
+	return xxx_messageInfo_EvaluateRequest.Size(m)
+}
+func (m *EvaluateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EvaluateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EvaluateRequest proto.InternalMessageInfo
+
+func (m *EvaluateRequest) GetExpression() string {
+	if m != nil {
+		return m.Expression
+	}
+	return ""
+}
+
+func (m *EvaluateRequest) GetVariables() map[string]float64 {
+	if m != nil {
+		return m.Variables
+	}
+	return nil
+}
+
+type EvaluateResponse struct {
+	Result               float64  `protobuf:"fixed64,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EvaluateResponse) Reset()         { *m = EvaluateResponse{} }
+func (m *EvaluateResponse) String() string { return proto.CompactTextString(m) }
+func (*EvaluateResponse) ProtoMessage()    {}
+func (*EvaluateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f42938f8c8365cf, []int{53}
+}
+
+func (m *EvaluateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EvaluateResponse.Unmarshal(m, b)
+}
+func (m *EvaluateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EvaluateResponse.Marshal(b, m, deterministic)
+}
+func (m *EvaluateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EvaluateResponse.Merge(m, src)
+}
+func (m *EvaluateResponse) XXX_Size() int {
+	return xxx_messageInfo_EvaluateResponse.Size(m)
+}
+func (m *EvaluateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EvaluateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EvaluateResponse proto.InternalMessageInfo
+
+func (m *EvaluateResponse) GetResult() float64 {
+	if m != nil {
+		return m.Result
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("calculator.Operation", Operation_name, Operation_value)
+	proto.RegisterEnum("calculator.Aggregate", Aggregate_name, Aggregate_value)
+	proto.RegisterType((*Calculator)(nil), "calculator.Calculator")
+	proto.RegisterType((*Prime)(nil), "calculator.Prime")
+	proto.RegisterType((*AverageRequest)(nil), "calculator.AverageRequest")
+	proto.RegisterType((*CalculatorRequest)(nil), "calculator.CalculatorRequest")
+	proto.RegisterType((*CalculatorResponse)(nil), "calculator.CalculatorResponse")
+	proto.RegisterType((*PrimeRequest)(nil), "calculator.PrimeRequest")
+	proto.RegisterType((*FindMaxRequest)(nil), "calculator.FindMaxRequest")
+	proto.RegisterType((*SquareRootRequest)(nil), "calculator.SquareRootRequest")
+	proto.RegisterType((*PrimeResponse)(nil), "calculator.PrimeResponse")
+	proto.RegisterType((*AverageResponse)(nil), "calculator.AverageResponse")
+	proto.RegisterType((*FindMaxResponse)(nil), "calculator.FindMaxResponse")
+	proto.RegisterType((*SquareRootResponse)(nil), "calculator.SquareRootResponse")
+	proto.RegisterType((*Operand)(nil), "calculator.Operand")
+	proto.RegisterType((*CalculateRequest)(nil), "calculator.CalculateRequest")
+	proto.RegisterType((*CalculateResponse)(nil), "calculator.CalculateResponse")
+	proto.RegisterType((*CalculateBigRequest)(nil), "calculator.CalculateBigRequest")
+	proto.RegisterType((*CalculateBigResponse)(nil), "calculator.CalculateBigResponse")
+	proto.RegisterType((*IsPrimeRequest)(nil), "calculator.IsPrimeRequest")
+	proto.RegisterType((*IsPrimeResponse)(nil), "calculator.IsPrimeResponse")
+	proto.RegisterType((*NextPrimeRequest)(nil), "calculator.NextPrimeRequest")
+	proto.RegisterType((*NextPrimeResponse)(nil), "calculator.NextPrimeResponse")
+	proto.RegisterType((*GeneratePrimesRequest)(nil), "calculator.GeneratePrimesRequest")
+	proto.RegisterType((*GeneratePrimesResponse)(nil), "calculator.GeneratePrimesResponse")
+	proto.RegisterType((*StatisticsRequest)(nil), "calculator.StatisticsRequest")
+	proto.RegisterType((*Percentile)(nil), "calculator.Percentile")
+	proto.RegisterType((*StatisticsResponse)(nil), "calculator.StatisticsResponse")
+	proto.RegisterType((*RunningStatsConfig)(nil), "calculator.RunningStatsConfig")
+	proto.RegisterType((*RunningStatsRequest)(nil), "calculator.RunningStatsRequest")
+	proto.RegisterType((*AggregateValue)(nil), "calculator.AggregateValue")
+	proto.RegisterType((*RunningStatsResponse)(nil), "calculator.RunningStatsResponse")
+	proto.RegisterType((*Vector)(nil), "calculator.Vector")
+	proto.RegisterType((*Matrix)(nil), "calculator.Matrix")
+	proto.RegisterType((*DotProductRequest)(nil), "calculator.DotProductRequest")
+	proto.RegisterType((*DotProductResponse)(nil), "calculator.DotProductResponse")
+	proto.RegisterType((*MatrixMultiplyRequest)(nil), "calculator.MatrixMultiplyRequest")
+	proto.RegisterType((*MatrixRequest)(nil), "calculator.MatrixRequest")
+	proto.RegisterType((*MatrixResponse)(nil), "calculator.MatrixResponse")
+	proto.RegisterType((*DeterminantResponse)(nil), "calculator.DeterminantResponse")
+	proto.RegisterType((*SolveRequest)(nil), "calculator.SolveRequest")
+	proto.RegisterType((*SolveResponse)(nil), "calculator.SolveResponse")
+	proto.RegisterType((*Integer)(nil), "calculator.Integer")
+	proto.RegisterType((*IntegerListRequest)(nil), "calculator.IntegerListRequest")
+	proto.RegisterType((*IntegerResponse)(nil), "calculator.IntegerResponse")
+	proto.RegisterType((*ModPowRequest)(nil), "calculator.ModPowRequest")
+	proto.RegisterType((*ModInverseRequest)(nil), "calculator.ModInverseRequest")
+	proto.RegisterType((*ExtendedGCDRequest)(nil), "calculator.ExtendedGCDRequest")
+	proto.RegisterType((*ExtendedGCDResponse)(nil), "calculator.ExtendedGCDResponse")
+	proto.RegisterType((*Unit)(nil), "calculator.Unit")
+	proto.RegisterType((*ConvertRequest)(nil), "calculator.ConvertRequest")
+	proto.RegisterType((*ConvertResponse)(nil), "calculator.ConvertResponse")
+	proto.RegisterType((*ListUnitsRequest)(nil), "calculator.ListUnitsRequest")
+	proto.RegisterType((*ListUnitsResponse)(nil), "calculator.ListUnitsResponse")
+	proto.RegisterType((*EvaluateRequest)(nil), "calculator.EvaluateRequest")
+	proto.RegisterMapType((map[string]float64)(nil), "calculator.EvaluateRequest.VariablesEntry")
+	proto.RegisterType((*EvaluateResponse)(nil), "calculator.EvaluateResponse")
+}
+
+func init() {
+	proto.RegisterFile("calculator/calculatorpb/calculator.proto", fileDescriptor_7f42938f8c8365cf)
+}
+
+var fileDescriptor_7f42938f8c8365cf = []byte{
+	// 2204 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x59, 0x5b, 0x73, 0xdb, 0xb8,
+	0x15, 0x0e, 0xad, 0xfb, 0x91, 0x2d, 0xd3, 0xc8, 0xa5, 0x0a, 0x1d, 0x3b, 0x0a, 0xb7, 0x6d, 0x12,
+	0x67, 0x9b, 0x4d, 0x9d, 0x69, 0x9b, 0x69, 0x76, 0x76, 0x2a, 0x5b, 0x8a, 0xad, 0x6c, 0x64, 0xab,
+	0x94, 0xad, 0xb4, 0x9b, 0x07, 0x97, 0x92, 0x60, 0x2d, 0x67, 0x45, 0x52, 0x21, 0x29, 0x5b, 0xde,
+	0xe9, 0x53, 0x5f, 0xfa, 0xd4, 0xf7, 0x4e, 0xfb, 0x4b, 0xfa, 0xd8, 0x9f, 0xd3, 0xfe, 0x8a, 0x0e,
+	0x2e, 0x04, 0x01, 0x99, 0x92, 0x33, 0xcd, 0x1b, 0xcf, 0x39, 0x1f, 0x3e, 0x9c, 0x0b, 0x20, 0x1c,
+	0x40, 0xf0, 0x64, 0x60, 0x8f, 0x07, 0xd3, 0xb1, 0x1d, 0xf9, 0xc1, 0x57, 0xc9, 0xe7, 0xa4, 0x2f,
+	0x09, 0xcf, 0x27, 0x81, 0x1f, 0xf9, 0x08, 0x12, 0x8d, 0xd9, 0x01, 0xd8, 0x17, 0x12, 0x7a, 0x04,
+	0xab, 0xe7, 0x4e, 0x10, 0x46, 0x67, 0xde, 0xd4, 0xed, 0xe3, 0xa0, 0xaa, 0xd5, 0xb4, 0x27, 0x19,
+	0xab, 0x4c, 0x75, 0x47, 0x54, 0x85, 0x1e, 0x42, 0x79, 0x6c, 0x27, 0x88, 0x15, 0x8a, 0x80, 0xb1,
+	0x1d, 0x03, 0xcc, 0x87, 0x90, 0xeb, 0x04, 0x8e, 0x8b, 0xd1, 0x3d, 0xc8, 0x2b, 0x34, 0x5c, 0x32,
+	0x9f, 0x40, 0xa5, 0x7e, 0x81, 0x03, 0x7b, 0x84, 0x2d, 0xfc, 0x71, 0x8a, 0xc3, 0x68, 0x21, 0xf2,
+	0x5b, 0xd8, 0x48, 0x9c, 0x8b, 0xc1, 0xbf, 0x06, 0xc9, 0x7f, 0x3a, 0xa0, 0xbc, 0x7b, 0xef, 0xb9,
+	0x14, 0xa4, 0x34, 0x44, 0x8e, 0xf4, 0x4b, 0x40, 0x32, 0x59, 0x38, 0xf1, 0xbd, 0x90, 0x3a, 0x19,
+	0xe0, 0x70, 0x3a, 0x8e, 0xe2, 0xa9, 0x99, 0x64, 0xf6, 0x60, 0x95, 0x46, 0x11, 0xcf, 0xfa, 0x18,
+	0x72, 0x13, 0x22, 0xf3, 0x09, 0x37, 0xe4, 0x09, 0x19, 0x90, 0xd9, 0xd1, 0x16, 0x40, 0xdf, 0x19,
+	0xc9, 0xe9, 0x29, 0x59, 0xa5, 0xbe, 0x33, 0x3a, 0x12, 0xc1, 0xbf, 0x71, 0xbc, 0x61, 0xdb, 0x9e,
+	0xa5, 0x07, 0xaf, 0x89, 0xe0, 0x9f, 0xc1, 0x46, 0xf7, 0xe3, 0xd4, 0x0e, 0xb0, 0xe5, 0xfb, 0xd1,
+	0x4d, 0xe0, 0x37, 0xb0, 0xc6, 0xdd, 0x4d, 0xe2, 0x4a, 0x4b, 0xe9, 0x4d, 0xee, 0x3d, 0x85, 0x75,
+	0x51, 0x9b, 0x54, 0xa6, 0x64, 0xca, 0xa7, 0xb0, 0x2e, 0x22, 0xb9, 0x01, 0xfa, 0x25, 0x20, 0x39,
+	0x94, 0x1b, 0xd0, 0x3d, 0x28, 0x1c, 0x4f, 0x70, 0x60, 0x7b, 0x43, 0xb4, 0x05, 0x25, 0xc7, 0x8b,
+	0xce, 0x2e, 0xec, 0xf1, 0x94, 0x65, 0x3e, 0x73, 0x78, 0xcb, 0x2a, 0x3a, 0x5e, 0xd4, 0x23, 0x1a,
+	0xf4, 0x05, 0xac, 0x0e, 0xfd, 0x69, 0x7f, 0x8c, 0x39, 0x82, 0x84, 0xa3, 0x1d, 0xde, 0xb2, 0xca,
+	0x4c, 0x4b, 0x41, 0x7b, 0x05, 0xc8, 0x51, 0xab, 0xf9, 0x4f, 0x0d, 0xf4, 0x78, 0x05, 0x88, 0xba,
+	0xbe, 0x84, 0x92, 0x4f, 0x26, 0x8b, 0x1c, 0xdf, 0xa3, 0x33, 0x54, 0x76, 0xef, 0xca, 0xb5, 0x3d,
+	0x8e, 0x8d, 0x56, 0x82, 0x43, 0x8f, 0x21, 0x3b, 0xc6, 0xe7, 0x11, 0x9d, 0xaf, 0xbc, 0x7b, 0xfb,
+	0x1a, 0xde, 0x1b, 0x5a, 0x14, 0x80, 0x9e, 0x42, 0x2e, 0x70, 0x46, 0xdf, 0x47, 0xd5, 0xcc, 0x62,
+	0x24, 0x43, 0x98, 0xbf, 0x4b, 0xd6, 0x7a, 0x92, 0xfb, 0x67, 0xca, 0xea, 0x5c, 0x40, 0x10, 0x2f,
+	0xd9, 0x3f, 0xc3, 0x6d, 0xc1, 0xb0, 0xe7, 0x8c, 0x3e, 0x2b, 0x42, 0x03, 0x8a, 0x3e, 0xa3, 0x0f,
+	0xab, 0x2b, 0xb5, 0xcc, 0x93, 0x92, 0x25, 0x64, 0x74, 0x07, 0x72, 0xe1, 0xc0, 0x1e, 0x63, 0x1a,
+	0xd4, 0x9a, 0xc5, 0x04, 0xb3, 0x01, 0x77, 0xd4, 0xd9, 0x53, 0x37, 0x58, 0x29, 0xf6, 0x96, 0xb0,
+	0xe0, 0x99, 0x3d, 0x60, 0x49, 0x2c, 0x5a, 0x4c, 0x20, 0xdb, 0xa3, 0x15, 0x2a, 0x1b, 0x4f, 0x5d,
+	0x25, 0x59, 0x69, 0x4d, 0xad, 0x0b, 0x24, 0x9f, 0xea, 0x3e, 0x14, 0x9d, 0xf0, 0x2c, 0xd9, 0xa6,
+	0x45, 0xab, 0xe0, 0x30, 0x88, 0xb9, 0x03, 0xfa, 0x11, 0x9e, 0x45, 0x9f, 0xc4, 0xfc, 0x14, 0x36,
+	0x24, 0x2c, 0xe7, 0xbe, 0x23, 0xef, 0xff, 0x2c, 0xdf, 0xec, 0xe6, 0x6b, 0xb8, 0x7b, 0x80, 0x3d,
+	0x92, 0x34, 0x4c, 0xe1, 0x61, 0xcc, 0x8d, 0x20, 0x7b, 0x1e, 0xf8, 0x2e, 0x47, 0xd3, 0x6f, 0x54,
+	0x81, 0x95, 0xc8, 0xa7, 0xe1, 0x66, 0xad, 0x95, 0xc8, 0x37, 0x5f, 0xc0, 0xbd, 0xf9, 0xc1, 0x49,
+	0xce, 0x28, 0x7f, 0x58, 0xd5, 0x6a, 0x19, 0xe2, 0x19, 0x93, 0xcc, 0x63, 0xd8, 0xe8, 0x46, 0x76,
+	0xe4, 0x84, 0x91, 0x33, 0x10, 0x53, 0x55, 0xa1, 0xc0, 0x1c, 0x67, 0x68, 0xcd, 0x8a, 0x45, 0x54,
+	0x83, 0xf2, 0x04, 0x07, 0x03, 0xec, 0x45, 0xce, 0x18, 0xb3, 0x3a, 0x6a, 0x96, 0xac, 0x32, 0xf7,
+	0x00, 0x3a, 0x42, 0x44, 0xdb, 0x00, 0x89, 0x91, 0x6f, 0x4a, 0x49, 0x83, 0xee, 0xf0, 0x9d, 0xc4,
+	0xf6, 0x99, 0xc5, 0x04, 0xf3, 0xbf, 0x2b, 0x80, 0x64, 0xaf, 0x92, 0x84, 0x0d, 0xfc, 0xa9, 0x17,
+	0xff, 0xae, 0x32, 0x01, 0xe9, 0x90, 0x09, 0xa7, 0x2e, 0x27, 0x20, 0x9f, 0x24, 0x53, 0x2e, 0xb6,
+	0x3d, 0xba, 0x98, 0x34, 0x8b, 0x7e, 0x93, 0xd5, 0x77, 0x61, 0x07, 0x8e, 0xed, 0x0d, 0x70, 0x35,
+	0x4b, 0xf5, 0x42, 0x26, 0xb9, 0x09, 0xa3, 0xe1, 0x10, 0x5f, 0x54, 0x73, 0xec, 0x57, 0x83, 0x49,
+	0xe8, 0x31, 0xac, 0x87, 0xb6, 0x3b, 0xa1, 0xbf, 0x05, 0x7c, 0x68, 0x9e, 0x02, 0x2a, 0x4c, 0xdd,
+	0x8b, 0x09, 0xbe, 0x80, 0x35, 0x0e, 0xe4, 0x3c, 0x05, 0x0a, 0x5b, 0x65, 0xca, 0x2e, 0x63, 0xd3,
+	0x21, 0xe3, 0x3a, 0x5e, 0xb5, 0xc8, 0xfc, 0x74, 0x1d, 0x8f, 0x6a, 0xec, 0x59, 0xb5, 0xc4, 0x35,
+	0xf6, 0x8c, 0x78, 0xe2, 0xe2, 0xa1, 0x63, 0x7b, 0x55, 0x60, 0x9e, 0x30, 0x09, 0xbd, 0x52, 0xd3,
+	0x5e, 0xae, 0x65, 0xe6, 0x4f, 0xa8, 0x24, 0xe7, 0x4a, 0x39, 0x48, 0xc1, 0xec, 0xc9, 0x24, 0xf0,
+	0x67, 0x8e, 0x6b, 0x47, 0xb8, 0xba, 0x4a, 0xd7, 0xb0, 0xac, 0x32, 0xff, 0xad, 0x01, 0xb2, 0xa6,
+	0x9e, 0xe7, 0x78, 0x23, 0x92, 0xf3, 0x70, 0xdf, 0xf7, 0xce, 0x9d, 0x11, 0xfa, 0x15, 0x80, 0x3d,
+	0x1a, 0x05, 0x78, 0x64, 0x47, 0x7c, 0xd1, 0xcc, 0x6d, 0xf2, 0x7a, 0x6c, 0xb5, 0x24, 0x20, 0x39,
+	0xcb, 0x2f, 0x1d, 0x6f, 0xe8, 0x5f, 0x9e, 0x85, 0xce, 0x8f, 0xac, 0xac, 0x6b, 0x16, 0x30, 0x55,
+	0xd7, 0xf9, 0x11, 0xa3, 0x4d, 0x28, 0x71, 0x80, 0x1b, 0xd2, 0x0a, 0x65, 0xad, 0x22, 0x53, 0xb4,
+	0x43, 0x72, 0x94, 0xe0, 0x4b, 0xd7, 0x3e, 0xb3, 0xc7, 0x93, 0xef, 0x6d, 0x5e, 0xa7, 0x12, 0xd1,
+	0xd4, 0x89, 0x02, 0xfd, 0x04, 0x0a, 0x91, 0x33, 0xf8, 0x81, 0x8c, 0xcc, 0xb1, 0xfd, 0x45, 0xc4,
+	0x76, 0x68, 0x7e, 0x84, 0xdb, 0x72, 0x08, 0xf1, 0x3a, 0x7e, 0x05, 0xf9, 0x01, 0x8d, 0x86, 0xff,
+	0xd6, 0x6d, 0xcb, 0xfe, 0x5f, 0x8f, 0xf9, 0xf0, 0x96, 0xc5, 0xf1, 0xa8, 0x2a, 0x36, 0x72, 0x7c,
+	0x00, 0x70, 0x79, 0x2f, 0x0f, 0x59, 0x27, 0xc2, 0xae, 0xf9, 0x01, 0x2a, 0x22, 0x03, 0xec, 0xe8,
+	0x78, 0x09, 0x25, 0x91, 0x88, 0xb4, 0x5f, 0xc5, 0x24, 0x61, 0x09, 0x6e, 0xc1, 0x06, 0xf8, 0x13,
+	0xdc, 0x51, 0xe3, 0x49, 0xdb, 0x01, 0xd9, 0x78, 0x07, 0xec, 0x42, 0x9e, 0x0e, 0x63, 0xfb, 0xb1,
+	0xbc, 0x6b, 0xa4, 0xce, 0x4a, 0x9d, 0xb4, 0x38, 0xd2, 0xac, 0x41, 0xbe, 0x87, 0x07, 0xa4, 0x41,
+	0xbb, 0x27, 0x46, 0xb3, 0xbd, 0x1e, 0x23, 0x0e, 0x21, 0xdf, 0xb6, 0xa3, 0xc0, 0x99, 0x91, 0xfd,
+	0x14, 0xf8, 0x97, 0x21, 0x9d, 0x74, 0xcd, 0xa2, 0xdf, 0x44, 0x37, 0xf0, 0xc7, 0x21, 0x2f, 0x30,
+	0xfd, 0x96, 0x98, 0x32, 0x0a, 0xd3, 0x7b, 0xd8, 0x68, 0xf8, 0x51, 0x27, 0xf0, 0x87, 0xd3, 0x81,
+	0x68, 0x3b, 0x6a, 0xa0, 0xd9, 0xbc, 0x2c, 0x48, 0xf6, 0x97, 0x79, 0x65, 0x69, 0x36, 0x41, 0xf4,
+	0xab, 0x2b, 0x8b, 0x11, 0x7d, 0xd2, 0x04, 0xc8, 0xc4, 0xa9, 0xc7, 0x83, 0x26, 0x0e, 0xb3, 0x0f,
+	0x70, 0x97, 0x05, 0xd4, 0x9e, 0x8e, 0x23, 0x67, 0x32, 0xbe, 0xba, 0xc9, 0x15, 0x86, 0x5e, 0xe6,
+	0x4a, 0x8c, 0xe8, 0x9b, 0xaf, 0x61, 0x8d, 0x0b, 0x9c, 0x74, 0x07, 0xf2, 0x2e, 0x55, 0x2c, 0x61,
+	0xe6, 0x08, 0xf3, 0x6b, 0xa8, 0xc4, 0x83, 0x79, 0x0c, 0x3b, 0x73, 0xa7, 0x74, 0xea, 0x68, 0x1e,
+	0xd7, 0x6f, 0xe0, 0x76, 0x03, 0x47, 0x38, 0x70, 0x1d, 0xcf, 0xf6, 0x92, 0x34, 0xd4, 0xa0, 0x3c,
+	0x4c, 0xd4, 0x3c, 0x17, 0xb2, 0xca, 0xb4, 0x60, 0xb5, 0xeb, 0x8f, 0x2f, 0xf0, 0xe7, 0xe7, 0x21,
+	0x29, 0xc9, 0x2f, 0x61, 0x8d, 0x73, 0x0a, 0x37, 0xb4, 0xd9, 0xb2, 0x3a, 0xcf, 0xcc, 0x0e, 0x14,
+	0x5a, 0x5e, 0x84, 0x47, 0x38, 0xb8, 0xa9, 0x39, 0xdb, 0x02, 0xd2, 0x57, 0x4a, 0x9d, 0x59, 0x89,
+	0x98, 0xfb, 0xce, 0x68, 0xae, 0x2d, 0xdb, 0x07, 0xc4, 0x19, 0xdf, 0x39, 0xa1, 0x58, 0x71, 0xbf,
+	0x50, 0x4f, 0xb5, 0xb9, 0xd6, 0x87, 0x0f, 0x10, 0x47, 0x9d, 0xf9, 0x0d, 0xac, 0xc7, 0xba, 0x4f,
+	0xea, 0x9d, 0x62, 0x70, 0x5c, 0x96, 0xbf, 0x6b, 0xb0, 0xd6, 0xf6, 0x87, 0x1d, 0xff, 0x32, 0x69,
+	0xf8, 0xb3, 0x7d, 0x3b, 0xc4, 0xcb, 0x06, 0x53, 0x00, 0xfa, 0x0a, 0x8a, 0x78, 0x36, 0xf1, 0x3d,
+	0xec, 0xa5, 0x36, 0x84, 0x31, 0x58, 0x80, 0x48, 0x68, 0xae, 0x3f, 0x9c, 0x8e, 0xa7, 0x61, 0x35,
+	0xb3, 0x18, 0x1f, 0x63, 0x4c, 0x1f, 0x36, 0xda, 0xfe, 0xb0, 0xe5, 0x5d, 0xe0, 0x20, 0x14, 0xd5,
+	0x7f, 0xa6, 0xf4, 0x2e, 0x8b, 0x82, 0x63, 0x10, 0x79, 0xc2, 0x95, 0x4f, 0x98, 0xf0, 0x3b, 0x40,
+	0xcd, 0x59, 0x84, 0xbd, 0x21, 0x1e, 0x1e, 0xec, 0x37, 0xe2, 0x19, 0x1f, 0x25, 0xeb, 0x2d, 0x75,
+	0xb8, 0x66, 0xa3, 0x47, 0xc9, 0x82, 0x4b, 0x87, 0xf4, 0xcd, 0xbf, 0x68, 0x70, 0x5b, 0x21, 0xe7,
+	0xc5, 0xfa, 0x19, 0x64, 0x46, 0x83, 0xe1, 0x32, 0x7e, 0x62, 0x27, 0x33, 0xcc, 0x96, 0xce, 0x30,
+	0x23, 0x90, 0xab, 0x65, 0x79, 0xd5, 0xae, 0xcc, 0x7f, 0x68, 0x90, 0x3d, 0xf5, 0x1c, 0xda, 0xa5,
+	0x79, 0x36, 0xef, 0xe9, 0x4a, 0x16, 0xfd, 0xa6, 0xfd, 0xc5, 0x95, 0xdb, 0xf7, 0xc7, 0xfc, 0x72,
+	0xc4, 0x25, 0xf4, 0x00, 0x4a, 0x43, 0xc7, 0xc5, 0x5e, 0x48, 0xda, 0xe8, 0x0c, 0x35, 0x25, 0x0a,
+	0xd2, 0x84, 0xd9, 0x63, 0xc7, 0x0e, 0x71, 0x58, 0xcd, 0xd2, 0x76, 0x39, 0x16, 0x09, 0xdf, 0xb9,
+	0x4d, 0x76, 0x4f, 0xdc, 0xaf, 0x30, 0x89, 0xe8, 0xfd, 0xf3, 0xf3, 0x10, 0x47, 0xbc, 0x4d, 0xe1,
+	0x92, 0xf9, 0x16, 0x2a, 0xfb, 0x3e, 0x29, 0xb6, 0xd8, 0x0a, 0xe2, 0xd4, 0xd1, 0xa4, 0x53, 0x47,
+	0x74, 0x98, 0xcc, 0x4b, 0xb9, 0xc3, 0x64, 0xce, 0x91, 0x0e, 0xd3, 0x87, 0x75, 0xc1, 0x95, 0x1c,
+	0x4a, 0x29, 0x64, 0x3f, 0x95, 0xc8, 0xca, 0xbb, 0xba, 0x9c, 0x37, 0x92, 0x28, 0x4e, 0x5f, 0x13,
+	0xf4, 0x69, 0x18, 0xd6, 0xd2, 0xea, 0x64, 0x13, 0x13, 0x59, 0x9c, 0xeb, 0x4a, 0xe2, 0xb4, 0xb9,
+	0xc4, 0x99, 0xaf, 0x61, 0x43, 0x1a, 0xc1, 0x9d, 0xfc, 0x39, 0xe4, 0xa6, 0x44, 0xc1, 0xb7, 0xfe,
+	0xf5, 0xb9, 0x98, 0xd9, 0xfc, 0x97, 0x06, 0xeb, 0x4d, 0x12, 0x81, 0x74, 0xa1, 0xdb, 0x06, 0xc0,
+	0xb3, 0x49, 0x80, 0x43, 0x69, 0x3e, 0x49, 0x83, 0x0e, 0xa1, 0x44, 0x1b, 0xc4, 0xfe, 0x58, 0x1c,
+	0xc1, 0x3b, 0x32, 0xff, 0x1c, 0xdf, 0xf3, 0x5e, 0x0c, 0x6e, 0x7a, 0x51, 0x70, 0x65, 0x25, 0x83,
+	0x8d, 0xaf, 0xa1, 0xa2, 0x1a, 0x49, 0x8f, 0xf8, 0x03, 0xbe, 0xe2, 0x93, 0x92, 0xcf, 0xf4, 0x8e,
+	0xe1, 0xb7, 0x2b, 0xaf, 0x34, 0x72, 0x23, 0x49, 0xa6, 0x5a, 0x7e, 0x18, 0xee, 0xfc, 0x4d, 0x83,
+	0x92, 0xb8, 0xa6, 0xa1, 0xfb, 0x70, 0xf7, 0xb8, 0xd3, 0xb4, 0xea, 0x27, 0xad, 0xe3, 0xa3, 0xb3,
+	0xd3, 0xa3, 0x6e, 0xa7, 0xb9, 0xdf, 0x7a, 0xd3, 0x6a, 0x36, 0xf4, 0x5b, 0xa8, 0x00, 0x99, 0x7a,
+	0xa3, 0xa1, 0x6b, 0x68, 0x15, 0x8a, 0xdd, 0xd3, 0xbd, 0x13, 0xab, 0xbe, 0x7f, 0xa2, 0xaf, 0x10,
+	0xa9, 0x7d, 0xfa, 0xee, 0xa4, 0xd5, 0x79, 0xf7, 0x47, 0x3d, 0x83, 0x00, 0xf2, 0x8d, 0x56, 0xaf,
+	0xd5, 0x68, 0xea, 0x59, 0xf2, 0xdd, 0x3e, 0x6e, 0x9c, 0xbe, 0x3b, 0xd6, 0x73, 0xa8, 0x04, 0xb9,
+	0xce, 0xf1, 0xfb, 0xa6, 0xa5, 0xe7, 0x09, 0x4f, 0xbb, 0x75, 0xa4, 0x17, 0xe8, 0x47, 0xfd, 0x0f,
+	0x7a, 0x11, 0x95, 0xa1, 0x50, 0xef, 0x35, 0xad, 0xfa, 0x41, 0x53, 0x2f, 0xed, 0xfc, 0x55, 0x83,
+	0x92, 0x68, 0x55, 0x88, 0x3f, 0xf5, 0x83, 0x03, 0xab, 0x79, 0x50, 0x3f, 0x69, 0xce, 0xf9, 0xb3,
+	0x01, 0x6b, 0x89, 0x89, 0x10, 0x69, 0x73, 0xaa, 0xd6, 0x91, 0xbe, 0x82, 0x10, 0x54, 0x24, 0x55,
+	0xb3, 0x7e, 0xa4, 0x67, 0x54, 0x58, 0xf7, 0xb4, 0xad, 0x67, 0x55, 0x58, 0xf3, 0x7d, 0xbb, 0xae,
+	0xe7, 0x76, 0xff, 0xb3, 0x2e, 0x3f, 0x11, 0x75, 0x71, 0x70, 0xe1, 0x0c, 0x30, 0x3a, 0x86, 0xd5,
+	0x58, 0x89, 0xbb, 0x53, 0x17, 0x6d, 0x2d, 0x78, 0x1e, 0x62, 0x25, 0x36, 0xb6, 0x17, 0x99, 0x59,
+	0x59, 0xcc, 0x5b, 0xa8, 0x07, 0x9b, 0x82, 0x90, 0xde, 0xd5, 0x1a, 0x78, 0xe0, 0xbb, 0x13, 0x3f,
+	0x74, 0x68, 0x45, 0xaa, 0xd7, 0x5f, 0x83, 0x38, 0xf5, 0xfd, 0x14, 0x4b, 0xcc, 0xfa, 0x42, 0x43,
+	0x0d, 0x28, 0xf0, 0x4b, 0x2c, 0x52, 0xfa, 0x40, 0xf5, 0x0e, 0x6c, 0x6c, 0xa6, 0xda, 0x84, 0x77,
+	0x6f, 0xa1, 0x24, 0x2e, 0xac, 0xe8, 0x81, 0x8c, 0x9d, 0xbf, 0xf3, 0x1a, 0x5b, 0x0b, 0xac, 0x82,
+	0xeb, 0x03, 0x54, 0xd4, 0x4b, 0x29, 0x7a, 0x24, 0x0f, 0x49, 0xbd, 0xed, 0x1a, 0xe6, 0x32, 0x88,
+	0x14, 0xee, 0xb1, 0xf4, 0x00, 0xc3, 0x9f, 0x99, 0xd4, 0xb8, 0xd5, 0x77, 0x41, 0x63, 0x33, 0xd5,
+	0x16, 0x13, 0x3e, 0xd1, 0x50, 0x0f, 0x36, 0xf6, 0x7d, 0x77, 0x32, 0x8d, 0x70, 0x72, 0x03, 0x55,
+	0xab, 0x7d, 0xed, 0xbe, 0x6c, 0x6c, 0x2f, 0x32, 0x4b, 0xbc, 0x87, 0x50, 0xe0, 0x6f, 0x5b, 0xaa,
+	0x7f, 0xea, 0xd3, 0x9d, 0xb1, 0x99, 0x6a, 0x4b, 0x78, 0x5e, 0x68, 0xe8, 0x3d, 0xac, 0xca, 0x97,
+	0x03, 0xf4, 0x70, 0xd1, 0xad, 0x26, 0xe6, 0xac, 0x2d, 0x06, 0x28, 0xc4, 0x6d, 0x80, 0xe4, 0x4d,
+	0x6d, 0x2e, 0xe6, 0xf9, 0x67, 0x43, 0x63, 0x7b, 0x91, 0x59, 0x5e, 0x43, 0xa2, 0x34, 0xea, 0x1a,
+	0x9a, 0x7f, 0x32, 0x33, 0xb6, 0x16, 0x58, 0x05, 0x57, 0x57, 0xda, 0x7e, 0x7b, 0xce, 0x48, 0x8d,
+	0x39, 0xe5, 0x89, 0xca, 0xa8, 0x2d, 0x06, 0x08, 0xd2, 0x06, 0x64, 0x0e, 0xf6, 0x1b, 0x68, 0x3b,
+	0xe5, 0x4c, 0x97, 0xfa, 0x46, 0x63, 0x33, 0xc5, 0xae, 0xb2, 0xbc, 0xdb, 0x6f, 0x7f, 0x2e, 0xcb,
+	0x1e, 0xe4, 0x59, 0xb3, 0x88, 0x94, 0xfd, 0xad, 0x34, 0x90, 0x37, 0x71, 0xbc, 0x05, 0x48, 0xda,
+	0x3a, 0xb5, 0x7e, 0xd7, 0xda, 0xbd, 0x9b, 0xb8, 0x3a, 0x50, 0x96, 0x9a, 0x2a, 0x35, 0xba, 0xeb,
+	0xad, 0x9c, 0xf1, 0x70, 0xa1, 0x5d, 0x30, 0xb6, 0x01, 0x92, 0xcb, 0x9a, 0xea, 0xdd, 0xb5, 0xdb,
+	0xa1, 0xb1, 0xbd, 0xc8, 0x2c, 0xe8, 0x7e, 0x0f, 0x15, 0xf5, 0x36, 0xa7, 0xfe, 0xaa, 0xa4, 0xde,
+	0xf4, 0x0c, 0xe3, 0x3a, 0x44, 0xa9, 0x64, 0xe9, 0x24, 0xb0, 0xbd, 0x70, 0xe2, 0x93, 0x97, 0xbf,
+	0x34, 0xe8, 0xa7, 0xb0, 0x7c, 0x0b, 0x65, 0xe9, 0x3a, 0xb6, 0x8c, 0x47, 0x49, 0x5a, 0xca, 0x15,
+	0x8e, 0x2e, 0x8b, 0x42, 0x5c, 0xcf, 0xff, 0xdb, 0xa1, 0x6f, 0x20, 0x47, 0xaf, 0x64, 0xea, 0x99,
+	0x22, 0xdf, 0xfc, 0x8c, 0xfb, 0x29, 0x16, 0x31, 0xfe, 0x00, 0x8a, 0x71, 0x5b, 0x81, 0x36, 0x97,
+	0xf4, 0x35, 0xc6, 0x83, 0x74, 0xa3, 0x94, 0xdf, 0x02, 0xef, 0x1d, 0xd5, 0x9f, 0x40, 0xb5, 0x39,
+	0x35, 0x36, 0x53, 0x6d, 0xf2, 0xcf, 0x8a, 0x68, 0xef, 0xd4, 0x9f, 0x95, 0xf9, 0x3e, 0xd1, 0xd8,
+	0x5a, 0x60, 0x8d, 0xb9, 0xf6, 0x2a, 0xdf, 0xad, 0xca, 0xff, 0x6b, 0xf5, 0xf3, 0xf4, 0xdf, 0xac,
+	0x97, 0xff, 0x1b, 0x00, 0xcb, 0x68, 0xb9, 0x9b, 0xf9, 0x1a, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// CalculatorServiceClient is the client API for CalculatorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CalculatorServiceClient interface {
+	//Unary
+	//A sum that overflows int64 returns OUT_OF_RANGE.
+	CalculateSum(ctx context.Context, in *CalculatorRequest, opts ...grpc.CallOption) (*CalculatorResponse, error)
+	//Streaming Request
+	//Streams the prime factors of a positive number in ascending order.
+	//Zero or negative numbers return INVALID_ARGUMENT. A factorization
+	//that outlives the server's time budget returns DEADLINE_EXCEEDED.
+	CalculatePrimeDecomposition(ctx context.Context, in *PrimeRequest, opts ...grpc.CallOption) (CalculatorService_CalculatePrimeDecompositionClient, error)
+	//Unary primality test, exact for every 64-bit number.
+	IsPrime(ctx context.Context, in *IsPrimeRequest, opts ...grpc.CallOption) (*IsPrimeResponse, error)
+	//Unary smallest prime greater than number.
+	//Returns OUT_OF_RANGE when that prime does not fit in 64 bits.
+	NextPrime(ctx context.Context, in *NextPrimeRequest, opts ...grpc.CallOption) (*NextPrimeResponse, error)
+	//Streams the primes between from and to in ascending order, in batches.
+	//Returns INVALID_ARGUMENT when to is less than from.
+	GeneratePrimes(ctx context.Context, in *GeneratePrimesRequest, opts ...grpc.CallOption) (CalculatorService_GeneratePrimesClient, error)
+	//Client side streaming
+	//A running total that overflows int64 returns OUT_OF_RANGE and an
+	//empty stream returns INVALID_ARGUMENT.
+	CalculateAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_CalculateAverageClient, error)
+	//Client side streaming statistics over doubles.
+	//An empty stream, non-finite numbers or percentiles outside 0-100
+	//return INVALID_ARGUMENT.
+	ComputeStatistics(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeStatisticsClient, error)
+	//Bi Directional Streaming
+	FindMax(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_FindMaxClient, error)
+	//Bi Directional windowed statistics.
+	//A bad config, a number before the config or a non-finite number
+	//returns INVALID_ARGUMENT.
+	RunningStats(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_RunningStatsClient, error)
+	//Unary Square Root
+	//Error handling
+	//This RPC will throw an exception is the number sent is less
+	//than 0.
+	//The error sent is type INVALID_ARGUMENT
+	SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error)
+	//Unary left <operation> right.
+	//Two int operands give an int result: DIVIDE truncates toward zero and
+	//POWER needs a non-negative exponent. A double operand makes the whole
+	//calculation double.
+	//Division or modulo by zero returns INVALID_ARGUMENT.
+	//Integer overflow or a non-finite double result returns OUT_OF_RANGE.
+	//AVERAGE is only supported by CalculateBig.
+	Calculate(ctx context.Context, in *CalculateRequest, opts ...grpc.CallOption) (*CalculateResponse, error)
+	//Unary arbitrary precision arithmetic on decimal strings.
+	//ADD, MULTIPLY and AVERAGE take one or more operands; POWER takes a
+	//base and an integer exponent.
+	//Malformed operands or an unsupported operation return INVALID_ARGUMENT.
+	//Results larger than the server's size limit return OUT_OF_RANGE.
+	CalculateBig(ctx context.Context, in *CalculateBigRequest, opts ...grpc.CallOption) (*CalculateBigResponse, error)
+	//Unary number theory. Results are int_value when every input is, and
+	//big_value as soon as one input is; an int_value result that does not
+	//fit returns OUT_OF_RANGE. Missing or malformed inputs, and a modulus
+	//below one, return INVALID_ARGUMENT.
+	//GCD and LCM take one or more numbers and return a non-negative result.
+	GCD(ctx context.Context, in *IntegerListRequest, opts ...grpc.CallOption) (*IntegerResponse, error)
+	LCM(ctx context.Context, in *IntegerListRequest, opts ...grpc.CallOption) (*IntegerResponse, error)
+	//base^exponent mod modulus. A negative exponent uses the inverse of
+	//base, returning FAILED_PRECONDITION when there is none.
+	ModPow(ctx context.Context, in *ModPowRequest, opts ...grpc.CallOption) (*IntegerResponse, error)
+	//Returns FAILED_PRECONDITION when number and modulus are not coprime.
+	ModInverse(ctx context.Context, in *ModInverseRequest, opts ...grpc.CallOption) (*IntegerResponse, error)
+	ExtendedGCD(ctx context.Context, in *ExtendedGCDRequest, opts ...grpc.CallOption) (*ExtendedGCDResponse, error)
+	//Unary linear algebra on dense vectors and matrices of up to 512 rows
+	//and columns. Shape mismatches, values that do not match rows*cols and
+	//non-finite numbers return INVALID_ARGUMENT. Inverse and Solve return
+	//FAILED_PRECONDITION for a singular matrix; Determinant returns zero.
+	//Results that overflow a double return OUT_OF_RANGE.
+	DotProduct(ctx context.Context, in *DotProductRequest, opts ...grpc.CallOption) (*DotProductResponse, error)
+	MatrixMultiply(ctx context.Context, in *MatrixMultiplyRequest, opts ...grpc.CallOption) (*MatrixResponse, error)
+	Transpose(ctx context.Context, in *MatrixRequest, opts ...grpc.CallOption) (*MatrixResponse, error)
+	Determinant(ctx context.Context, in *MatrixRequest, opts ...grpc.CallOption) (*DeterminantResponse, error)
+	Inverse(ctx context.Context, in *MatrixRequest, opts ...grpc.CallOption) (*MatrixResponse, error)
+	//Solves a x = b for x.
+	Solve(ctx context.Context, in *SolveRequest, opts ...grpc.CallOption) (*SolveResponse, error)
+	//Unary expression evaluation, e.g. "(a + b) * sqrt(c) / 2" with
+	//variables a, b and c bound in the request.
+	//Supports + - * / ^, parentheses, unary minus and the functions sqrt,
+	//abs, log, exp, sin, cos, min and max.
+	//Syntax errors, unknown variables, division by zero and arguments
+	//outside a function's domain return INVALID_ARGUMENT with the 1-based
+	//position in the expression.
+	//A non-finite result returns OUT_OF_RANGE.
+	Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error)
+	//Unary unit conversion between units of the same dimension: length,
+	//mass, time, temperature, data and speed, plus whatever the server's
+	//unit config adds.
+	//Unknown units, mismatched dimensions and a non-finite value return
+	//INVALID_ARGUMENT. A result that overflows returns OUT_OF_RANGE.
+	Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error)
+	//Lists the known units by dimension and size. An unknown dimension
+	//returns INVALID_ARGUMENT.
+	ListUnits(ctx context.Context, in *ListUnitsRequest, opts ...grpc.CallOption) (*ListUnitsResponse, error)
+}
+
+type calculatorServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCalculatorServiceClient(cc *grpc.ClientConn) CalculatorServiceClient {
+	return &calculatorServiceClient{cc}
+}
+
+func (c *calculatorServiceClient) CalculateSum(ctx context.Context, in *CalculatorRequest, opts ...grpc.CallOption) (*CalculatorResponse, error) {
+	out := new(CalculatorResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/CalculateSum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) CalculatePrimeDecomposition(ctx context.Context, in *PrimeRequest, opts ...grpc.CallOption) (CalculatorService_CalculatePrimeDecompositionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[0], "/calculator.CalculatorService/CalculatePrimeDecomposition", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceCalculatePrimeDecompositionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CalculatorService_CalculatePrimeDecompositionClient interface {
+	Recv() (*PrimeResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceCalculatePrimeDecompositionClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceCalculatePrimeDecompositionClient) Recv() (*PrimeResponse, error) {
+	m := new(PrimeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) IsPrime(ctx context.Context, in *IsPrimeRequest, opts ...grpc.CallOption) (*IsPrimeResponse, error) {
+	out := new(IsPrimeResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/IsPrime", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) NextPrime(ctx context.Context, in *NextPrimeRequest, opts ...grpc.CallOption) (*NextPrimeResponse, error) {
+	out := new(NextPrimeResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/NextPrime", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) GeneratePrimes(ctx context.Context, in *GeneratePrimesRequest, opts ...grpc.CallOption) (CalculatorService_GeneratePrimesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[1], "/calculator.CalculatorService/GeneratePrimes", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceGeneratePrimesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CalculatorService_GeneratePrimesClient interface {
+	Recv() (*GeneratePrimesResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceGeneratePrimesClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceGeneratePrimesClient) Recv() (*GeneratePrimesResponse, error) {
+	m := new(GeneratePrimesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) CalculateAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_CalculateAverageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[2], "/calculator.CalculatorService/CalculateAverage", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceCalculateAverageClient{stream}
+	return x, nil
+}
+
+type CalculatorService_CalculateAverageClient interface {
+	Send(*AverageRequest) error
+	CloseAndRecv() (*AverageResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceCalculateAverageClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceCalculateAverageClient) Send(m *AverageRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceCalculateAverageClient) CloseAndRecv() (*AverageResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(AverageResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) ComputeStatistics(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeStatisticsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[3], "/calculator.CalculatorService/ComputeStatistics", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceComputeStatisticsClient{stream}
+	return x, nil
+}
+
+type CalculatorService_ComputeStatisticsClient interface {
+	Send(*StatisticsRequest) error
+	CloseAndRecv() (*StatisticsResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceComputeStatisticsClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceComputeStatisticsClient) Send(m *StatisticsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceComputeStatisticsClient) CloseAndRecv() (*StatisticsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(StatisticsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) FindMax(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_FindMaxClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[4], "/calculator.CalculatorService/FindMax", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceFindMaxClient{stream}
+	return x, nil
+}
+
+type CalculatorService_FindMaxClient interface {
+	Send(*FindMaxRequest) error
+	Recv() (*FindMaxResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceFindMaxClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceFindMaxClient) Send(m *FindMaxRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceFindMaxClient) Recv() (*FindMaxResponse, error) {
+	m := new(FindMaxResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) RunningStats(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_RunningStatsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[5], "/calculator.CalculatorService/RunningStats", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceRunningStatsClient{stream}
+	return x, nil
+}
+
+type CalculatorService_RunningStatsClient interface {
+	Send(*RunningStatsRequest) error
+	Recv() (*RunningStatsResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceRunningStatsClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceRunningStatsClient) Send(m *RunningStatsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceRunningStatsClient) Recv() (*RunningStatsResponse, error) {
+	m := new(RunningStatsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error) {
+	out := new(SquareRootResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/SquareRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) Calculate(ctx context.Context, in *CalculateRequest, opts ...grpc.CallOption) (*CalculateResponse, error) {
+	out := new(CalculateResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Calculate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) CalculateBig(ctx context.Context, in *CalculateBigRequest, opts ...grpc.CallOption) (*CalculateBigResponse, error) {
+	out := new(CalculateBigResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/CalculateBig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) GCD(ctx context.Context, in *IntegerListRequest, opts ...grpc.CallOption) (*IntegerResponse, error) {
+	out := new(IntegerResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/GCD", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) LCM(ctx context.Context, in *IntegerListRequest, opts ...grpc.CallOption) (*IntegerResponse, error) {
+	out := new(IntegerResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/LCM", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ModPow(ctx context.Context, in *ModPowRequest, opts ...grpc.CallOption) (*IntegerResponse, error) {
+	out := new(IntegerResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/ModPow", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ModInverse(ctx context.Context, in *ModInverseRequest, opts ...grpc.CallOption) (*IntegerResponse, error) {
+	out := new(IntegerResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/ModInverse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ExtendedGCD(ctx context.Context, in *ExtendedGCDRequest, opts ...grpc.CallOption) (*ExtendedGCDResponse, error) {
+	out := new(ExtendedGCDResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/ExtendedGCD", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) DotProduct(ctx context.Context, in *DotProductRequest, opts ...grpc.CallOption) (*DotProductResponse, error) {
+	out := new(DotProductResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/DotProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) MatrixMultiply(ctx context.Context, in *MatrixMultiplyRequest, opts ...grpc.CallOption) (*MatrixResponse, error) {
+	out := new(MatrixResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/MatrixMultiply", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) Transpose(ctx context.Context, in *MatrixRequest, opts ...grpc.CallOption) (*MatrixResponse, error) {
+	out := new(MatrixResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Transpose", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) Determinant(ctx context.Context, in *MatrixRequest, opts ...grpc.CallOption) (*DeterminantResponse, error) {
+	out := new(DeterminantResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Determinant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) Inverse(ctx context.Context, in *MatrixRequest, opts ...grpc.CallOption) (*MatrixResponse, error) {
+	out := new(MatrixResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Inverse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) Solve(ctx context.Context, in *SolveRequest, opts ...grpc.CallOption) (*SolveResponse, error) {
+	out := new(SolveResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Solve", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error) {
+	out := new(EvaluateResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Evaluate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error) {
+	out := new(ConvertResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Convert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ListUnits(ctx context.Context, in *ListUnitsRequest, opts ...grpc.CallOption) (*ListUnitsResponse, error) {
+	out := new(ListUnitsResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/ListUnits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CalculatorServiceServer is the server API for CalculatorService service.
+type CalculatorServiceServer interface {
+	//Unary
+	//A sum that overflows int64 returns OUT_OF_RANGE.
+	CalculateSum(context.Context, *CalculatorRequest) (*CalculatorResponse, error)
+	//Streaming Request
+	//Streams the prime factors of a positive number in ascending order.
+	//Zero or negative numbers return INVALID_ARGUMENT. A factorization
+	//that outlives the server's time budget returns DEADLINE_EXCEEDED.
+	CalculatePrimeDecomposition(*PrimeRequest, CalculatorService_CalculatePrimeDecompositionServer) error
+	//Unary primality test, exact for every 64-bit number.
+	IsPrime(context.Context, *IsPrimeRequest) (*IsPrimeResponse, error)
+	//Unary smallest prime greater than number.
+	//Returns OUT_OF_RANGE when that prime does not fit in 64 bits.
+	NextPrime(context.Context, *NextPrimeRequest) (*NextPrimeResponse, error)
+	//Streams the primes between from and to in ascending order, in batches.
+	//Returns INVALID_ARGUMENT when to is less than from.
+	GeneratePrimes(*GeneratePrimesRequest, CalculatorService_GeneratePrimesServer) error
+	//Client side streaming
+	//A running total that overflows int64 returns OUT_OF_RANGE and an
+	//empty stream returns INVALID_ARGUMENT.
+	CalculateAverage(CalculatorService_CalculateAverageServer) error
+	//Client side streaming statistics over doubles.
+	//An empty stream, non-finite numbers or percentiles outside 0-100
+	//return INVALID_ARGUMENT.
+	ComputeStatistics(CalculatorService_ComputeStatisticsServer) error
+	//Bi Directional Streaming
+	FindMax(CalculatorService_FindMaxServer) error
+	//Bi Directional windowed statistics.
+	//A bad config, a number before the config or a non-finite number
+	//returns INVALID_ARGUMENT.
+	RunningStats(CalculatorService_RunningStatsServer) error
+	//Unary Square Root
+	//Error handling
+	//This RPC will throw an exception is the number sent is less
+	//than 0.
+	//The error sent is type INVALID_ARGUMENT
+	SquareRoot(context.Context, *SquareRootRequest) (*SquareRootResponse, error)
+	//Unary left <operation> right.
+	//Two int operands give an int result: DIVIDE truncates toward zero and
+	//POWER needs a non-negative exponent. A double operand makes the whole
+	//calculation double.
+	//Division or modulo by zero returns INVALID_ARGUMENT.
+	//Integer overflow or a non-finite double result returns OUT_OF_RANGE.
+	//AVERAGE is only supported by CalculateBig.
+	Calculate(context.Context, *CalculateRequest) (*CalculateResponse, error)
+	//Unary arbitrary precision arithmetic on decimal strings.
+	//ADD, MULTIPLY and AVERAGE take one or more operands; POWER takes a
+	//base and an integer exponent.
+	//Malformed operands or an unsupported operation return INVALID_ARGUMENT.
+	//Results larger than the server's size limit return OUT_OF_RANGE.
+	CalculateBig(context.Context, *CalculateBigRequest) (*CalculateBigResponse, error)
+	//Unary number theory. Results are int_value when every input is, and
+	//big_value as soon as one input is; an int_value result that does not
+	//fit returns OUT_OF_RANGE. Missing or malformed inputs, and a modulus
+	//below one, return INVALID_ARGUMENT.
+	//GCD and LCM take one or more numbers and return a non-negative result.
+	GCD(context.Context, *IntegerListRequest) (*IntegerResponse, error)
+	LCM(context.Context, *IntegerListRequest) (*IntegerResponse, error)
+	//base^exponent mod modulus. A negative exponent uses the inverse of
+	//base, returning FAILED_PRECONDITION when there is none.
+	ModPow(context.Context, *ModPowRequest) (*IntegerResponse, error)
+	//Returns FAILED_PRECONDITION when number and modulus are not coprime.
+	ModInverse(context.Context, *ModInverseRequest) (*IntegerResponse, error)
+	ExtendedGCD(context.Context, *ExtendedGCDRequest) (*ExtendedGCDResponse, error)
+	//Unary linear algebra on dense vectors and matrices of up to 512 rows
+	//and columns. Shape mismatches, values that do not match rows*cols and
+	//non-finite numbers return INVALID_ARGUMENT. Inverse and Solve return
+	//FAILED_PRECONDITION for a singular matrix; Determinant returns zero.
+	//Results that overflow a double return OUT_OF_RANGE.
+	DotProduct(context.Context, *DotProductRequest) (*DotProductResponse, error)
+	MatrixMultiply(context.Context, *MatrixMultiplyRequest) (*MatrixResponse, error)
+	Transpose(context.Context, *MatrixRequest) (*MatrixResponse, error)
+	Determinant(context.Context, *MatrixRequest) (*DeterminantResponse, error)
+	Inverse(context.Context, *MatrixRequest) (*MatrixResponse, error)
+	//Solves a x = b for x.
+	Solve(context.Context, *SolveRequest) (*SolveResponse, error)
+	//Unary expression evaluation, e.g. "(a + b) * sqrt(c) / 2" with
+	//variables a, b and c bound in the request.
+	//Supports + - * / ^, parentheses, unary minus and the functions sqrt,
+	//abs, log, exp, sin, cos, min and max.
+	//Syntax errors, unknown variables, division by zero and arguments
+	//outside a function's domain return INVALID_ARGUMENT with the 1-based
+	//position in the expression.
+	//A non-finite result returns OUT_OF_RANGE.
+	Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error)
+	//Unary unit conversion between units of the same dimension: length,
+	//mass, time, temperature, data and speed, plus whatever the server's
+	//unit config adds.
+	//Unknown units, mismatched dimensions and a non-finite value return
+	//INVALID_ARGUMENT. A result that overflows returns OUT_OF_RANGE.
+	Convert(context.Context, *ConvertRequest) (*ConvertResponse, error)
+	//Lists the known units by dimension and size. An unknown dimension
+	//returns INVALID_ARGUMENT.
+	ListUnits(context.Context, *ListUnitsRequest) (*ListUnitsResponse, error)
+}
+
+// UnimplementedCalculatorServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedCalculatorServiceServer struct {
+}
+
+func (*UnimplementedCalculatorServiceServer) CalculateSum(ctx context.Context, req *CalculatorRequest) (*CalculatorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CalculateSum not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) CalculatePrimeDecomposition(req *PrimeRequest, srv CalculatorService_CalculatePrimeDecompositionServer) error {
+	return status.Errorf(codes.Unimplemented, "method CalculatePrimeDecomposition not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) IsPrime(ctx context.Context, req *IsPrimeRequest) (*IsPrimeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsPrime not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) NextPrime(ctx context.Context, req *NextPrimeRequest) (*NextPrimeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NextPrime not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) GeneratePrimes(req *GeneratePrimesRequest, srv CalculatorService_GeneratePrimesServer) error {
+	return status.Errorf(codes.Unimplemented, "method GeneratePrimes not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) CalculateAverage(srv CalculatorService_CalculateAverageServer) error {
+	return status.Errorf(codes.Unimplemented, "method CalculateAverage not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) ComputeStatistics(srv CalculatorService_ComputeStatisticsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ComputeStatistics not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) FindMax(srv CalculatorService_FindMaxServer) error {
+	return status.Errorf(codes.Unimplemented, "method FindMax not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) RunningStats(srv CalculatorService_RunningStatsServer) error {
+	return status.Errorf(codes.Unimplemented, "method RunningStats not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) SquareRoot(ctx context.Context, req *SquareRootRequest) (*SquareRootResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SquareRoot not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) Calculate(ctx context.Context, req *CalculateRequest) (*CalculateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Calculate not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) CalculateBig(ctx context.Context, req *CalculateBigRequest) (*CalculateBigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CalculateBig not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) GCD(ctx context.Context, req *IntegerListRequest) (*IntegerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GCD not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) LCM(ctx context.Context, req *IntegerListRequest) (*IntegerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LCM not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) ModPow(ctx context.Context, req *ModPowRequest) (*IntegerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModPow not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) ModInverse(ctx context.Context, req *ModInverseRequest) (*IntegerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModInverse not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) ExtendedGCD(ctx context.Context, req *ExtendedGCDRequest) (*ExtendedGCDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtendedGCD not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) DotProduct(ctx context.Context, req *DotProductRequest) (*DotProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DotProduct not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) MatrixMultiply(ctx context.Context, req *MatrixMultiplyRequest) (*MatrixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatrixMultiply not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) Transpose(ctx context.Context, req *MatrixRequest) (*MatrixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transpose not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) Determinant(ctx context.Context, req *MatrixRequest) (*DeterminantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Determinant not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) Inverse(ctx context.Context, req *MatrixRequest) (*MatrixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Inverse not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) Solve(ctx context.Context, req *SolveRequest) (*SolveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Solve not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) Evaluate(ctx context.Context, req *EvaluateRequest) (*EvaluateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Evaluate not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) Convert(ctx context.Context, req *ConvertRequest) (*ConvertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Convert not implemented")
+}
+func (*UnimplementedCalculatorServiceServer) ListUnits(ctx context.Context, req *ListUnitsRequest) (*ListUnitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUnits not implemented")
+}
+
+func RegisterCalculatorServiceServer(s *grpc.Server, srv CalculatorServiceServer) {
+	s.RegisterService(&_CalculatorService_serviceDesc, srv)
+}
+
+func _CalculatorService_CalculateSum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculatorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).CalculateSum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/CalculateSum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).CalculateSum(ctx, req.(*CalculatorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_CalculatePrimeDecomposition_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PrimeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalculatorServiceServer).CalculatePrimeDecomposition(m, &calculatorServiceCalculatePrimeDecompositionServer{stream})
+}
+
+type CalculatorService_CalculatePrimeDecompositionServer interface {
+	Send(*PrimeResponse) error
+	grpc.ServerStream
+}
+
+type calculatorServiceCalculatePrimeDecompositionServer struct {
+	grpc.ServerStream
 }
 
 func (x *calculatorServiceCalculatePrimeDecompositionServer) Send(m *PrimeResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _CalculatorService_CalculateAverage_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(CalculatorServiceServer).CalculateAverage(&calculatorServiceCalculateAverageServer{stream})
+func _CalculatorService_IsPrime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsPrimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).IsPrime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/IsPrime",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).IsPrime(ctx, req.(*IsPrimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_NextPrime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NextPrimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).NextPrime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/NextPrime",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).NextPrime(ctx, req.(*NextPrimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_GeneratePrimes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GeneratePrimesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalculatorServiceServer).GeneratePrimes(m, &calculatorServiceGeneratePrimesServer{stream})
+}
+
+type CalculatorService_GeneratePrimesServer interface {
+	Send(*GeneratePrimesResponse) error
+	grpc.ServerStream
+}
+
+type calculatorServiceGeneratePrimesServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceGeneratePrimesServer) Send(m *GeneratePrimesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CalculatorService_CalculateAverage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).CalculateAverage(&calculatorServiceCalculateAverageServer{stream})
+}
+
+type CalculatorService_CalculateAverageServer interface {
+	SendAndClose(*AverageResponse) error
+	Recv() (*AverageRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceCalculateAverageServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceCalculateAverageServer) SendAndClose(m *AverageResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceCalculateAverageServer) Recv() (*AverageRequest, error) {
+	m := new(AverageRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CalculatorService_ComputeStatistics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).ComputeStatistics(&calculatorServiceComputeStatisticsServer{stream})
+}
+
+type CalculatorService_ComputeStatisticsServer interface {
+	SendAndClose(*StatisticsResponse) error
+	Recv() (*StatisticsRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceComputeStatisticsServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceComputeStatisticsServer) SendAndClose(m *StatisticsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceComputeStatisticsServer) Recv() (*StatisticsRequest, error) {
+	m := new(StatisticsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CalculatorService_FindMax_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).FindMax(&calculatorServiceFindMaxServer{stream})
+}
+
+type CalculatorService_FindMaxServer interface {
+	Send(*FindMaxResponse) error
+	Recv() (*FindMaxRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceFindMaxServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceFindMaxServer) Send(m *FindMaxResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceFindMaxServer) Recv() (*FindMaxRequest, error) {
+	m := new(FindMaxRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CalculatorService_RunningStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).RunningStats(&calculatorServiceRunningStatsServer{stream})
+}
+
+type CalculatorService_RunningStatsServer interface {
+	Send(*RunningStatsResponse) error
+	Recv() (*RunningStatsRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceRunningStatsServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceRunningStatsServer) Send(m *RunningStatsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceRunningStatsServer) Recv() (*RunningStatsRequest, error) {
+	m := new(RunningStatsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CalculatorService_SquareRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SquareRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).SquareRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/SquareRoot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).SquareRoot(ctx, req.(*SquareRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_Calculate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).Calculate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/Calculate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).Calculate(ctx, req.(*CalculateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type CalculatorService_CalculateAverageServer interface {
-	SendAndClose(*AverageResponse) error
-	Recv() (*AverageRequest, error)
-	grpc.ServerStream
+func _CalculatorService_CalculateBig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculateBigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).CalculateBig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/CalculateBig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).CalculateBig(ctx, req.(*CalculateBigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type calculatorServiceCalculateAverageServer struct {
-	grpc.ServerStream
+func _CalculatorService_GCD_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IntegerListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).GCD(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/GCD",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).GCD(ctx, req.(*IntegerListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *calculatorServiceCalculateAverageServer) SendAndClose(m *AverageResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _CalculatorService_LCM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IntegerListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).LCM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/LCM",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).LCM(ctx, req.(*IntegerListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *calculatorServiceCalculateAverageServer) Recv() (*AverageRequest, error) {
-	m := new(AverageRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func _CalculatorService_ModPow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModPowRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ModPow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/ModPow",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ModPow(ctx, req.(*ModPowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _CalculatorService_FindMax_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(CalculatorServiceServer).FindMax(&calculatorServiceFindMaxServer{stream})
+func _CalculatorService_ModInverse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModInverseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ModInverse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/ModInverse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ModInverse(ctx, req.(*ModInverseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type CalculatorService_FindMaxServer interface {
-	Send(*FindMaxResponse) error
-	Recv() (*FindMaxRequest, error)
-	grpc.ServerStream
+func _CalculatorService_ExtendedGCD_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtendedGCDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ExtendedGCD(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/ExtendedGCD",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ExtendedGCD(ctx, req.(*ExtendedGCDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type calculatorServiceFindMaxServer struct {
-	grpc.ServerStream
+func _CalculatorService_DotProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DotProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).DotProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/DotProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).DotProduct(ctx, req.(*DotProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *calculatorServiceFindMaxServer) Send(m *FindMaxResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _CalculatorService_MatrixMultiply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatrixMultiplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).MatrixMultiply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/MatrixMultiply",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).MatrixMultiply(ctx, req.(*MatrixMultiplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *calculatorServiceFindMaxServer) Recv() (*FindMaxRequest, error) {
-	m := new(FindMaxRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func _CalculatorService_Transpose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatrixRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).Transpose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/Transpose",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).Transpose(ctx, req.(*MatrixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _CalculatorService_SquareRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SquareRootRequest)
+func _CalculatorService_Determinant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatrixRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CalculatorServiceServer).SquareRoot(ctx, in)
+		return srv.(CalculatorServiceServer).Determinant(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/calculator.CalculatorService/SquareRoot",
+		FullMethod: "/calculator.CalculatorService/Determinant",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CalculatorServiceServer).SquareRoot(ctx, req.(*SquareRootRequest))
+		return srv.(CalculatorServiceServer).Determinant(ctx, req.(*MatrixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_Inverse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatrixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).Inverse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/Inverse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).Inverse(ctx, req.(*MatrixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_Solve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SolveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).Solve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/Solve",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).Solve(ctx, req.(*SolveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_Evaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).Evaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/Evaluate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).Evaluate(ctx, req.(*EvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_Convert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConvertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).Convert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/Convert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).Convert(ctx, req.(*ConvertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_ListUnits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUnitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ListUnits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/ListUnits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ListUnits(ctx, req.(*ListUnitsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -859,10 +4159,82 @@ var _CalculatorService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CalculateSum",
 			Handler:    _CalculatorService_CalculateSum_Handler,
 		},
+		{
+			MethodName: "IsPrime",
+			Handler:    _CalculatorService_IsPrime_Handler,
+		},
+		{
+			MethodName: "NextPrime",
+			Handler:    _CalculatorService_NextPrime_Handler,
+		},
 		{
 			MethodName: "SquareRoot",
 			Handler:    _CalculatorService_SquareRoot_Handler,
 		},
+		{
+			MethodName: "Calculate",
+			Handler:    _CalculatorService_Calculate_Handler,
+		},
+		{
+			MethodName: "CalculateBig",
+			Handler:    _CalculatorService_CalculateBig_Handler,
+		},
+		{
+			MethodName: "GCD",
+			Handler:    _CalculatorService_GCD_Handler,
+		},
+		{
+			MethodName: "LCM",
+			Handler:    _CalculatorService_LCM_Handler,
+		},
+		{
+			MethodName: "ModPow",
+			Handler:    _CalculatorService_ModPow_Handler,
+		},
+		{
+			MethodName: "ModInverse",
+			Handler:    _CalculatorService_ModInverse_Handler,
+		},
+		{
+			MethodName: "ExtendedGCD",
+			Handler:    _CalculatorService_ExtendedGCD_Handler,
+		},
+		{
+			MethodName: "DotProduct",
+			Handler:    _CalculatorService_DotProduct_Handler,
+		},
+		{
+			MethodName: "MatrixMultiply",
+			Handler:    _CalculatorService_MatrixMultiply_Handler,
+		},
+		{
+			MethodName: "Transpose",
+			Handler:    _CalculatorService_Transpose_Handler,
+		},
+		{
+			MethodName: "Determinant",
+			Handler:    _CalculatorService_Determinant_Handler,
+		},
+		{
+			MethodName: "Inverse",
+			Handler:    _CalculatorService_Inverse_Handler,
+		},
+		{
+			MethodName: "Solve",
+			Handler:    _CalculatorService_Solve_Handler,
+		},
+		{
+			MethodName: "Evaluate",
+			Handler:    _CalculatorService_Evaluate_Handler,
+		},
+		{
+			MethodName: "Convert",
+			Handler:    _CalculatorService_Convert_Handler,
+		},
+		{
+			MethodName: "ListUnits",
+			Handler:    _CalculatorService_ListUnits_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -870,17 +4242,33 @@ var _CalculatorService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _CalculatorService_CalculatePrimeDecomposition_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GeneratePrimes",
+			Handler:       _CalculatorService_GeneratePrimes_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "CalculateAverage",
 			Handler:       _CalculatorService_CalculateAverage_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ComputeStatistics",
+			Handler:       _CalculatorService_ComputeStatistics_Handler,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "FindMax",
 			Handler:       _CalculatorService_FindMax_Handler,
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "RunningStats",
+			Handler:       _CalculatorService_RunningStats_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "calculator/calculatorpb/calculator.proto",
 }
@@ -0,0 +1,9 @@
+package calculatorpb
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI v3 document for the services in calculator.proto,
+// written by generate.sh.
+//
+//go:embed calculator.openapi.json
+var OpenAPI []byte
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -12,31 +13,101 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
-
-	"google.golang.org/grpc"
+	"github.com/jwfrizzell/grpc-go-course/dial"
 )
 
 type options struct{}
 
+// defaultServiceConfig retries the cheap unary calls and hedges SquareRoot,
+// none of which have side effects.
+var defaultServiceConfig = &dial.ServiceConfig{
+	MethodConfig: []dial.MethodConfig{
+		{
+			Name: []dial.Name{
+				{Service: "calculator.CalculatorService", Method: "CalculateSum"},
+				{Service: "calculator.CalculatorService", Method: "Calculate"},
+				{Service: "calculator.CalculatorService", Method: "CalculateBig"},
+				{Service: "calculator.CalculatorService", Method: "Evaluate"},
+				{Service: "calculator.CalculatorService", Method: "IsPrime"},
+				{Service: "calculator.CalculatorService", Method: "NextPrime"},
+				{Service: "calculator.CalculatorService", Method: "GCD"},
+				{Service: "calculator.CalculatorService", Method: "LCM"},
+				{Service: "calculator.CalculatorService", Method: "ModPow"},
+				{Service: "calculator.CalculatorService", Method: "ModInverse"},
+				{Service: "calculator.CalculatorService", Method: "ExtendedGCD"},
+				{Service: "calculator.CalculatorService", Method: "DotProduct"},
+				{Service: "calculator.CalculatorService", Method: "MatrixMultiply"},
+				{Service: "calculator.CalculatorService", Method: "Transpose"},
+				{Service: "calculator.CalculatorService", Method: "Determinant"},
+				{Service: "calculator.CalculatorService", Method: "Inverse"},
+				{Service: "calculator.CalculatorService", Method: "Solve"},
+				{Service: "calculator.CalculatorService", Method: "Convert"},
+				{Service: "calculator.CalculatorService", Method: "ListUnits"},
+			},
+			Timeout:     dial.Duration(5 * time.Second),
+			RetryPolicy: dial.DefaultRetryPolicy(),
+		},
+		{
+			Name:    []dial.Name{{Service: "calculator.CalculatorService", Method: "SquareRoot"}},
+			Timeout: dial.Duration(5 * time.Second),
+			HedgingPolicy: &dial.HedgingPolicy{
+				MaxAttempts:         3,
+				HedgingDelay:        dial.Duration(200 * time.Millisecond),
+				NonFatalStatusCodes: []string{dial.Unavailable},
+			},
+		},
+	},
+	RetryThrottling: dial.DefaultRetryThrottling(),
+}
+
 func main() {
+	flags := dial.RegisterFlags(flag.CommandLine, dial.Defaults{
+		ServiceName:   "calculator-client",
+		ServiceConfig: defaultServiceConfig,
+	})
+	flag.Parse()
+
 	fmt.Println("Initializing Client Connection...")
 
-	cc, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
-	defer cc.Close()
+	conn, err := dial.Setup("localhost:50051", flags)
 	if err != nil {
 		log.Fatalf("Client Connection Failure: %v\n", err)
 	}
+	defer conn.Close(context.Background())
+	cc := conn.Conn
 
 	c := calculatorpb.NewCalculatorServiceClient(cc)
 
 	// runUnary(c)
 
+	//doCalculate(c)
+
+	//doCalculateBig(c)
+
+	//doEvaluate(c)
+
+	//doLinearAlgebra(c)
+
+	//doNumberTheory(c)
+
+	//doConvert(c)
+
 	// doServiceStreaming(c)
 
+	//doBigPrimeDecomposition(c)
+
+	//doPrimeUtilities(c)
+
 	//doClientStreaming(c)
 
+	//doComputeStatistics(c)
+
 	//doBiDirectionalStreaming(c)
 
+	//doRunningStats(c)
+
+	//doClientStreamingDisconnect(c)
+
 	doUnaryErrorHandling(c, 10)
 
 	doUnaryErrorHandling(c, -10)
@@ -61,6 +132,164 @@ func runUnary(c calculatorpb.CalculatorServiceClient) {
 
 }
 
+func doCalculate(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting Calculate RPC...")
+
+	i := func(v int64) *calculatorpb.Operand {
+		return &calculatorpb.Operand{Value: &calculatorpb.Operand_IntValue{IntValue: v}}
+	}
+	d := func(v float64) *calculatorpb.Operand {
+		return &calculatorpb.Operand{Value: &calculatorpb.Operand_DoubleValue{DoubleValue: v}}
+	}
+	reqs := []*calculatorpb.CalculateRequest{
+		{Operation: calculatorpb.Operation_SUBTRACT, Left: i(3), Right: i(10)},
+		{Operation: calculatorpb.Operation_DIVIDE, Left: i(7), Right: i(2)},
+		{Operation: calculatorpb.Operation_DIVIDE, Left: d(7), Right: i(2)},
+		{Operation: calculatorpb.Operation_POWER, Left: i(2), Right: i(62)},
+		{Operation: calculatorpb.Operation_POWER, Left: i(2), Right: i(63)},
+		{Operation: calculatorpb.Operation_MODULO, Left: i(7), Right: i(0)},
+	}
+	for _, req := range reqs {
+		resp, err := c.Calculate(context.Background(), req)
+		if err != nil {
+			s := status.Convert(err)
+			fmt.Printf("%v: %v %v\n", req.GetOperation(), s.Code(), s.Message())
+			continue
+		}
+		fmt.Printf("%v: %v\n", req.GetOperation(), resp.GetResult())
+	}
+}
+
+func doCalculateBig(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting CalculateBig RPC...")
+
+	reqs := []*calculatorpb.CalculateBigRequest{
+		{Operation: calculatorpb.Operation_ADD, Operands: []string{"9223372036854775807", "1"}},
+		{Operation: calculatorpb.Operation_MULTIPLY, Operands: []string{"1.5", "-2.25", "1e30"}},
+		{Operation: calculatorpb.Operation_POWER, Operands: []string{"2", "200"}},
+		{Operation: calculatorpb.Operation_AVERAGE, Operands: []string{"1", "2", "2"}, Scale: 10},
+	}
+	for _, req := range reqs {
+		resp, err := c.CalculateBig(context.Background(), req)
+		if err != nil {
+			s := status.Convert(err)
+			fmt.Printf("%v: %v %v\n", req.GetOperation(), s.Code(), s.Message())
+			continue
+		}
+		fmt.Printf("%v: %s (exact %v)\n", req.GetOperation(), resp.GetResult(), resp.GetExact())
+	}
+}
+
+func doEvaluate(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting Evaluate RPC...")
+
+	vars := map[string]float64{"a": 3, "b": 5, "c": 16}
+	for _, e := range []string{
+		"(a + b) * sqrt(c) / 2",
+		"-2^2 + max(a, b, c)",
+		"log(a - 3)",
+		"a + * b",
+		"a / (b - 5)",
+		"d * 2",
+	} {
+		resp, err := c.Evaluate(context.Background(), &calculatorpb.EvaluateRequest{Expression: e, Variables: vars})
+		if err != nil {
+			s := status.Convert(err)
+			fmt.Printf("%s: %v %v\n", e, s.Code(), s.Message())
+			continue
+		}
+		fmt.Printf("%s = %v\n", e, resp.GetResult())
+	}
+}
+
+func doLinearAlgebra(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting Linear Algebra RPCs...")
+
+	a := &calculatorpb.Matrix{Rows: 3, Cols: 3, Values: []float64{2, 1, -1, -3, -1, 2, -2, 1, 2}}
+	singular := &calculatorpb.Matrix{Rows: 2, Cols: 2, Values: []float64{1, 2, 2, 4}}
+
+	det, err := c.Determinant(context.Background(), &calculatorpb.MatrixRequest{Matrix: a})
+	if err != nil {
+		log.Fatalf("Determinant Failure: %v", err)
+	}
+	log.Printf("det(a): %v", det.GetDeterminant())
+
+	x, err := c.Solve(context.Background(), &calculatorpb.SolveRequest{A: a, B: &calculatorpb.Vector{Values: []float64{8, -11, -3}}})
+	if err != nil {
+		log.Fatalf("Solve Failure: %v", err)
+	}
+	log.Printf("a x = b: x = %v", x.GetX().GetValues())
+
+	_, err = c.Inverse(context.Background(), &calculatorpb.MatrixRequest{Matrix: singular})
+	s := status.Convert(err)
+	log.Printf("Inverse of a singular matrix: %v %v", s.Code(), s.Message())
+}
+
+func doNumberTheory(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting Number Theory RPCs...")
+
+	i := func(v int64) *calculatorpb.Integer {
+		return &calculatorpb.Integer{Value: &calculatorpb.Integer_IntValue{IntValue: v}}
+	}
+	b := func(v string) *calculatorpb.Integer {
+		return &calculatorpb.Integer{Value: &calculatorpb.Integer_BigValue{BigValue: v}}
+	}
+
+	g, err := c.GCD(context.Background(), &calculatorpb.IntegerListRequest{Numbers: []*calculatorpb.Integer{i(84), i(-126), i(210)}})
+	if err != nil {
+		log.Fatalf("GCD Failure: %v", err)
+	}
+	log.Printf("gcd(84, -126, 210): %v", g.GetResult().GetIntValue())
+
+	p, err := c.ModPow(context.Background(), &calculatorpb.ModPowRequest{
+		Base:     b("2"),
+		Exponent: b("1000"),
+		Modulus:  b("340282366920938463463374607431768211457"),
+	})
+	if err != nil {
+		log.Fatalf("ModPow Failure: %v", err)
+	}
+	log.Printf("2^1000 mod 2^128+1: %v", p.GetResult().GetBigValue())
+
+	e, err := c.ExtendedGCD(context.Background(), &calculatorpb.ExtendedGCDRequest{A: i(240), B: i(46)})
+	if err != nil {
+		log.Fatalf("ExtendedGCD Failure: %v", err)
+	}
+	log.Printf("240x + 46y = %v: x=%v y=%v", e.GetGcd().GetIntValue(), e.GetX().GetIntValue(), e.GetY().GetIntValue())
+
+	_, err = c.ModInverse(context.Background(), &calculatorpb.ModInverseRequest{Number: i(6), Modulus: i(9)})
+	s := status.Convert(err)
+	log.Printf("ModInverse(6, 9): %v %v", s.Code(), s.Message())
+}
+
+func doConvert(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting Convert RPC...")
+
+	for _, r := range []*calculatorpb.ConvertRequest{
+		{Value: 100, From: "km/h", To: "mph"},
+		{Value: 98.6, From: "fahrenheit", To: "celsius"},
+		{Value: 1, From: "GiB", To: "MB"},
+		{Value: 3, From: "feet", To: "kg"},
+		{Value: 1, From: "parsec", To: "m"},
+	} {
+		resp, err := c.Convert(context.Background(), r)
+		if err != nil {
+			s := status.Convert(err)
+			fmt.Printf("%v %s -> %s: %v %v\n", r.GetValue(), r.GetFrom(), r.GetTo(), s.Code(), s.Message())
+			continue
+		}
+		fmt.Printf("%v %s = %v %s\n", r.GetValue(), resp.GetFrom().GetName(), resp.GetValue(), resp.GetTo().GetName())
+	}
+
+	list, err := c.ListUnits(context.Background(), &calculatorpb.ListUnitsRequest{Dimension: "temperature"})
+	if err != nil {
+		log.Fatalf("ListUnits Failure: %v", err)
+	}
+	for _, u := range list.GetUnits() {
+		log.Printf("%s (%s): base = value * %v + %v", u.GetName(), u.GetSymbol(), u.GetFactor(), u.GetOffset())
+	}
+}
+
 func doServiceStreaming(c calculatorpb.CalculatorServiceClient) {
 	fmt.Println("Starting Server Side Streaming Server...")
 
@@ -90,6 +319,63 @@ func doServiceStreaming(c calculatorpb.CalculatorServiceClient) {
 
 }
 
+func doBigPrimeDecomposition(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting Big Prime Decomposition...")
+
+	//2^64 + 1, which trial division alone would take ages to factor.
+	req := &calculatorpb.PrimeRequest{BigNumber: "18446744073709551617"}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rs, err := c.CalculatePrimeDecomposition(ctx, req)
+	if err != nil {
+		log.Fatalf("Calculate Prime Number Error: %v", err)
+	}
+	for {
+		msg, err := rs.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("Prime Decomposition Stream Failure: %v", err)
+		}
+		log.Printf("Prime Response: %s", msg.GetBigNumber())
+	}
+}
+
+func doPrimeUtilities(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting Prime Utilities...")
+
+	for _, n := range []uint64{1, 97, 561, 18446744073709551557} {
+		resp, err := c.IsPrime(context.Background(), &calculatorpb.IsPrimeRequest{Number: n})
+		if err != nil {
+			log.Fatalf("IsPrime Failure: %v", err)
+		}
+		log.Printf("IsPrime(%d): %v", n, resp.GetIsPrime())
+	}
+
+	np, err := c.NextPrime(context.Background(), &calculatorpb.NextPrimeRequest{Number: 1000000})
+	if err != nil {
+		log.Fatalf("NextPrime Failure: %v", err)
+	}
+	log.Printf("NextPrime(1000000): %d", np.GetPrime())
+
+	rs, err := c.GeneratePrimes(context.Background(), &calculatorpb.GeneratePrimesRequest{From: 1, To: 100})
+	if err != nil {
+		log.Fatalf("GeneratePrimes Failure: %v", err)
+	}
+	for {
+		msg, err := rs.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("GeneratePrimes Stream Failure: %v", err)
+		}
+		log.Printf("Primes: %v", msg.GetPrimes())
+	}
+}
+
 func doClientStreaming(c calculatorpb.CalculatorServiceClient) {
 	fmt.Println("Starting Client Side Streaming...")
 
@@ -116,6 +402,73 @@ func doClientStreaming(c calculatorpb.CalculatorServiceClient) {
 
 }
 
+// doClientStreamingDisconnect walks away from CalculateAverage and FindMax
+// mid-stream and then checks that the server still answers other calls.
+func doClientStreamingDisconnect(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting Mid-Stream Disconnect...")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	avg, err := c.CalculateAverage(ctx)
+	if err != nil {
+		log.Fatalf("CalculateAverage() Failure: %v", err)
+	}
+	for _, v := range []int64{3, 5, 9} {
+		if err := avg.Send(&calculatorpb.AverageRequest{Number: v}); err != nil {
+			log.Fatalf("CalculateAverage() Send Failure: %v", err)
+		}
+	}
+	cancel()
+	_, err = avg.CloseAndRecv()
+	fmt.Printf("CalculateAverage after cancel: %v\n", status.Code(err))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	max, err := c.FindMax(ctx)
+	if err != nil {
+		log.Fatalf("FindMax() Failure: %v", err)
+	}
+	if err := max.Send(&calculatorpb.FindMaxRequest{Number: 7}); err != nil {
+		log.Fatalf("FindMax() Send Failure: %v", err)
+	}
+	if _, err := max.Recv(); err != nil {
+		log.Fatalf("FindMax() Recv Failure: %v", err)
+	}
+	cancel()
+	_, err = max.Recv()
+	fmt.Printf("FindMax after cancel: %v\n", status.Code(err))
+
+	//The server must still be up for everyone else.
+	resp, err := c.CalculateSum(context.Background(), &calculatorpb.CalculatorRequest{
+		Calculator: &calculatorpb.Calculator{FirstNumber: 1, LastNumber: 2},
+	})
+	if err != nil {
+		log.Fatalf("Server stopped serving after disconnect: %v", err)
+	}
+	fmt.Printf("Server still serving. Sum: %d\n", resp.GetResult())
+}
+
+func doComputeStatistics(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting Compute Statistics...")
+
+	stream, err := c.ComputeStatistics(context.Background())
+	if err != nil {
+		log.Fatalf("doComputeStatistics() Failure: %v", err)
+	}
+	batches := []*calculatorpb.StatisticsRequest{
+		{Numbers: []float64{2, 4, 4, 4}, Percentiles: []float64{10, 90, 99}},
+		{Numbers: []float64{5, 5, 7, 9}},
+	}
+	for _, req := range batches {
+		if err := stream.Send(req); err != nil {
+			log.Fatalf("Error Sending Statistics Request: %v", err)
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		log.Fatalf("Error Receiving Statistics Response: %v", err)
+	}
+	log.Printf("Statistics: %v", resp)
+}
+
 func doBiDirectionalStreaming(c calculatorpb.CalculatorServiceClient) {
 	fmt.Println("Starting Bi-Directional Client Side Streaming...")
 
@@ -165,6 +518,66 @@ func doBiDirectionalStreaming(c calculatorpb.CalculatorServiceClient) {
 	<-waitc
 }
 
+func doRunningStats(c calculatorpb.CalculatorServiceClient) {
+	fmt.Println("Starting Running Stats...")
+
+	values := []float64{-4, -9, -1, -7, -2, -8}
+
+	stream, err := c.RunningStats(context.Background())
+	if err != nil {
+		log.Fatalf("Running Stats Stream Error: %v", err)
+	}
+	cfg := &calculatorpb.RunningStatsConfig{
+		Aggregates: []calculatorpb.Aggregate{
+			calculatorpb.Aggregate_AGGREGATE_MAX,
+			calculatorpb.Aggregate_AGGREGATE_MEAN,
+			calculatorpb.Aggregate_AGGREGATE_EWMA,
+		},
+		WindowSize: 3,
+	}
+
+	waitc := make(chan struct{})
+	//Sending data to server.
+	go func() {
+		err := stream.Send(&calculatorpb.RunningStatsRequest{
+			Item: &calculatorpb.RunningStatsRequest_Config{Config: cfg},
+		})
+		for _, v := range values {
+			if err != nil {
+				break
+			}
+			err = stream.Send(&calculatorpb.RunningStatsRequest{
+				Item: &calculatorpb.RunningStatsRequest_Number{Number: v},
+			})
+			time.Sleep(500 * time.Millisecond)
+		}
+		if err != nil {
+			log.Fatalf("doRunningStats Error: %v", err)
+		}
+		if err := stream.CloseSend(); err != nil {
+			log.Fatalf("CloseSend() Error: %v", err)
+		}
+	}()
+
+	//Receive Response
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				fmt.Println("End of data stream...")
+				break
+			}
+			if err != nil {
+				log.Fatalf("Receive Response Error: %v", err)
+			}
+			fmt.Printf("Window of %d: %v\n", resp.GetCount(), resp.GetValues())
+		}
+		close(waitc)
+	}()
+
+	<-waitc
+}
+
 func doUnaryErrorHandling(c calculatorpb.CalculatorServiceClient, value float64) {
 	fmt.Println("Starting Unary Error handling...")
 
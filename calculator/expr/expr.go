@@ -0,0 +1,455 @@
+// Package expr parses and evaluates arithmetic expressions such as
+// "(a + b) * sqrt(c) / 2".
+//
+// The grammar, loosest binding first:
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/") unary }
+//	unary   = ("-" | "+") unary | power
+//	power   = primary [ "^" unary ]
+//	primary = number | name | name "(" expr { "," expr } ")" | "(" expr ")"
+//
+// "^" is right associative and binds tighter than unary minus, so -2^2 is
+// -4. The functions are sqrt, abs, log, exp, sin, cos, min and max.
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Limits on what Parse accepts, so a single request cannot exhaust the
+// stack or the CPU.
+const (
+	MaxLength = 4096
+	MaxDepth  = 64
+)
+
+// Kind classifies an Error.
+type Kind int
+
+const (
+	//Syntax is a malformed expression.
+	Syntax Kind = iota
+	//Domain is a well formed expression that cannot be evaluated with the
+	//given variables, such as division by zero or an unknown variable.
+	Domain
+	//Range is a result too large to represent.
+	Range
+)
+
+// Error reports a problem at a 1-based byte position of the expression.
+type Error struct {
+	Kind Kind
+	Pos  int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+type function struct {
+	minArgs, maxArgs int //maxArgs < 0 means variadic
+	fn               func(pos int, args []float64) (float64, error)
+}
+
+func unary(f func(float64) float64) function {
+	return function{1, 1, func(_ int, a []float64) (float64, error) { return f(a[0]), nil }}
+}
+
+var functions = map[string]function{
+	"sqrt": {1, 1, func(pos int, a []float64) (float64, error) {
+		if a[0] < 0 {
+			return 0, &Error{Domain, pos, fmt.Sprintf("sqrt of negative number %v", a[0])}
+		}
+		return math.Sqrt(a[0]), nil
+	}},
+	"log": {1, 1, func(pos int, a []float64) (float64, error) {
+		if a[0] <= 0 {
+			return 0, &Error{Domain, pos, fmt.Sprintf("log of non-positive number %v", a[0])}
+		}
+		return math.Log(a[0]), nil
+	}},
+	"abs": unary(math.Abs),
+	"exp": unary(math.Exp),
+	"sin": unary(math.Sin),
+	"cos": unary(math.Cos),
+	"min": {1, -1, func(_ int, a []float64) (float64, error) {
+		m := a[0]
+		for _, v := range a[1:] {
+			m = math.Min(m, v)
+		}
+		return m, nil
+	}},
+	"max": {1, -1, func(_ int, a []float64) (float64, error) {
+		m := a[0]
+		for _, v := range a[1:] {
+			m = math.Max(m, v)
+		}
+		return m, nil
+	}},
+}
+
+type node interface {
+	eval(vars map[string]float64) (float64, error)
+}
+
+type number float64
+
+type variable struct {
+	pos  int
+	name string
+}
+
+type negate struct {
+	x node
+}
+
+type binary struct {
+	pos  int
+	op   byte
+	l, r node
+}
+
+type call struct {
+	pos  int
+	name string
+	fn   function
+	args []node
+}
+
+// Expr is a parsed expression. It is safe for concurrent use.
+type Expr struct {
+	root node
+}
+
+// Parse parses src. Errors are *Error values of Kind Syntax.
+func Parse(src string) (*Expr, error) {
+	if len(src) > MaxLength {
+		return nil, &Error{Syntax, MaxLength + 1, fmt.Sprintf("expression longer than %d bytes", MaxLength)}
+	}
+	p := &parser{src: src}
+	p.next()
+	root, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.tok != tokEOF {
+		return nil, p.errorf("unexpected %s", p.describe())
+	}
+	return &Expr{root: root}, nil
+}
+
+// Eval evaluates e with vars bound. Errors are *Error values of Kind
+// Domain or Range.
+func (e *Expr) Eval(vars map[string]float64) (float64, error) {
+	return e.root.eval(vars)
+}
+
+func finite(pos int, v float64) (float64, error) {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, &Error{Range, pos, "result is not a finite number"}
+	}
+	return v, nil
+}
+
+func (n number) eval(map[string]float64) (float64, error) {
+	return float64(n), nil
+}
+
+func (v *variable) eval(vars map[string]float64) (float64, error) {
+	x, ok := vars[v.name]
+	if !ok {
+		return 0, &Error{Domain, v.pos, fmt.Sprintf("unknown variable %q", v.name)}
+	}
+	return x, nil
+}
+
+func (n *negate) eval(vars map[string]float64) (float64, error) {
+	x, err := n.x.eval(vars)
+	return -x, err
+}
+
+func (b *binary) eval(vars map[string]float64) (float64, error) {
+	l, err := b.l.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	r, err := b.r.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	switch b.op {
+	case '+':
+		return finite(b.pos, l+r)
+	case '-':
+		return finite(b.pos, l-r)
+	case '*':
+		return finite(b.pos, l*r)
+	case '/':
+		if r == 0 {
+			return 0, &Error{Domain, b.pos, "division by zero"}
+		}
+		return finite(b.pos, l/r)
+	}
+	return finite(b.pos, math.Pow(l, r))
+}
+
+func (c *call) eval(vars map[string]float64) (float64, error) {
+	args := make([]float64, len(c.args))
+	for i, a := range c.args {
+		v, err := a.eval(vars)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = v
+	}
+	v, err := c.fn.fn(c.pos, args)
+	if err != nil {
+		return 0, err
+	}
+	return finite(c.pos, v)
+}
+
+type token int
+
+const (
+	tokEOF token = iota
+	tokNumber
+	tokName
+	tokOp //one of + - * / ^ ( ) ,
+)
+
+type parser struct {
+	src   string
+	off   int //offset of the next unread byte
+	depth int
+
+	tok token
+	pos int //1-based position of tok
+	lit string
+	op  byte
+	err *Error //lexing error, reported when tok is used
+}
+
+func (p *parser) errorf(format string, args ...interface{}) *Error {
+	return &Error{Syntax, p.pos, fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) describe() string {
+	switch p.tok {
+	case tokEOF:
+		return "end of expression"
+	case tokNumber:
+		return "number " + p.lit
+	case tokName:
+		return "name " + p.lit
+	}
+	return strconv.Quote(string(p.op))
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isNameStart(c byte) bool { return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+// next scans the next token.
+func (p *parser) next() {
+	for p.off < len(p.src) && (p.src[p.off] == ' ' || p.src[p.off] == '\t' || p.src[p.off] == '\n' || p.src[p.off] == '\r') {
+		p.off++
+	}
+	p.pos = p.off + 1
+	if p.off >= len(p.src) {
+		p.tok = tokEOF
+		return
+	}
+	start := p.off
+	c := p.src[p.off]
+	switch {
+	case isDigit(c) || c == '.':
+		for p.off < len(p.src) && (isDigit(p.src[p.off]) || p.src[p.off] == '.') {
+			p.off++
+		}
+		if p.off < len(p.src) && (p.src[p.off] == 'e' || p.src[p.off] == 'E') {
+			p.off++
+			if p.off < len(p.src) && (p.src[p.off] == '+' || p.src[p.off] == '-') {
+				p.off++
+			}
+			for p.off < len(p.src) && isDigit(p.src[p.off]) {
+				p.off++
+			}
+		}
+		p.tok, p.lit = tokNumber, p.src[start:p.off]
+	case isNameStart(c):
+		for p.off < len(p.src) && (isNameStart(p.src[p.off]) || isDigit(p.src[p.off])) {
+			p.off++
+		}
+		p.tok, p.lit = tokName, p.src[start:p.off]
+	case c == '+' || c == '-' || c == '*' || c == '/' || c == '^' || c == '(' || c == ')' || c == ',':
+		p.off++
+		p.tok, p.op = tokOp, c
+	default:
+		p.tok, p.op = tokOp, 0
+		p.err = p.errorf("unexpected character %q", c)
+	}
+}
+
+func (p *parser) isOp(op byte) bool {
+	return p.tok == tokOp && p.op == op
+}
+
+func (p *parser) expect(op byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	if !p.isOp(op) {
+		return p.errorf("expected %s, found %s", strconv.Quote(string(op)), p.describe())
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) expr() (node, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > MaxDepth {
+		return nil, p.errorf("expression nested deeper than %d levels", MaxDepth)
+	}
+
+	l, err := p.term()
+	for err == nil && (p.isOp('+') || p.isOp('-')) {
+		op, pos := p.op, p.pos
+		p.next()
+		var r node
+		if r, err = p.term(); err == nil {
+			l = &binary{pos: pos, op: op, l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *parser) term() (node, error) {
+	l, err := p.unary()
+	for err == nil && (p.isOp('*') || p.isOp('/')) {
+		op, pos := p.op, p.pos
+		p.next()
+		var r node
+		if r, err = p.unary(); err == nil {
+			l = &binary{pos: pos, op: op, l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *parser) unary() (node, error) {
+	if p.isOp('-') || p.isOp('+') {
+		neg := p.op == '-'
+		p.depth++
+		defer func() { p.depth-- }()
+		if p.depth > MaxDepth {
+			return nil, p.errorf("expression nested deeper than %d levels", MaxDepth)
+		}
+		p.next()
+		x, err := p.unary()
+		if err != nil || !neg {
+			return x, err
+		}
+		return &negate{x: x}, nil
+	}
+	return p.power()
+}
+
+func (p *parser) power() (node, error) {
+	base, err := p.primary()
+	if err != nil || !p.isOp('^') {
+		return base, err
+	}
+	pos := p.pos
+	//"^" recurses for its right operand, so a chain like 2^2^2 nests as
+	//deeply as parentheses do.
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > MaxDepth {
+		return nil, p.errorf("expression nested deeper than %d levels", MaxDepth)
+	}
+	p.next()
+	exp, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return &binary{pos: pos, op: '^', l: base, r: exp}, nil
+}
+
+func (p *parser) primary() (node, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	switch {
+	case p.tok == tokNumber:
+		v, err := strconv.ParseFloat(p.lit, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", p.lit)
+		}
+		p.next()
+		return number(v), nil
+	case p.tok == tokName:
+		name, pos := p.lit, p.pos
+		p.next()
+		if !p.isOp('(') {
+			return &variable{pos: pos, name: name}, nil
+		}
+		return p.call(name, pos)
+	case p.isOp('('):
+		p.next()
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+	return nil, p.errorf("expected a number, name or \"(\", found %s", p.describe())
+}
+
+func (p *parser) call(name string, pos int) (node, error) {
+	fn, ok := functions[name]
+	if !ok {
+		return nil, &Error{Syntax, pos, fmt.Sprintf("unknown function %q", name)}
+	}
+	p.next() //consume "("
+	c := &call{pos: pos, name: name, fn: fn}
+	for {
+		arg, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		c.args = append(c.args, arg)
+		if !p.isOp(',') {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	if len(c.args) < fn.minArgs || fn.maxArgs >= 0 && len(c.args) > fn.maxArgs {
+		return nil, &Error{Syntax, pos, fmt.Sprintf("%s takes %s, got %d", name, arity(fn), len(c.args))}
+	}
+	return c, nil
+}
+
+func arity(fn function) string {
+	switch {
+	case fn.maxArgs < 0:
+		return fmt.Sprintf("at least %d arguments", fn.minArgs)
+	case fn.minArgs == 1 && fn.maxArgs == 1:
+		return "1 argument"
+	}
+	return fmt.Sprintf("%d to %d arguments", fn.minArgs, fn.maxArgs)
+}
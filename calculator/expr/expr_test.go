@@ -0,0 +1,144 @@
+package expr
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseDepth(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		ok   bool
+	}{
+		{"parentheses at limit", strings.Repeat("(", MaxDepth-1) + "1" + strings.Repeat(")", MaxDepth-1), true},
+		{"parentheses past limit", strings.Repeat("(", MaxDepth) + "1" + strings.Repeat(")", MaxDepth), false},
+		{"signs past limit", strings.Repeat("-", MaxDepth+1) + "1", false},
+		{"power chain at limit", "1" + strings.Repeat("^1", MaxDepth-1), true},
+		{"power chain past limit", "1" + strings.Repeat("^1", MaxDepth), false},
+		{"long power chain", "2" + strings.Repeat("^2", 2000), false},
+		{"signed power chain past limit", "1" + strings.Repeat("^-1", MaxDepth/2+1), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.src)
+			if tc.ok {
+				if err != nil {
+					t.Fatalf("Parse: %v", err)
+				}
+				return
+			}
+			var ee *Error
+			if !errors.As(err, &ee) || ee.Kind != Syntax || !strings.Contains(ee.Msg, "nested deeper") {
+				t.Fatalf("Parse error = %v, want a nesting error", err)
+			}
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	vars := map[string]float64{"a": 3, "b": 4, "zero": 0, "big": 1e308}
+	tests := []struct {
+		src  string
+		want float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"24 / 4 / 3", 2},
+		{"2^3^2", 512},
+		{"-2^2", -4},
+		{"(-2)^2", 4},
+		{"2^-1", 0.5},
+		{"-+-3", 3},
+		{"2 * -3", -6},
+		{"1.5e2 + .5", 150.5},
+		{"1E-1", 0.1},
+		{"sqrt(a*a + b*b)", 5},
+		{"abs(a - b)", 1},
+		{"min(b, a, 7)", 3},
+		{"max(a)", 3},
+		{"log(exp(2))", 2},
+		{"cos(0) + sin(0)", 1},
+		{" \ta\n+\rb ", 7},
+	}
+	for _, tc := range tests {
+		t.Run(tc.src, func(t *testing.T) {
+			e, err := Parse(tc.src)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			got, err := e.Eval(vars)
+			if err != nil {
+				t.Fatalf("Eval: %v", err)
+			}
+			if math.Abs(got-tc.want) > 1e-12 {
+				t.Errorf("Eval = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	vars := map[string]float64{"zero": 0, "big": 1e308}
+	tests := []struct {
+		src  string
+		kind Kind
+		pos  int
+		msg  string
+	}{
+		{"", Syntax, 1, "expected a number"},
+		{"1 +", Syntax, 4, "expected a number"},
+		{"1 2", Syntax, 3, "unexpected number 2"},
+		{"(1 + 2", Syntax, 7, `expected ")"`},
+		{"1 + 2)", Syntax, 6, `unexpected ")"`},
+		{"1 $ 2", Syntax, 3, "unexpected character"},
+		{"1..2", Syntax, 1, "invalid number"},
+		{"foo(1)", Syntax, 1, `unknown function "foo"`},
+		{"sqrt(1, 2)", Syntax, 1, "sqrt takes 1 argument, got 2"},
+		{"min()", Syntax, 5, "expected a number"},
+		{"1 / zero", Domain, 3, "division by zero"},
+		{"1 + nope", Domain, 5, `unknown variable "nope"`},
+		{"sqrt(-1)", Domain, 1, "sqrt of negative"},
+		{"log(zero)", Domain, 1, "log of non-positive"},
+		{"big * 10", Range, 5, "not a finite number"},
+		{"exp(1000)", Range, 1, "not a finite number"},
+		{"(-8)^(1/3)", Range, 5, "not a finite number"},
+		{strings.Repeat("1", MaxLength+1), Syntax, MaxLength + 1, "longer than"},
+	}
+	for _, tc := range tests {
+		name := tc.src
+		if len(name) > 20 {
+			name = name[:20] + "..."
+		}
+		t.Run(name, func(t *testing.T) {
+			e, err := Parse(tc.src)
+			if err == nil {
+				_, err = e.Eval(vars)
+			}
+			var ee *Error
+			if !errors.As(err, &ee) {
+				t.Fatalf("error = %v, want an *Error", err)
+			}
+			if ee.Kind != tc.kind || ee.Pos != tc.pos || !strings.Contains(ee.Msg, tc.msg) {
+				t.Errorf("error = {%v %d %q}, want {%v %d %q}", ee.Kind, ee.Pos, ee.Msg, tc.kind, tc.pos, tc.msg)
+			}
+		})
+	}
+}
+
+func TestMaxLength(t *testing.T) {
+	src := "1" + strings.Repeat("+1", (MaxLength-1)/2)
+	if len(src) > MaxLength {
+		t.Fatalf("test expression is %d bytes", len(src))
+	}
+	e, err := Parse(src)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got, err := e.Eval(nil); err != nil || got != float64(len(src)/2+1) {
+		t.Errorf("Eval = %v, %v, want %d", got, err, len(src)/2+1)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"errors"
+	"math"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+	"github.com/jwfrizzell/grpc-go-course/calculator/expr"
+)
+
+// errOverflow is returned when an int64 result does not fit.
+var errOverflow = status.Error(codes.OutOfRange, "integer overflow")
+
+func addInt64(a, b int64) (int64, error) {
+	c := a + b
+	if (c > a) != (b > 0) {
+		return 0, errOverflow
+	}
+	return c, nil
+}
+
+func subInt64(a, b int64) (int64, error) {
+	c := a - b
+	if (c < a) != (b > 0) {
+		return 0, errOverflow
+	}
+	return c, nil
+}
+
+func mulInt64(a, b int64) (int64, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	c := a * b
+	if c/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, errOverflow
+	}
+	return c, nil
+}
+
+// powInt64 raises base to a non-negative exp by repeated squaring.
+func powInt64(base, exp int64) (int64, error) {
+	if exp < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "negative exponent %d needs a double operand", exp)
+	}
+	result := int64(1)
+	for {
+		var err error
+		if exp&1 == 1 {
+			if result, err = mulInt64(result, base); err != nil {
+				return 0, err
+			}
+		}
+		exp >>= 1
+		if exp == 0 {
+			return result, nil
+		}
+		if base, err = mulInt64(base, base); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func calculateInt(op calculatorpb.Operation, a, b int64) (int64, error) {
+	switch op {
+	case calculatorpb.Operation_ADD:
+		return addInt64(a, b)
+	case calculatorpb.Operation_SUBTRACT:
+		return subInt64(a, b)
+	case calculatorpb.Operation_MULTIPLY:
+		return mulInt64(a, b)
+	case calculatorpb.Operation_DIVIDE, calculatorpb.Operation_MODULO:
+		if b == 0 {
+			return 0, status.Error(codes.InvalidArgument, "division by zero")
+		}
+		if op == calculatorpb.Operation_MODULO {
+			return a % b, nil
+		}
+		if a == math.MinInt64 && b == -1 {
+			return 0, errOverflow
+		}
+		return a / b, nil
+	case calculatorpb.Operation_POWER:
+		return powInt64(a, b)
+	case calculatorpb.Operation_MIN:
+		return min(a, b), nil
+	case calculatorpb.Operation_MAX:
+		return max(a, b), nil
+	}
+	return 0, status.Errorf(codes.InvalidArgument, "unsupported operation %v", op)
+}
+
+func calculateDouble(op calculatorpb.Operation, a, b float64) (float64, error) {
+	var c float64
+	switch op {
+	case calculatorpb.Operation_ADD:
+		c = a + b
+	case calculatorpb.Operation_SUBTRACT:
+		c = a - b
+	case calculatorpb.Operation_MULTIPLY:
+		c = a * b
+	case calculatorpb.Operation_DIVIDE, calculatorpb.Operation_MODULO:
+		if b == 0 {
+			return 0, status.Error(codes.InvalidArgument, "division by zero")
+		}
+		if op == calculatorpb.Operation_MODULO {
+			c = math.Mod(a, b)
+		} else {
+			c = a / b
+		}
+	case calculatorpb.Operation_POWER:
+		c = math.Pow(a, b)
+	case calculatorpb.Operation_MIN:
+		c = math.Min(a, b)
+	case calculatorpb.Operation_MAX:
+		c = math.Max(a, b)
+	default:
+		return 0, status.Errorf(codes.InvalidArgument, "unsupported operation %v", op)
+	}
+	if math.IsInf(c, 0) || math.IsNaN(c) {
+		return 0, status.Errorf(codes.OutOfRange, "%v of %v and %v is not a finite number", op, a, b)
+	}
+	return c, nil
+}
+
+// operandValue returns o as a float64, and as an int64 when it holds one.
+func operandValue(name string, o *calculatorpb.Operand) (f float64, i int64, isInt bool, err error) {
+	switch v := o.GetValue().(type) {
+	case *calculatorpb.Operand_IntValue:
+		return float64(v.IntValue), v.IntValue, true, nil
+	case *calculatorpb.Operand_DoubleValue:
+		if math.IsInf(v.DoubleValue, 0) || math.IsNaN(v.DoubleValue) {
+			return 0, 0, false, status.Errorf(codes.InvalidArgument, "%s operand must be finite", name)
+		}
+		return v.DoubleValue, 0, false, nil
+	}
+	return 0, 0, false, status.Errorf(codes.InvalidArgument, "%s operand is missing", name)
+}
+
+// calculate evaluates a CalculateRequest. Two int operands use checked
+// int64 arithmetic; any double operand switches to float64.
+func calculate(req *calculatorpb.CalculateRequest) (*calculatorpb.Operand, error) {
+	lf, li, lInt, err := operandValue("left", req.GetLeft())
+	if err != nil {
+		return nil, err
+	}
+	rf, ri, rInt, err := operandValue("right", req.GetRight())
+	if err != nil {
+		return nil, err
+	}
+	if lInt && rInt {
+		v, err := calculateInt(req.GetOperation(), li, ri)
+		if err != nil {
+			return nil, err
+		}
+		return &calculatorpb.Operand{Value: &calculatorpb.Operand_IntValue{IntValue: v}}, nil
+	}
+	v, err := calculateDouble(req.GetOperation(), lf, rf)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.Operand{Value: &calculatorpb.Operand_DoubleValue{DoubleValue: v}}, nil
+}
+
+// evaluate parses and evaluates an EvaluateRequest. Result errors become
+// OutOfRange; every other expression error is InvalidArgument.
+func evaluate(req *calculatorpb.EvaluateRequest) (float64, error) {
+	for name, v := range req.GetVariables() {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return 0, status.Errorf(codes.InvalidArgument, "variable %q must be finite", name)
+		}
+	}
+	e, err := expr.Parse(req.GetExpression())
+	if err == nil {
+		var v float64
+		if v, err = e.Eval(req.GetVariables()); err == nil {
+			return v, nil
+		}
+	}
+	var ee *expr.Error
+	if !errors.As(err, &ee) {
+		return 0, status.Error(codes.Internal, err.Error())
+	}
+	switch ee.Kind {
+	case expr.Syntax:
+		return 0, status.Errorf(codes.InvalidArgument, "syntax error at position %d: %s", ee.Pos, ee.Msg)
+	case expr.Range:
+		return 0, status.Errorf(codes.OutOfRange, "at position %d: %s", ee.Pos, ee.Msg)
+	}
+	return 0, status.Errorf(codes.InvalidArgument, "at position %d: %s", ee.Pos, ee.Msg)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+)
+
+func TestCalculateInt(t *testing.T) {
+	const (
+		add = calculatorpb.Operation_ADD
+		sub = calculatorpb.Operation_SUBTRACT
+		mul = calculatorpb.Operation_MULTIPLY
+		div = calculatorpb.Operation_DIVIDE
+		mod = calculatorpb.Operation_MODULO
+		pow = calculatorpb.Operation_POWER
+	)
+	tests := []struct {
+		name string
+		op   calculatorpb.Operation
+		a, b int64
+		want int64
+		code codes.Code
+	}{
+		{"add", add, 2, 3, 5, codes.OK},
+		{"add max", add, math.MaxInt64 - 1, 1, math.MaxInt64, codes.OK},
+		{"add overflow", add, math.MaxInt64, 1, 0, codes.OutOfRange},
+		{"add underflow", add, math.MinInt64, -1, 0, codes.OutOfRange},
+		{"add min plus max", add, math.MinInt64, math.MaxInt64, -1, codes.OK},
+		{"sub", sub, 2, 3, -1, codes.OK},
+		{"sub underflow", sub, math.MinInt64, 1, 0, codes.OutOfRange},
+		{"sub overflow", sub, 0, math.MinInt64, 0, codes.OutOfRange},
+		{"sub min from -1", sub, -1, math.MinInt64, math.MaxInt64, codes.OK},
+		{"mul", mul, -4, 5, -20, codes.OK},
+		{"mul zero", mul, math.MinInt64, 0, 0, codes.OK},
+		{"mul overflow", mul, math.MaxInt64/2 + 1, 2, 0, codes.OutOfRange},
+		{"mul min", mul, math.MinInt64 / 2, 2, math.MinInt64, codes.OK},
+		{"mul min by -1", mul, math.MinInt64, -1, 0, codes.OutOfRange},
+		{"mul -1 by min", mul, -1, math.MinInt64, 0, codes.OutOfRange},
+		{"mul 32-bit halves", mul, 1 << 32, 1 << 31, 0, codes.OutOfRange},
+		{"div", div, 7, 2, 3, codes.OK},
+		{"div negative truncates", div, -7, 2, -3, codes.OK},
+		{"div min by -1", div, math.MinInt64, -1, 0, codes.OutOfRange},
+		{"div min by 1", div, math.MinInt64, 1, math.MinInt64, codes.OK},
+		{"div by zero", div, 1, 0, 0, codes.InvalidArgument},
+		{"mod", mod, -7, 3, -1, codes.OK},
+		{"mod min by -1", mod, math.MinInt64, -1, 0, codes.OK},
+		{"mod by zero", mod, 1, 0, 0, codes.InvalidArgument},
+		{"pow", pow, 3, 4, 81, codes.OK},
+		{"pow zero exponent", pow, 0, 0, 1, codes.OK},
+		{"pow negative base", pow, -2, 63, math.MinInt64, codes.OK},
+		{"pow overflow", pow, 2, 63, 0, codes.OutOfRange},
+		{"pow overflow while squaring", pow, 1 << 32, 2, 0, codes.OutOfRange},
+		{"pow -1 large exponent", pow, -1, math.MaxInt64, -1, codes.OK},
+		{"pow negative exponent", pow, 2, -1, 0, codes.InvalidArgument},
+		{"min", calculatorpb.Operation_MIN, math.MinInt64, 0, math.MinInt64, codes.OK},
+		{"max", calculatorpb.Operation_MAX, math.MinInt64, 0, 0, codes.OK},
+		{"unsupported", calculatorpb.Operation(99), 1, 1, 0, codes.InvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := calculateInt(tc.op, tc.a, tc.b)
+			if code := status.Code(err); code != tc.code {
+				t.Fatalf("calculateInt(%v, %d, %d) error = %v, want %v", tc.op, tc.a, tc.b, err, tc.code)
+			}
+			if err == nil && got != tc.want {
+				t.Errorf("calculateInt(%v, %d, %d) = %d, want %d", tc.op, tc.a, tc.b, got, tc.want)
+			}
+		})
+	}
+}
+
+func intOperand(v int64) *calculatorpb.Operand {
+	return &calculatorpb.Operand{Value: &calculatorpb.Operand_IntValue{IntValue: v}}
+}
+
+func doubleOperand(v float64) *calculatorpb.Operand {
+	return &calculatorpb.Operand{Value: &calculatorpb.Operand_DoubleValue{DoubleValue: v}}
+}
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name        string
+		op          calculatorpb.Operation
+		left, right *calculatorpb.Operand
+		want        *calculatorpb.Operand
+		code        codes.Code
+	}{
+		{"ints stay int", calculatorpb.Operation_DIVIDE, intOperand(7), intOperand(2), intOperand(3), codes.OK},
+		{"double switches to float", calculatorpb.Operation_DIVIDE, intOperand(7), doubleOperand(2), doubleOperand(3.5), codes.OK},
+		{"int overflow", calculatorpb.Operation_ADD, intOperand(math.MaxInt64), intOperand(1), nil, codes.OutOfRange},
+		{"double avoids int overflow", calculatorpb.Operation_ADD, doubleOperand(math.MaxInt64), intOperand(1), doubleOperand(math.MaxInt64 + 1.0), codes.OK},
+		{"negative exponent as double", calculatorpb.Operation_POWER, intOperand(2), doubleOperand(-1), doubleOperand(0.5), codes.OK},
+		{"double modulo", calculatorpb.Operation_MODULO, doubleOperand(-7.5), doubleOperand(2), doubleOperand(-1.5), codes.OK},
+		{"double division by zero", calculatorpb.Operation_DIVIDE, doubleOperand(1), doubleOperand(0), nil, codes.InvalidArgument},
+		{"double overflow", calculatorpb.Operation_MULTIPLY, doubleOperand(math.MaxFloat64), doubleOperand(2), nil, codes.OutOfRange},
+		{"double NaN result", calculatorpb.Operation_POWER, doubleOperand(-8), doubleOperand(1.0 / 3), nil, codes.OutOfRange},
+		{"infinite operand", calculatorpb.Operation_ADD, doubleOperand(math.Inf(1)), intOperand(1), nil, codes.InvalidArgument},
+		{"NaN operand", calculatorpb.Operation_ADD, intOperand(1), doubleOperand(math.NaN()), nil, codes.InvalidArgument},
+		{"missing operand", calculatorpb.Operation_ADD, intOperand(1), nil, nil, codes.InvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := calculate(&calculatorpb.CalculateRequest{Operation: tc.op, Left: tc.left, Right: tc.right})
+			if code := status.Code(err); code != tc.code {
+				t.Fatalf("calculate error = %v, want %v", err, tc.code)
+			}
+			if err != nil {
+				return
+			}
+			switch want := tc.want.GetValue().(type) {
+			case *calculatorpb.Operand_IntValue:
+				if v, ok := got.GetValue().(*calculatorpb.Operand_IntValue); !ok || v.IntValue != want.IntValue {
+					t.Errorf("calculate = %v, want int %d", got, want.IntValue)
+				}
+			case *calculatorpb.Operand_DoubleValue:
+				if v, ok := got.GetValue().(*calculatorpb.Operand_DoubleValue); !ok || v.DoubleValue != want.DoubleValue {
+					t.Errorf("calculate = %v, want double %v", got, want.DoubleValue)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		vars map[string]float64
+		want float64
+		code codes.Code
+	}{
+		{"variables", "x * (y + 1)", map[string]float64{"x": 2, "y": 3}, 8, codes.OK},
+		{"short power chain", "2^2^2", nil, 16, codes.OK},
+		{"syntax", "1 +", nil, 0, codes.InvalidArgument},
+		{"domain", "1 / 0", nil, 0, codes.InvalidArgument},
+		{"unknown variable", "x", nil, 0, codes.InvalidArgument},
+		{"range", "10^400", nil, 0, codes.OutOfRange},
+		{"infinite variable", "x", map[string]float64{"x": math.Inf(-1)}, 0, codes.InvalidArgument},
+		{"NaN variable", "1", map[string]float64{"x": math.NaN()}, 0, codes.InvalidArgument},
+		{"power chain past depth limit", "2" + strings.Repeat("^2", 2000), nil, 0, codes.InvalidArgument},
+		{"parentheses past depth limit", strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100), nil, 0, codes.InvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := evaluate(&calculatorpb.EvaluateRequest{Expression: tc.src, Variables: tc.vars})
+			if code := status.Code(err); code != tc.code {
+				t.Fatalf("evaluate error = %v, want %v", err, tc.code)
+			}
+			if err == nil && got != tc.want {
+				t.Errorf("evaluate = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
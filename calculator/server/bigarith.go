@@ -0,0 +1,177 @@
+package main
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+)
+
+// Limits on CalculateBig, so one request cannot tie up the server building
+// an enormous number.
+const (
+	maxBigOperands     = 1000
+	maxBigOperandBytes = 10000
+	maxBigResultBits   = 1 << 20
+	defaultBigScale    = 20
+	maxBigScale        = 1000
+	maxBigExponent     = 100000
+)
+
+// parseBig parses a decimal string operand.
+func parseBig(i int, s string) (*big.Rat, error) {
+	if len(s) > maxBigOperandBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "operand %d is longer than %d bytes", i, maxBigOperandBytes)
+	}
+	//big.Rat also accepts fractions like "1/3"; operands are decimals only.
+	if strings.ContainsRune(s, '/') {
+		return nil, status.Errorf(codes.InvalidArgument, "operand %d %q is not a decimal number", i, s)
+	}
+	s = strings.TrimSpace(s)
+	//Check the exponent first: big.Rat expands "1e99999999" in full.
+	if e := strings.IndexAny(s, "eE"); e >= 0 {
+		n, err := strconv.Atoi(s[e+1:])
+		if err != nil || n > maxBigExponent || n < -maxBigExponent {
+			return nil, status.Errorf(codes.InvalidArgument, "operand %d %q has an exponent outside ±%d", i, s, maxBigExponent)
+		}
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "operand %d %q is not a decimal number", i, s)
+	}
+	if bigBits(r) > maxBigResultBits {
+		return nil, status.Errorf(codes.InvalidArgument, "operand %d is too large", i)
+	}
+	return r, nil
+}
+
+func bigBits(r *big.Rat) int {
+	return r.Num().BitLen() + r.Denom().BitLen()
+}
+
+// checkBig rejects intermediate results that have grown past the limit.
+func checkBig(r *big.Rat) error {
+	if bigBits(r) > maxBigResultBits {
+		return status.Errorf(codes.OutOfRange, "result exceeds %d bits", maxBigResultBits)
+	}
+	return nil
+}
+
+// powBig raises base to an integer exponent.
+func powBig(base, exp *big.Rat) (*big.Rat, error) {
+	if !exp.IsInt() {
+		return nil, status.Errorf(codes.InvalidArgument, "exponent %s must be an integer", exp.RatString())
+	}
+	e := exp.Num()
+	neg := e.Sign() < 0
+	if neg && base.Sign() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "division by zero")
+	}
+	if e.Sign() == 0 {
+		return new(big.Rat).SetInt64(1), nil
+	}
+	//|base| <= 1 stays small whatever the exponent.
+	if base.Sign() == 0 || base.Num().CmpAbs(base.Denom()) == 0 {
+		if e.Bit(0) == 0 {
+			return new(big.Rat).Abs(base), nil
+		}
+		return new(big.Rat).Set(base), nil
+	}
+	if e.CmpAbs(big.NewInt(maxBigResultBits)) > 0 || int64(bigBits(base)-2)*abs64(e.Int64()) > maxBigResultBits {
+		return nil, status.Errorf(codes.OutOfRange, "result exceeds %d bits", maxBigResultBits)
+	}
+	abs := new(big.Int).Abs(e)
+	num := new(big.Int).Exp(base.Num(), abs, nil)
+	den := new(big.Int).Exp(base.Denom(), abs, nil)
+	if neg {
+		num, den = den, num
+	}
+	r := new(big.Rat).SetFrac(num, den)
+	return r, checkBig(r)
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// formatBig renders r as a decimal with at most scale fractional digits,
+// reporting whether that is r exactly.
+func formatBig(r *big.Rat, scale int) (string, bool) {
+	if r.IsInt() {
+		return r.Num().String(), true
+	}
+	s := r.FloatString(scale)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" {
+		s = "0"
+	}
+	back, _ := new(big.Rat).SetString(s)
+	return s, back.Cmp(r) == 0
+}
+
+// calculateBig evaluates a CalculateBigRequest with big.Rat arithmetic.
+func calculateBig(req *calculatorpb.CalculateBigRequest) (*calculatorpb.CalculateBigResponse, error) {
+	scale := int(req.GetScale())
+	if scale == 0 {
+		scale = defaultBigScale
+	}
+	if scale > maxBigScale {
+		return nil, status.Errorf(codes.InvalidArgument, "scale %d is larger than %d", scale, maxBigScale)
+	}
+	ops := req.GetOperands()
+	if len(ops) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one operand is required")
+	}
+	if len(ops) > maxBigOperands {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d operands are allowed", maxBigOperands)
+	}
+	nums := make([]*big.Rat, len(ops))
+	for i, s := range ops {
+		r, err := parseBig(i, s)
+		if err != nil {
+			return nil, err
+		}
+		nums[i] = r
+	}
+
+	var result *big.Rat
+	switch op := req.GetOperation(); op {
+	case calculatorpb.Operation_ADD, calculatorpb.Operation_AVERAGE:
+		result = new(big.Rat)
+		for _, n := range nums {
+			if err := checkBig(result.Add(result, n)); err != nil {
+				return nil, err
+			}
+		}
+		if op == calculatorpb.Operation_AVERAGE {
+			result.Quo(result, new(big.Rat).SetInt64(int64(len(nums))))
+		}
+	case calculatorpb.Operation_MULTIPLY:
+		result = new(big.Rat).SetInt64(1)
+		for _, n := range nums {
+			if err := checkBig(result.Mul(result, n)); err != nil {
+				return nil, err
+			}
+		}
+	case calculatorpb.Operation_POWER:
+		if len(nums) != 2 {
+			return nil, status.Errorf(codes.InvalidArgument, "POWER takes a base and an exponent, got %d operands", len(nums))
+		}
+		var err error
+		if result, err = powBig(nums[0], nums[1]); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported operation %v", op)
+	}
+
+	s, exact := formatBig(result, scale)
+	return &calculatorpb.CalculateBigResponse{Result: s, Exact: exact}, nil
+}
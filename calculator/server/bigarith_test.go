@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+)
+
+func TestCalculateBig(t *testing.T) {
+	const (
+		add = calculatorpb.Operation_ADD
+		avg = calculatorpb.Operation_AVERAGE
+		mul = calculatorpb.Operation_MULTIPLY
+		pow = calculatorpb.Operation_POWER
+	)
+	tests := []struct {
+		name  string
+		op    calculatorpb.Operation
+		ops   []string
+		scale uint32
+		want  string
+		exact bool
+		code  codes.Code
+	}{
+		{"add past int64", add, []string{"9223372036854775807", "1"}, 0, "9223372036854775808", true, codes.OK},
+		{"add decimals exactly", add, []string{"0.1", "0.2"}, 0, "0.3", true, codes.OK},
+		{"add cancels to zero", add, []string{"-1.5", "1.5"}, 0, "0", true, codes.OK},
+		{"exponent notation", add, []string{"1e3", " 2.5E-1 "}, 0, "1000.25", true, codes.OK},
+		{"average", avg, []string{"1", "2"}, 0, "1.5", true, codes.OK},
+		{"average repeating", avg, []string{"1", "0", "0"}, 5, "0.33333", false, codes.OK},
+		{"default scale", avg, []string{"2", "0", "0"}, 0, "0." + strings.Repeat("6", defaultBigScale-1) + "7", false, codes.OK},
+		{"rounds to zero", avg, []string{"-0.001", "0"}, 2, "0", false, codes.OK},
+		{"multiply", mul, []string{"-1.5", "4", "1e-1"}, 0, "-0.6", true, codes.OK},
+		{"power", pow, []string{"2", "100"}, 0, "1267650600228229401496703205376", true, codes.OK},
+		{"negative power", pow, []string{"2", "-3"}, 0, "0.125", true, codes.OK},
+		{"fraction power", pow, []string{"-0.5", "3"}, 0, "-0.125", true, codes.OK},
+		{"one to a huge power", pow, []string{"1", "1e100000"}, 0, "1", true, codes.OK},
+		{"minus one to an odd power", pow, []string{"-1", "99999999999999999999"}, 0, "-1", true, codes.OK},
+		{"minus one to an even power", pow, []string{"-1", "1e20"}, 0, "1", true, codes.OK},
+		{"zero to the zero", pow, []string{"0", "0"}, 0, "1", true, codes.OK},
+		{"zero to a negative power", pow, []string{"0", "-1"}, 0, "", false, codes.InvalidArgument},
+		{"fractional exponent", pow, []string{"2", "0.5"}, 0, "", false, codes.InvalidArgument},
+		{"power too large", pow, []string{"2", "2000000"}, 0, "", false, codes.OutOfRange},
+		{"power just too large", pow, []string{"2", "1048577"}, 0, "", false, codes.OutOfRange},
+		{"power wrong arity", pow, []string{"2"}, 0, "", false, codes.InvalidArgument},
+		{"product too large", mul, []string{"1e100000", "1e100000", "1e100000", "1e100000"}, 0, "", false, codes.OutOfRange},
+		{"no operands", add, nil, 0, "", false, codes.InvalidArgument},
+		{"too many operands", add, make([]string, maxBigOperands+1), 0, "", false, codes.InvalidArgument},
+		{"operand too long", add, []string{strings.Repeat("9", maxBigOperandBytes+1)}, 0, "", false, codes.InvalidArgument},
+		{"fraction operand", add, []string{"1/3"}, 0, "", false, codes.InvalidArgument},
+		{"not a number", add, []string{"twelve"}, 0, "", false, codes.InvalidArgument},
+		{"huge exponent", add, []string{"1e99999999"}, 0, "", false, codes.InvalidArgument},
+		{"huge negative exponent", add, []string{"1e-100001"}, 0, "", false, codes.InvalidArgument},
+		{"scale too large", add, []string{"1"}, maxBigScale + 1, "", false, codes.InvalidArgument},
+		{"unsupported", calculatorpb.Operation_DIVIDE, []string{"1", "2"}, 0, "", false, codes.InvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := calculateBig(&calculatorpb.CalculateBigRequest{Operation: tc.op, Operands: tc.ops, Scale: tc.scale})
+			if code := status.Code(err); code != tc.code {
+				t.Fatalf("calculateBig error = %v, want %v", err, tc.code)
+			}
+			if err != nil {
+				return
+			}
+			if resp.GetResult() != tc.want || resp.GetExact() != tc.exact {
+				t.Errorf("calculateBig = %q exact=%v, want %q exact=%v", resp.GetResult(), resp.GetExact(), tc.want, tc.exact)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"math"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+	"github.com/jwfrizzell/grpc-go-course/calculator/units"
+)
+
+func unitProto(u units.Unit) *calculatorpb.Unit {
+	return &calculatorpb.Unit{
+		Name:      u.Name,
+		Symbol:    u.Symbol,
+		Dimension: u.Dimension,
+		Aliases:   u.Aliases,
+		Factor:    u.Factor,
+		Offset:    u.Offset,
+	}
+}
+
+func convert(reg *units.Registry, req *calculatorpb.ConvertRequest) (*calculatorpb.ConvertResponse, error) {
+	v := req.GetValue()
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return nil, status.Error(codes.InvalidArgument, "value is not finite")
+	}
+	result, from, to, err := reg.Convert(v, req.GetFrom(), req.GetTo())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if math.IsInf(result, 0) {
+		return nil, status.Error(codes.OutOfRange, "result is not finite")
+	}
+	return &calculatorpb.ConvertResponse{Value: result, From: unitProto(from), To: unitProto(to)}, nil
+}
+
+func listUnits(reg *units.Registry, req *calculatorpb.ListUnitsRequest) (*calculatorpb.ListUnitsResponse, error) {
+	list := reg.Units(req.GetDimension())
+	if len(list) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown dimension %q; known dimensions are %v", req.GetDimension(), reg.Dimensions())
+	}
+	resp := &calculatorpb.ListUnitsResponse{}
+	for _, u := range list {
+		resp.Units = append(resp.Units, unitProto(u))
+	}
+	return resp, nil
+}
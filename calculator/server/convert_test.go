@@ -0,0 +1,72 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+	"github.com/jwfrizzell/grpc-go-course/calculator/units"
+)
+
+func TestConvert(t *testing.T) {
+	reg := units.NewRegistry()
+	tests := []struct {
+		name     string
+		v        float64
+		from, to string
+		want     float64
+		code     codes.Code
+	}{
+		{"length", 2.5, "km", "m", 2500, codes.OK},
+		{"temperature", -40, "°C", "°F", -40, codes.OK},
+		{"folded names", 1, "Kilobytes", "BITS", 8000, codes.OK},
+		{"symbols keep case", 1, "Mb", "MB", 0.125, codes.OK},
+		{"unknown symbol case", 1, "Kb", "b", 0, codes.InvalidArgument},
+		{"unknown", 1, "parsec", "m", 0, codes.InvalidArgument},
+		{"different dimensions", 1, "m", "s", 0, codes.InvalidArgument},
+		{"infinite value", math.Inf(1), "m", "km", 0, codes.InvalidArgument},
+		{"NaN value", math.NaN(), "m", "km", 0, codes.InvalidArgument},
+		{"overflow", math.MaxFloat64, "PB", "b", 0, codes.OutOfRange},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := convert(reg, &calculatorpb.ConvertRequest{Value: tc.v, From: tc.from, To: tc.to})
+			if status.Code(err) != tc.code {
+				t.Fatalf("convert error = %v, want %v", err, tc.code)
+			}
+			if err == nil && math.Abs(resp.GetValue()-tc.want) > 1e-12*math.Max(1, math.Abs(tc.want)) {
+				t.Errorf("convert = %v, want %v", resp.GetValue(), tc.want)
+			}
+		})
+	}
+}
+
+func TestListUnits(t *testing.T) {
+	reg := units.NewRegistry()
+	tests := []struct {
+		dimension string
+		first     string
+		code      codes.Code
+	}{
+		{"", "bit", codes.OK},
+		{"data", "bit", codes.OK},
+		{"length", "nanometre", codes.OK},
+		{"temperature", "rankine", codes.OK},
+		{"Length", "", codes.InvalidArgument},
+		{"volume", "", codes.InvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.dimension, func(t *testing.T) {
+			resp, err := listUnits(reg, &calculatorpb.ListUnitsRequest{Dimension: tc.dimension})
+			if status.Code(err) != tc.code {
+				t.Fatalf("listUnits error = %v, want %v", err, tc.code)
+			}
+			if err == nil && resp.GetUnits()[0].GetName() != tc.first {
+				t.Errorf("first unit = %q, want %q", resp.GetUnits()[0].GetName(), tc.first)
+			}
+		})
+	}
+}
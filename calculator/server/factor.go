@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	//trialLimit bounds trial division; what is left after it is either
+	//prime or split with Pollard's rho.
+	trialLimit = 1 << 16
+	//maxFactorBits caps big_number inputs.
+	maxFactorBits = 4096
+	//checkEvery is how many loop iterations run between context checks.
+	checkEvery = 1024
+	//millerRabinRounds is passed to big.Int.ProbablyPrime.
+	millerRabinRounds = 20
+	//factorOp names prime decomposition in cancellation errors.
+	factorOp = "prime decomposition"
+)
+
+var (
+	bigOne = big.NewInt(1)
+	bigTwo = big.NewInt(2)
+)
+
+// ctxError converts a done context into the status a handler returns,
+// naming op, such as "prime decomposition", in the message.
+func ctxError(ctx context.Context, op string) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		return status.Errorf(codes.DeadlineExceeded, "%s ran out of time", op)
+	}
+	return status.Errorf(codes.Canceled, "%s cancelled", op)
+}
+
+// factor calls emit with the prime factors of n > 0 in ascending order,
+// with repeats. It gives up with a status error once ctx is done.
+func factor(ctx context.Context, n *big.Int, emit func(*big.Int) error) error {
+	n = new(big.Int).Set(n)
+	p, q, r := new(big.Int), new(big.Int), new(big.Int)
+
+	//Trial division by 2 and then odd numbers up to sqrt(n) or trialLimit.
+	for k, i := int64(2), 0; k < trialLimit; i++ {
+		if i%checkEvery == 0 {
+			if err := ctxError(ctx, factorOp); err != nil {
+				return err
+			}
+		}
+		p.SetInt64(k)
+		if q.Mul(p, p).Cmp(n) > 0 {
+			break
+		}
+		if q.QuoRem(n, p, r); r.Sign() == 0 {
+			n.Set(q)
+			if err := emit(new(big.Int).Set(p)); err != nil {
+				return err
+			}
+			continue
+		}
+		if k == 2 {
+			k = 3
+		} else {
+			k += 2
+		}
+	}
+	if n.Cmp(bigOne) == 0 {
+		return nil
+	}
+
+	//No factor below trialLimit, so every factor left is at least that and
+	//they can be sorted and sent after the split.
+	var rest []*big.Int
+	if err := split(ctx, n, &rest); err != nil {
+		return err
+	}
+	sort.Slice(rest, func(i, j int) bool { return rest[i].Cmp(rest[j]) < 0 })
+	for _, f := range rest {
+		if err := emit(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// split appends the prime factors of n to out.
+func split(ctx context.Context, n *big.Int, out *[]*big.Int) error {
+	if n.ProbablyPrime(millerRabinRounds) {
+		*out = append(*out, n)
+		return nil
+	}
+	d, err := rho(ctx, n)
+	if err != nil {
+		return err
+	}
+	if err := split(ctx, d, out); err != nil {
+		return err
+	}
+	return split(ctx, new(big.Int).Quo(n, d), out)
+}
+
+// rho finds a non-trivial divisor of the odd composite n with Brent's
+// variant of Pollard's rho, trying successive constants c in x^2+c until
+// one works.
+func rho(ctx context.Context, n *big.Int) (*big.Int, error) {
+	const batch = 128
+	x, y, ys := new(big.Int), new(big.Int), new(big.Int)
+	q, g, t := new(big.Int), new(big.Int), new(big.Int)
+	c := new(big.Int)
+	f := func(v *big.Int) {
+		v.Mul(v, v).Add(v, c).Mod(v, n)
+	}
+
+	for ci := int64(1); ; ci++ {
+		c.SetInt64(ci)
+		y.Set(bigTwo)
+		q.SetInt64(1)
+		g.SetInt64(1)
+		for r := 1; g.Cmp(bigOne) == 0; r *= 2 {
+			x.Set(y)
+			for i := 0; i < r; i++ {
+				if i%checkEvery == 0 {
+					if err := ctxError(ctx, factorOp); err != nil {
+						return nil, err
+					}
+				}
+				f(y)
+			}
+			for k := 0; k < r && g.Cmp(bigOne) == 0; k += batch {
+				if err := ctxError(ctx, factorOp); err != nil {
+					return nil, err
+				}
+				ys.Set(y)
+				for i := 0; i < batch && i < r-k; i++ {
+					f(y)
+					q.Mul(q, t.Sub(x, y).Abs(t)).Mod(q, n)
+				}
+				g.GCD(nil, nil, q, n)
+			}
+		}
+		if g.Cmp(n) == 0 {
+			//The batch overshot; step through it one at a time.
+			for {
+				f(ys)
+				if g.GCD(nil, nil, t.Sub(x, ys).Abs(t), n); g.Cmp(bigOne) != 0 {
+					break
+				}
+			}
+		}
+		if g.Cmp(n) != 0 {
+			return new(big.Int).Set(g), nil
+		}
+	}
+}
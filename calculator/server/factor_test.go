@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func bigInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("bad test number " + s)
+	}
+	return n
+}
+
+func factors(ctx context.Context, n *big.Int) ([]string, error) {
+	var got []string
+	err := factor(ctx, n, func(p *big.Int) error {
+		got = append(got, p.String())
+		return nil
+	})
+	return got, err
+}
+
+func TestFactor(t *testing.T) {
+	tests := []struct {
+		n    string
+		want []string
+	}{
+		{"1", nil},
+		{"2", []string{"2"}},
+		{"4", []string{"2", "2"}},
+		{"360", []string{"2", "2", "2", "3", "3", "5"}},
+		{"65521", []string{"65521"}},
+		{"65537", []string{"65537"}},
+		//561 is a Carmichael number.
+		{"561", []string{"3", "11", "17"}},
+		//Both factors are past trialLimit, so rho has to split them.
+		{"4295098369", []string{"65537", "65537"}},
+		{"281487861809153", []string{"65537", "65537", "65537"}},
+		{"4295491591", []string{"65537", "65543"}},
+		{"600851475143", []string{"71", "839", "1471", "6857"}},
+		{"9223372036854775807", []string{"7", "7", "73", "127", "337", "92737", "649657"}},
+		{"18446744073709551617", []string{"274177", "67280421310721"}},
+		{"1000000016000000063", []string{"1000000007", "1000000009"}},
+		{"170141183460469231731687303715884105727", []string{"170141183460469231731687303715884105727"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.n, func(t *testing.T) {
+			got, err := factors(context.Background(), bigInt(tc.n))
+			if err != nil {
+				t.Fatalf("factor: %v", err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Errorf("factor(%s) = %v, want %v", tc.n, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFactorStops(t *testing.T) {
+	//A product of two 96-bit primes takes rho far longer than the deadline.
+	hard := new(big.Int).Mul(bigInt("79228162514264337593543950397"), bigInt("79228162514264337593543950319"))
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	errStop := errors.New("stop")
+
+	none := func(*big.Int) error { return nil }
+	tests := []struct {
+		name string
+		ctx  context.Context
+		n    *big.Int
+		emit func(*big.Int) error
+		code codes.Code
+		err  error //when set, the exact error wanted
+	}{
+		{"cancelled", cancelled, big.NewInt(360), none, codes.Canceled, nil},
+		{"deadline", expired, hard, none, codes.DeadlineExceeded, nil},
+		{"emit error in trial division", context.Background(), big.NewInt(360), func(*big.Int) error { return errStop }, codes.Unknown, errStop},
+		{"emit error after split", context.Background(), bigInt("4295491591"), func(*big.Int) error { return errStop }, codes.Unknown, errStop},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := factor(tc.ctx, tc.n, tc.emit)
+			if status.Code(err) != tc.code || tc.err != nil && err != tc.err {
+				t.Errorf("factor error = %v, want %v", err, tc.code)
+			}
+			if tc.err == nil && !strings.Contains(err.Error(), factorOp) {
+				t.Errorf("factor error %q does not name %s", err, factorOp)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"math"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+)
+
+// maxMatrixDim caps rows and columns; the cubic algorithms stay well under
+// a second at this size.
+const maxMatrixDim = 512
+
+var errSingular = status.Error(codes.FailedPrecondition, "matrix is singular")
+
+// matrix is a dense row-major matrix.
+type matrix struct {
+	rows, cols int
+	data       []float64
+}
+
+func newMatrix(rows, cols int) *matrix {
+	return &matrix{rows: rows, cols: cols, data: make([]float64, rows*cols)}
+}
+
+func (m *matrix) at(i, j int) float64 { return m.data[i*m.cols+j] }
+
+func (m *matrix) proto() *calculatorpb.Matrix {
+	return &calculatorpb.Matrix{Rows: uint32(m.rows), Cols: uint32(m.cols), Values: m.data}
+}
+
+func checkFinite(name string, values []float64) error {
+	for i, v := range values {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return status.Errorf(codes.InvalidArgument, "%s[%d] is not finite", name, i)
+		}
+	}
+	return nil
+}
+
+// checkResult rejects results that overflowed along the way.
+func checkResult(values ...float64) error {
+	for _, v := range values {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return status.Error(codes.OutOfRange, "result is not finite")
+		}
+	}
+	return nil
+}
+
+func toMatrix(name string, pm *calculatorpb.Matrix) (*matrix, error) {
+	r, c := pm.GetRows(), pm.GetCols()
+	if r == 0 || c == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "%s must have at least one row and column", name)
+	}
+	if r > maxMatrixDim || c > maxMatrixDim {
+		return nil, status.Errorf(codes.InvalidArgument, "%s is %dx%d; at most %d rows and columns are allowed", name, r, c, maxMatrixDim)
+	}
+	if len(pm.GetValues()) != int(r*c) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s is %dx%d but has %d values", name, r, c, len(pm.GetValues()))
+	}
+	if err := checkFinite(name, pm.GetValues()); err != nil {
+		return nil, err
+	}
+	return &matrix{rows: int(r), cols: int(c), data: pm.GetValues()}, nil
+}
+
+func toSquare(name string, pm *calculatorpb.Matrix) (*matrix, error) {
+	m, err := toMatrix(name, pm)
+	if err != nil {
+		return nil, err
+	}
+	if m.rows != m.cols {
+		return nil, status.Errorf(codes.InvalidArgument, "%s is %dx%d, not square", name, m.rows, m.cols)
+	}
+	return m, nil
+}
+
+func toVector(name string, v *calculatorpb.Vector) ([]float64, error) {
+	if len(v.GetValues()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "%s is empty", name)
+	}
+	if len(v.GetValues()) > maxMatrixDim*maxMatrixDim {
+		return nil, status.Errorf(codes.InvalidArgument, "%s has more than %d values", name, maxMatrixDim*maxMatrixDim)
+	}
+	return v.GetValues(), checkFinite(name, v.GetValues())
+}
+
+func dotProduct(req *calculatorpb.DotProductRequest) (float64, error) {
+	a, err := toVector("a", req.GetA())
+	if err != nil {
+		return 0, err
+	}
+	b, err := toVector("b", req.GetB())
+	if err != nil {
+		return 0, err
+	}
+	if len(a) != len(b) {
+		return 0, status.Errorf(codes.InvalidArgument, "a has %d values but b has %d", len(a), len(b))
+	}
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum, checkResult(sum)
+}
+
+func matrixMultiply(req *calculatorpb.MatrixMultiplyRequest) (*matrix, error) {
+	a, err := toMatrix("a", req.GetA())
+	if err != nil {
+		return nil, err
+	}
+	b, err := toMatrix("b", req.GetB())
+	if err != nil {
+		return nil, err
+	}
+	if a.cols != b.rows {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot multiply %dx%d by %dx%d", a.rows, a.cols, b.rows, b.cols)
+	}
+	out := newMatrix(a.rows, b.cols)
+	for i := 0; i < a.rows; i++ {
+		row := out.data[i*out.cols : (i+1)*out.cols]
+		for k := 0; k < a.cols; k++ {
+			aik := a.at(i, k)
+			for j, bkj := range b.data[k*b.cols : (k+1)*b.cols] {
+				row[j] += aik * bkj
+			}
+		}
+	}
+	return out, checkResult(out.data...)
+}
+
+func transpose(pm *calculatorpb.Matrix) (*matrix, error) {
+	m, err := toMatrix("matrix", pm)
+	if err != nil {
+		return nil, err
+	}
+	out := newMatrix(m.cols, m.rows)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			out.data[j*out.cols+i] = m.at(i, j)
+		}
+	}
+	return out, nil
+}
+
+// lu is an LU decomposition with partial pivoting: rows perm of the input
+// equal L*U, with L's unit diagonal left implicit.
+type lu struct {
+	n    int
+	a    []float64 //L below the diagonal, U on and above it
+	perm []int
+	sign float64
+	//singular is set when a pivot is small enough relative to the input
+	//that the matrix is numerically singular.
+	singular bool
+}
+
+func decompose(m *matrix) *lu {
+	n := m.rows
+	f := &lu{n: n, a: append([]float64(nil), m.data...), perm: make([]int, n), sign: 1}
+	var scale float64
+	for _, v := range m.data {
+		scale = math.Max(scale, math.Abs(v))
+	}
+	tol := float64(n) * scale * 0x1p-52
+	for i := range f.perm {
+		f.perm[i] = i
+	}
+	a := f.a
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a[i*n+k]) > math.Abs(a[p*n+k]) {
+				p = i
+			}
+		}
+		if p != k {
+			for j := 0; j < n; j++ {
+				a[k*n+j], a[p*n+j] = a[p*n+j], a[k*n+j]
+			}
+			f.perm[k], f.perm[p] = f.perm[p], f.perm[k]
+			f.sign = -f.sign
+		}
+		pivot := a[k*n+k]
+		if math.Abs(pivot) <= tol {
+			f.singular = true
+		}
+		if pivot == 0 {
+			continue
+		}
+		for i := k + 1; i < n; i++ {
+			l := a[i*n+k] / pivot
+			a[i*n+k] = l
+			if l == 0 {
+				continue
+			}
+			for j := k + 1; j < n; j++ {
+				a[i*n+j] -= l * a[k*n+j]
+			}
+		}
+	}
+	return f
+}
+
+// determinant returns zero for a numerically singular matrix rather than
+// the rounding noise its pivots multiply out to.
+func (f *lu) determinant() float64 {
+	if f.singular {
+		return 0
+	}
+	det := f.sign
+	for k := 0; k < f.n; k++ {
+		det *= f.a[k*f.n+k]
+	}
+	return det
+}
+
+// solve returns x with A x = b. The decomposition must be non-singular.
+func (f *lu) solve(b []float64) []float64 {
+	n, a := f.n, f.a
+	x := make([]float64, n)
+	for i, p := range f.perm {
+		x[i] = b[p]
+	}
+	for i := 0; i < n; i++ {
+		for k := 0; k < i; k++ {
+			x[i] -= a[i*n+k] * x[k]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		for k := i + 1; k < n; k++ {
+			x[i] -= a[i*n+k] * x[k]
+		}
+		x[i] /= a[i*n+i]
+	}
+	return x
+}
+
+func determinant(pm *calculatorpb.Matrix) (float64, error) {
+	m, err := toSquare("matrix", pm)
+	if err != nil {
+		return 0, err
+	}
+	det := decompose(m).determinant()
+	return det, checkResult(det)
+}
+
+func inverse(pm *calculatorpb.Matrix) (*matrix, error) {
+	m, err := toSquare("matrix", pm)
+	if err != nil {
+		return nil, err
+	}
+	f := decompose(m)
+	if f.singular {
+		return nil, errSingular
+	}
+	n := m.rows
+	out := newMatrix(n, n)
+	e := make([]float64, n)
+	for j := 0; j < n; j++ {
+		clear(e)
+		e[j] = 1
+		for i, v := range f.solve(e) {
+			out.data[i*n+j] = v
+		}
+	}
+	return out, checkResult(out.data...)
+}
+
+func solve(req *calculatorpb.SolveRequest) ([]float64, error) {
+	m, err := toSquare("a", req.GetA())
+	if err != nil {
+		return nil, err
+	}
+	b, err := toVector("b", req.GetB())
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != m.rows {
+		return nil, status.Errorf(codes.InvalidArgument, "a is %dx%d but b has %d values", m.rows, m.cols, len(b))
+	}
+	f := decompose(m)
+	if f.singular {
+		return nil, errSingular
+	}
+	x := f.solve(b)
+	return x, checkResult(x...)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+)
+
+func pbMatrix(rows, cols uint32, values ...float64) *calculatorpb.Matrix {
+	return &calculatorpb.Matrix{Rows: rows, Cols: cols, Values: values}
+}
+
+func closeTo(got, want []float64, tol float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > tol*math.Max(1, math.Abs(want[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// eps is the double spacing at 1, the unit the singularity tolerance in
+// decompose is measured in.
+const eps = 0x1p-52
+
+func TestDeterminant(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *calculatorpb.Matrix
+		want float64
+		code codes.Code
+	}{
+		{"1x1", pbMatrix(1, 1, -3), -3, codes.OK},
+		{"identity", pbMatrix(3, 3, 1, 0, 0, 0, 1, 0, 0, 0, 1), 1, codes.OK},
+		{"row swap flips sign", pbMatrix(2, 2, 0, 1, 1, 0), -1, codes.OK},
+		{"3x3", pbMatrix(3, 3, 2, -3, 1, 2, 0, -1, 1, 4, 5), 49, codes.OK},
+		{"zero", pbMatrix(2, 2, 0, 0, 0, 0), 0, codes.OK},
+		{"repeated row", pbMatrix(3, 3, 1, 2, 3, 4, 5, 6, 1, 2, 3), 0, codes.OK},
+		//Exact arithmetic gives 0; without the tolerance the pivots
+		//multiply out to rounding noise.
+		{"rank deficient", pbMatrix(3, 3, 1, 2, 3, 4, 5, 6, 7, 8, 9), 0, codes.OK},
+		{"pivot at tolerance", pbMatrix(2, 2, 1, 1, 1, 1+eps), 0, codes.OK},
+		{"pivot past tolerance", pbMatrix(2, 2, 1, 1, 1, 1+4*eps), 4 * eps, codes.OK},
+		{"tolerance is relative", pbMatrix(2, 2, 1e-150, 0, 0, 1e-150), 1e-300, codes.OK},
+		{"overflow", pbMatrix(2, 2, 1e200, 0, 0, 1e200), 0, codes.OutOfRange},
+		{"not square", pbMatrix(2, 3, 1, 2, 3, 4, 5, 6), 0, codes.InvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := determinant(tc.m)
+			if status.Code(err) != tc.code {
+				t.Fatalf("determinant error = %v, want %v", err, tc.code)
+			}
+			if err == nil && math.Abs(got-tc.want) > 1e-12*math.Abs(tc.want) {
+				t.Errorf("determinant = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestInverse(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *calculatorpb.Matrix
+		want []float64
+		code codes.Code
+	}{
+		{"2x2", pbMatrix(2, 2, 4, 7, 2, 6), []float64{0.6, -0.7, -0.2, 0.4}, codes.OK},
+		{"needs pivoting", pbMatrix(2, 2, 0, 1, 1, 0), []float64{0, 1, 1, 0}, codes.OK},
+		{"tiny but regular", pbMatrix(2, 2, 1e-200, 0, 0, 2e-200), []float64{1e200, 0, 0, 5e199}, codes.OK},
+		{"ill conditioned but regular", pbMatrix(2, 2, 1, 1, 1, 1+1e-10), []float64{1 + 1e10, -1e10, -1e10, 1e10}, codes.OK},
+		{"singular", pbMatrix(2, 2, 1, 2, 2, 4), nil, codes.FailedPrecondition},
+		{"rank deficient", pbMatrix(3, 3, 1, 2, 3, 4, 5, 6, 7, 8, 9), nil, codes.FailedPrecondition},
+		{"near singular", pbMatrix(2, 2, 1, 1, 1, 1+eps), nil, codes.FailedPrecondition},
+		{"zero", pbMatrix(1, 1, 0), nil, codes.FailedPrecondition},
+		{"badly scaled", pbMatrix(2, 2, 1e-300, 0, 0, 1e300), nil, codes.FailedPrecondition},
+		{"inverse overflows", pbMatrix(1, 1, 1e-310), nil, codes.OutOfRange},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := inverse(tc.m)
+			if status.Code(err) != tc.code {
+				t.Fatalf("inverse error = %v, want %v", err, tc.code)
+			}
+			if err == nil && !closeTo(got.data, tc.want, 1e-5) {
+				t.Errorf("inverse = %v, want %v", got.data, tc.want)
+			}
+		})
+	}
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name string
+		a    *calculatorpb.Matrix
+		b    []float64
+		want []float64
+		code codes.Code
+	}{
+		{"3x3", pbMatrix(3, 3, 2, 1, -1, -3, -1, 2, -2, 1, 2), []float64{8, -11, -3}, []float64{2, 3, -1}, codes.OK},
+		{"zero leading pivot", pbMatrix(2, 2, 0, 2, 3, 0), []float64{4, 9}, []float64{3, 2}, codes.OK},
+		{"singular", pbMatrix(2, 2, 1, 2, 2, 4), []float64{1, 2}, nil, codes.FailedPrecondition},
+		{"near singular", pbMatrix(2, 2, 1, 1, 1, 1+eps), []float64{2, 2}, nil, codes.FailedPrecondition},
+		{"wrong length", pbMatrix(2, 2, 1, 0, 0, 1), []float64{1}, nil, codes.InvalidArgument},
+		{"not finite", pbMatrix(1, 1, 1), []float64{math.Inf(1)}, nil, codes.InvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := solve(&calculatorpb.SolveRequest{A: tc.a, B: &calculatorpb.Vector{Values: tc.b}})
+			if status.Code(err) != tc.code {
+				t.Fatalf("solve error = %v, want %v", err, tc.code)
+			}
+			if err == nil && !closeTo(got, tc.want, 1e-12) {
+				t.Errorf("solve = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatrixShapes(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *calculatorpb.Matrix
+		want []float64
+		code codes.Code
+	}{
+		{"2x3 by 3x1", pbMatrix(2, 3, 1, 2, 3, 4, 5, 6), pbMatrix(3, 1, 1, 0, -1), []float64{-2, -2}, codes.OK},
+		{"mismatch", pbMatrix(2, 3, 1, 2, 3, 4, 5, 6), pbMatrix(2, 1, 1, 1), nil, codes.InvalidArgument},
+		{"empty", pbMatrix(0, 0), pbMatrix(1, 1, 1), nil, codes.InvalidArgument},
+		{"too large", pbMatrix(maxMatrixDim+1, 1, make([]float64, maxMatrixDim+1)...), pbMatrix(1, 1, 1), nil, codes.InvalidArgument},
+		{"wrong value count", pbMatrix(2, 2, 1, 2, 3), pbMatrix(2, 1, 1, 1), nil, codes.InvalidArgument},
+		{"NaN", pbMatrix(1, 1, math.NaN()), pbMatrix(1, 1, 1), nil, codes.InvalidArgument},
+		{"overflow", pbMatrix(1, 2, 1e300, 1e300), pbMatrix(2, 1, 1e300, 1), nil, codes.OutOfRange},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := matrixMultiply(&calculatorpb.MatrixMultiplyRequest{A: tc.a, B: tc.b})
+			if status.Code(err) != tc.code {
+				t.Fatalf("matrixMultiply error = %v, want %v", err, tc.code)
+			}
+			if err == nil && !closeTo(got.data, tc.want, 0) {
+				t.Errorf("matrixMultiply = %v, want %v", got.data, tc.want)
+			}
+		})
+	}
+
+	tr, err := transpose(pbMatrix(2, 3, 1, 2, 3, 4, 5, 6))
+	if err != nil || tr.rows != 3 || tr.cols != 2 || !closeTo(tr.data, []float64{1, 4, 2, 5, 3, 6}, 0) {
+		t.Errorf("transpose = %+v, %v", tr, err)
+	}
+	if _, err := dotProduct(&calculatorpb.DotProductRequest{A: &calculatorpb.Vector{Values: []float64{1, 2}}, B: &calculatorpb.Vector{Values: []float64{3}}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("dotProduct of different lengths: error = %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"math/big"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+)
+
+// Limits on the number theory RPCs.
+const (
+	maxIntegerBits  = 4096
+	maxIntegerCount = 1000
+)
+
+// integers converts request Integers to big.Ints, whichever form they came
+// in, and reports whether any came as a big_value so the result can answer
+// in kind.
+type integers struct {
+	big bool
+}
+
+func (in *integers) get(name string, i *calculatorpb.Integer) (*big.Int, error) {
+	switch v := i.GetValue().(type) {
+	case *calculatorpb.Integer_IntValue:
+		return big.NewInt(v.IntValue), nil
+	case *calculatorpb.Integer_BigValue:
+		in.big = true
+		if len(v.BigValue) > maxIntegerBits/3 {
+			return nil, status.Errorf(codes.InvalidArgument, "%s must be at most %d bits", name, maxIntegerBits)
+		}
+		n, ok := new(big.Int).SetString(v.BigValue, 10)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "%s %q is not a decimal integer", name, v.BigValue)
+		}
+		if n.BitLen() > maxIntegerBits {
+			return nil, status.Errorf(codes.InvalidArgument, "%s must be at most %d bits", name, maxIntegerBits)
+		}
+		return n, nil
+	}
+	return nil, status.Errorf(codes.InvalidArgument, "%s is missing", name)
+}
+
+func (in *integers) modulus(i *calculatorpb.Integer) (*big.Int, error) {
+	m, err := in.get("modulus", i)
+	if err != nil {
+		return nil, err
+	}
+	if m.Sign() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "modulus must be positive, got %s", m)
+	}
+	return m, nil
+}
+
+// result converts n back to the form the inputs used.
+func (in *integers) result(n *big.Int) (*calculatorpb.Integer, error) {
+	if in.big {
+		return &calculatorpb.Integer{Value: &calculatorpb.Integer_BigValue{BigValue: n.String()}}, nil
+	}
+	if !n.IsInt64() {
+		return nil, status.Errorf(codes.OutOfRange, "result %s does not fit in an int64; send big_value inputs", n)
+	}
+	return &calculatorpb.Integer{Value: &calculatorpb.Integer_IntValue{IntValue: n.Int64()}}, nil
+}
+
+func (in *integers) list(numbers []*calculatorpb.Integer) ([]*big.Int, error) {
+	if len(numbers) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one number is required")
+	}
+	if len(numbers) > maxIntegerCount {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d numbers are allowed", maxIntegerCount)
+	}
+	out := make([]*big.Int, len(numbers))
+	for i, n := range numbers {
+		v, err := in.get("number", n)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "numbers[%d]: %s", i, status.Convert(err).Message())
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func gcd(req *calculatorpb.IntegerListRequest) (*calculatorpb.Integer, error) {
+	var in integers
+	nums, err := in.list(req.GetNumbers())
+	if err != nil {
+		return nil, err
+	}
+	g := new(big.Int)
+	for _, n := range nums {
+		g.GCD(nil, nil, g, n)
+	}
+	return in.result(g)
+}
+
+func lcm(req *calculatorpb.IntegerListRequest) (*calculatorpb.Integer, error) {
+	var in integers
+	nums, err := in.list(req.GetNumbers())
+	if err != nil {
+		return nil, err
+	}
+	l := big.NewInt(1)
+	g := new(big.Int)
+	for _, n := range nums {
+		if n.Sign() == 0 {
+			return in.result(new(big.Int))
+		}
+		//lcm(l, n) = l / gcd(l, n) * |n|
+		g.GCD(nil, nil, l, n)
+		l.Quo(l, g).Mul(l, new(big.Int).Abs(n))
+	}
+	return in.result(l)
+}
+
+// noInverse is the FailedPrecondition for a number without an inverse.
+func noInverse(n, m *big.Int) error {
+	g := new(big.Int).GCD(nil, nil, n, m)
+	return status.Errorf(codes.FailedPrecondition, "%s has no inverse modulo %s; their gcd is %s", n, m, g)
+}
+
+func modPow(req *calculatorpb.ModPowRequest) (*calculatorpb.Integer, error) {
+	var in integers
+	base, err := in.get("base", req.GetBase())
+	if err != nil {
+		return nil, err
+	}
+	exp, err := in.get("exponent", req.GetExponent())
+	if err != nil {
+		return nil, err
+	}
+	m, err := in.modulus(req.GetModulus())
+	if err != nil {
+		return nil, err
+	}
+	if m.Cmp(bigOne) == 0 {
+		//Every number is 0 modulo 1, including any inverse.
+		return in.result(new(big.Int))
+	}
+	b := new(big.Int).Mod(base, m)
+	if exp.Sign() < 0 {
+		if b.ModInverse(b, m) == nil {
+			return nil, noInverse(base, m)
+		}
+		exp = new(big.Int).Neg(exp)
+	}
+	return in.result(b.Exp(b, exp, m))
+}
+
+func modInverse(req *calculatorpb.ModInverseRequest) (*calculatorpb.Integer, error) {
+	var in integers
+	n, err := in.get("number", req.GetNumber())
+	if err != nil {
+		return nil, err
+	}
+	m, err := in.modulus(req.GetModulus())
+	if err != nil {
+		return nil, err
+	}
+	if m.Cmp(bigOne) == 0 {
+		//Every number is 0 modulo 1, including any inverse.
+		return in.result(new(big.Int))
+	}
+	inv := new(big.Int).Mod(n, m)
+	if inv.ModInverse(inv, m) == nil {
+		return nil, noInverse(n, m)
+	}
+	return in.result(inv)
+}
+
+func extendedGCD(req *calculatorpb.ExtendedGCDRequest) (*calculatorpb.ExtendedGCDResponse, error) {
+	var in integers
+	a, err := in.get("a", req.GetA())
+	if err != nil {
+		return nil, err
+	}
+	b, err := in.get("b", req.GetB())
+	if err != nil {
+		return nil, err
+	}
+	x, y := new(big.Int), new(big.Int)
+	g := new(big.Int).GCD(x, y, a, b)
+	resp := &calculatorpb.ExtendedGCDResponse{}
+	if resp.Gcd, err = in.result(g); err != nil {
+		return nil, err
+	}
+	if resp.X, err = in.result(x); err != nil {
+		return nil, err
+	}
+	if resp.Y, err = in.result(y); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+)
+
+func intValue(v int64) *calculatorpb.Integer {
+	return &calculatorpb.Integer{Value: &calculatorpb.Integer_IntValue{IntValue: v}}
+}
+
+func bigValue(s string) *calculatorpb.Integer {
+	return &calculatorpb.Integer{Value: &calculatorpb.Integer_BigValue{BigValue: s}}
+}
+
+// integerString renders i as the test tables write it, with big values
+// prefixed "big:" so answering in the wrong form is caught.
+func integerString(i *calculatorpb.Integer) string {
+	if v, ok := i.GetValue().(*calculatorpb.Integer_BigValue); ok {
+		return "big:" + v.BigValue
+	}
+	return strconv.FormatInt(i.GetIntValue(), 10)
+}
+
+func TestModPow(t *testing.T) {
+	tests := []struct {
+		name           string
+		base, exp, mod *calculatorpb.Integer
+		want           string
+		code           codes.Code
+	}{
+		{"small", intValue(4), intValue(13), intValue(497), "445", codes.OK},
+		{"zero exponent", intValue(0), intValue(0), intValue(7), "1", codes.OK},
+		{"negative base", intValue(-2), intValue(3), intValue(5), "2", codes.OK},
+		{"negative exponent", intValue(3), intValue(-1), intValue(7), "5", codes.OK},
+		{"negative exponent power", intValue(3), intValue(-2), intValue(7), "4", codes.OK},
+		{"negative base and exponent", intValue(-3), intValue(-1), intValue(7), "2", codes.OK},
+		{"negative exponent without inverse", intValue(2), intValue(-1), intValue(4), "", codes.FailedPrecondition},
+		{"zero to a negative exponent", intValue(0), intValue(-1), intValue(7), "", codes.FailedPrecondition},
+		{"modulus one", intValue(5), intValue(3), intValue(1), "0", codes.OK},
+		{"modulus one zero exponent", intValue(5), intValue(0), intValue(1), "0", codes.OK},
+		//Modulo 1 every number is 0, so there is an inverse even for 0.
+		{"modulus one negative exponent", intValue(0), intValue(-5), intValue(1), "0", codes.OK},
+		{"modulus one big", bigValue("7"), intValue(-1), intValue(1), "big:0", codes.OK},
+		{"zero modulus", intValue(2), intValue(3), intValue(0), "", codes.InvalidArgument},
+		{"negative modulus", intValue(2), intValue(3), intValue(-5), "", codes.InvalidArgument},
+		{"int64 extremes", intValue(math.MinInt64), intValue(math.MaxInt64), intValue(math.MaxInt64), "9223372036854775806", codes.OK},
+		{"big answers big", bigValue("2"), intValue(127), bigValue("170141183460469231731687303715884105727"), "big:1", codes.OK},
+		{"big exponent", intValue(2), bigValue("-170141183460469231731687303715884105726"), intValue(1000003), "big:291774", codes.OK},
+		{"missing", nil, intValue(1), intValue(2), "", codes.InvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := modPow(&calculatorpb.ModPowRequest{Base: tc.base, Exponent: tc.exp, Modulus: tc.mod})
+			if status.Code(err) != tc.code {
+				t.Fatalf("modPow error = %v, want %v", err, tc.code)
+			}
+			if err == nil && integerString(got) != tc.want {
+				t.Errorf("modPow = %s, want %s", integerString(got), tc.want)
+			}
+		})
+	}
+}
+
+func TestModInverse(t *testing.T) {
+	tests := []struct {
+		name   string
+		n, mod *calculatorpb.Integer
+		want   string
+		code   codes.Code
+	}{
+		{"small", intValue(3), intValue(11), "4", codes.OK},
+		{"negative", intValue(-3), intValue(11), "7", codes.OK},
+		{"larger than modulus", intValue(14), intValue(11), "4", codes.OK},
+		{"one", intValue(1), intValue(2), "1", codes.OK},
+		{"modulus one", intValue(0), intValue(1), "0", codes.OK},
+		{"modulus one negative", intValue(-9), intValue(1), "0", codes.OK},
+		{"modulus one big", bigValue("9"), intValue(1), "big:0", codes.OK},
+		{"shared factor", intValue(6), intValue(9), "", codes.FailedPrecondition},
+		{"zero", intValue(0), intValue(7), "", codes.FailedPrecondition},
+		{"zero modulus", intValue(3), intValue(0), "", codes.InvalidArgument},
+		{"big", bigValue("2"), bigValue("170141183460469231731687303715884105727"), "big:85070591730234615865843651857942052864", codes.OK},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := modInverse(&calculatorpb.ModInverseRequest{Number: tc.n, Modulus: tc.mod})
+			if status.Code(err) != tc.code {
+				t.Fatalf("modInverse error = %v, want %v", err, tc.code)
+			}
+			if err == nil && integerString(got) != tc.want {
+				t.Errorf("modInverse = %s, want %s", integerString(got), tc.want)
+			}
+		})
+	}
+}
+
+func TestGCDAndLCM(t *testing.T) {
+	tests := []struct {
+		name     string
+		numbers  []*calculatorpb.Integer
+		gcd, lcm string
+		code     codes.Code
+	}{
+		{"pair", []*calculatorpb.Integer{intValue(12), intValue(18)}, "6", "36", codes.OK},
+		{"negative", []*calculatorpb.Integer{intValue(-4), intValue(6)}, "2", "12", codes.OK},
+		{"with zero", []*calculatorpb.Integer{intValue(0), intValue(5)}, "5", "0", codes.OK},
+		{"single", []*calculatorpb.Integer{intValue(-7)}, "7", "7", codes.OK},
+		{"big", []*calculatorpb.Integer{bigValue("18446744073709551616"), intValue(6)}, "big:2", "big:55340232221128654848", codes.OK},
+		{"none", nil, "", "", codes.InvalidArgument},
+		{"too many", make([]*calculatorpb.Integer, maxIntegerCount+1), "", "", codes.InvalidArgument},
+		{"not a number", []*calculatorpb.Integer{bigValue("1.5")}, "", "", codes.InvalidArgument},
+		{"too many bits", []*calculatorpb.Integer{bigValue("1" + strings.Repeat("0", maxIntegerBits/3))}, "", "", codes.InvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &calculatorpb.IntegerListRequest{Numbers: tc.numbers}
+			g, err := gcd(req)
+			if status.Code(err) != tc.code {
+				t.Fatalf("gcd error = %v, want %v", err, tc.code)
+			}
+			l, err := lcm(req)
+			if status.Code(err) != tc.code {
+				t.Fatalf("lcm error = %v, want %v", err, tc.code)
+			}
+			if tc.code != codes.OK {
+				return
+			}
+			if integerString(g) != tc.gcd || integerString(l) != tc.lcm {
+				t.Errorf("gcd, lcm = %s, %s, want %s, %s", integerString(g), integerString(l), tc.gcd, tc.lcm)
+			}
+		})
+	}
+
+	//An int64 result that does not fit asks for big_value inputs.
+	_, err := lcm(&calculatorpb.IntegerListRequest{Numbers: []*calculatorpb.Integer{intValue(math.MaxInt64), intValue(2)}})
+	if status.Code(err) != codes.OutOfRange {
+		t.Errorf("lcm overflowing int64: error = %v, want %v", err, codes.OutOfRange)
+	}
+}
+
+func TestExtendedGCD(t *testing.T) {
+	tests := []struct {
+		a, b int64
+		gcd  int64
+	}{
+		{240, 46, 2},
+		{-240, 46, 2},
+		{17, 0, 17},
+		{0, 0, 0},
+		{math.MaxInt64, math.MaxInt64 - 1, 1},
+	}
+	for _, tc := range tests {
+		t.Run(strconv.FormatInt(tc.a, 10)+","+strconv.FormatInt(tc.b, 10), func(t *testing.T) {
+			resp, err := extendedGCD(&calculatorpb.ExtendedGCDRequest{A: intValue(tc.a), B: intValue(tc.b)})
+			if err != nil {
+				t.Fatalf("extendedGCD: %v", err)
+			}
+			g, x, y := resp.GetGcd().GetIntValue(), resp.GetX().GetIntValue(), resp.GetY().GetIntValue()
+			if g != tc.gcd {
+				t.Errorf("gcd = %d, want %d", g, tc.gcd)
+			}
+			//Bezout: a*x + b*y = gcd, checked modulo 2^64 which is exact
+			//here since the true sum is small.
+			if tc.a*x+tc.b*y != g {
+				t.Errorf("%d*%d + %d*%d != %d", tc.a, x, tc.b, y, g)
+			}
+		})
+	}
+}
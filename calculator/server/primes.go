@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"math"
+	"math/bits"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxPrime64 is the largest prime that fits in a uint64.
+const maxPrime64 = 18446744073709551557
+
+// Sieve sizes for GeneratePrimes. Base primes stop at sieveBaseLimit, so
+// ranges past sieveBaseLimit^2 confirm sieve survivors with Miller-Rabin.
+const (
+	sieveSegment   = 1 << 15
+	sieveBaseLimit = 1 << 20
+)
+
+// millerRabinBases make Miller-Rabin deterministic for every n < 2^64.
+var millerRabinBases = []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, m)
+}
+
+func powMod(base, exp, m uint64) uint64 {
+	result := uint64(1)
+	base %= m
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result = mulMod(result, base, m)
+		}
+		base = mulMod(base, base, m)
+	}
+	return result
+}
+
+// isPrime64 is a deterministic Miller-Rabin test.
+func isPrime64(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+	for _, p := range millerRabinBases {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+	d, s := n-1, 0
+	for d&1 == 0 {
+		d >>= 1
+		s++
+	}
+	for _, a := range millerRabinBases {
+		x := powMod(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+		composite := true
+		for i := 1; i < s && composite; i++ {
+			x = mulMod(x, x, n)
+			composite = x != n-1
+		}
+		if composite {
+			return false
+		}
+	}
+	return true
+}
+
+// nextPrime returns the smallest prime greater than n.
+func nextPrime(n uint64) (uint64, error) {
+	if n >= maxPrime64 {
+		return 0, status.Errorf(codes.OutOfRange, "no prime after %d fits in 64 bits", n)
+	}
+	if n < 2 {
+		return 2, nil
+	}
+	c := n + 1
+	if c&1 == 0 {
+		c++
+	}
+	for !isPrime64(c) {
+		c += 2
+	}
+	return c, nil
+}
+
+// sievePrimes returns the primes up to and including limit.
+func sievePrimes(limit uint64) []uint64 {
+	composite := make([]bool, limit+1)
+	var primes []uint64
+	for i := uint64(2); i <= limit; i++ {
+		if composite[i] {
+			continue
+		}
+		primes = append(primes, i)
+		for j := i * i; j <= limit; j += i {
+			composite[j] = true
+		}
+	}
+	return primes
+}
+
+// isqrt returns floor(sqrt(n)) for n < 2^62.
+func isqrt(n uint64) uint64 {
+	r := uint64(math.Sqrt(float64(n)))
+	for r*r > n {
+		r--
+	}
+	for (r+1)*(r+1) <= n {
+		r++
+	}
+	return r
+}
+
+// generatePrimes calls emit with the primes in [from, to], a segment at a
+// time and in ascending order. The next segment is only sieved once emit
+// returns, so a slow consumer holds the generator back.
+func generatePrimes(ctx context.Context, from, to uint64, emit func([]uint64) error) error {
+	lo := max(from, 2)
+	if lo > to {
+		return nil
+	}
+	limit := uint64(sieveBaseLimit)
+	if to < sieveBaseLimit*sieveBaseLimit {
+		limit = isqrt(to)
+	}
+	base := sievePrimes(limit)
+
+	seg := make([]bool, sieveSegment)
+	for {
+		if err := ctxError(ctx, "prime generation"); err != nil {
+			return err
+		}
+		hi := to
+		if to-lo >= sieveSegment {
+			hi = lo + sieveSegment - 1
+		}
+		marks := seg[:hi-lo+1]
+		clear(marks)
+		for _, p := range base {
+			start := p * p
+			if start > hi {
+				break
+			}
+			if start < lo {
+				//Offset from lo rather than rounding lo up, which can
+				//overflow at the top of the uint64 range.
+				off := (p - lo%p) % p
+				if off > hi-lo {
+					continue
+				}
+				start = lo + off
+			}
+			for j := start; j <= hi; j += p {
+				marks[j-lo] = true
+				if hi-j < p {
+					break
+				}
+			}
+		}
+
+		var primes []uint64
+		for i, composite := range marks {
+			n := lo + uint64(i)
+			//Survivors past sieveBaseLimit^2 may have two large factors.
+			if !composite && (n < sieveBaseLimit*sieveBaseLimit || isPrime64(n)) {
+				primes = append(primes, n)
+			}
+		}
+		if len(primes) > 0 {
+			if err := emit(primes); err != nil {
+				return err
+			}
+		}
+		if hi == to {
+			return nil
+		}
+		lo = hi + 1
+	}
+}
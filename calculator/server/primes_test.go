@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsPrime64(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want bool
+	}{
+		{0, false},
+		{1, false},
+		{2, true},
+		{37, true},
+		{41, true},
+		{65537, true},
+		{2147483647, true},
+		{1000000007, true},
+		{2305843009213693951, true},
+		{maxPrime64, true},
+		{math.MaxUint64, false},
+		{4, false},
+		{37 * 41, false},
+		//Carmichael numbers fool the Fermat test.
+		{561, false},
+		{41041, false},
+		//Strong pseudoprimes to the first 1, 2, ... 9 prime bases.
+		{2047, false},
+		{1373653, false},
+		{25326001, false},
+		{3215031751, false},
+		{2152302898747, false},
+		{3474749660383, false},
+		{341550071728321, false},
+		{3825123056546413051, false},
+		{4759123141, false},
+		//Squares of primes just past sieveBaseLimit.
+		{1048583 * 1048583, false},
+		{4294967291 * 4294967291, false},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.n), func(t *testing.T) {
+			if got := isPrime64(tc.n); got != tc.want {
+				t.Errorf("isPrime64(%d) = %v, want %v", tc.n, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsPrime64Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		n := r.Uint64() >> uint(r.Intn(64))
+		if got, want := isPrime64(n), new(big.Int).SetUint64(n).ProbablyPrime(20); got != want {
+			t.Fatalf("isPrime64(%d) = %v, want %v", n, got, want)
+		}
+	}
+}
+
+func TestNextPrime(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want uint64
+		code codes.Code
+	}{
+		{0, 2, codes.OK},
+		{1, 2, codes.OK},
+		{2, 3, codes.OK},
+		{3, 5, codes.OK},
+		{24, 29, codes.OK},
+		{2047, 2053, codes.OK},
+		{maxPrime64 - 1, maxPrime64, codes.OK},
+		{maxPrime64, 0, codes.OutOfRange},
+		{math.MaxUint64, 0, codes.OutOfRange},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.n), func(t *testing.T) {
+			got, err := nextPrime(tc.n)
+			if status.Code(err) != tc.code || got != tc.want {
+				t.Errorf("nextPrime(%d) = %d, %v, want %d, %v", tc.n, got, err, tc.want, tc.code)
+			}
+		})
+	}
+}
+
+// slowPrimes lists the primes in [from, to] one candidate at a time.
+func slowPrimes(from, to uint64) []uint64 {
+	var primes []uint64
+	for n := from; n <= to; n++ {
+		if new(big.Int).SetUint64(n).ProbablyPrime(20) {
+			primes = append(primes, n)
+		}
+		if n == to {
+			break
+		}
+	}
+	return primes
+}
+
+func TestGeneratePrimes(t *testing.T) {
+	const top = math.MaxUint64
+	const edge = sieveBaseLimit * sieveBaseLimit
+	tests := []struct {
+		name     string
+		from, to uint64
+	}{
+		{"empty", 10, 2},
+		{"none", 24, 28},
+		{"zero to small", 0, 100},
+		{"single prime", 7, 7},
+		{"segments", 0, 3*sieveSegment + 17},
+		{"segment boundary", sieveSegment - 10, sieveSegment + 10},
+		{"below base edge", edge - 50000, edge - 1},
+		{"across base edge", edge - 20000, edge + 20000},
+		{"past base edge", edge, edge + 50000},
+		{"top of range", top - 2*sieveSegment, top},
+		{"last prime", maxPrime64, top},
+		{"single top value", top, top},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []uint64
+			prev := uint64(0)
+			err := generatePrimes(context.Background(), tc.from, tc.to, func(primes []uint64) error {
+				if len(primes) == 0 {
+					t.Error("emitted an empty segment")
+				}
+				if len(primes) > 0 && len(got) > 0 && primes[0] <= prev {
+					t.Errorf("segment starting %d follows %d", primes[0], prev)
+				}
+				got = append(got, primes...)
+				if len(got) > 0 {
+					prev = got[len(got)-1]
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("generatePrimes: %v", err)
+			}
+			if want := slowPrimes(tc.from, tc.to); fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("generatePrimes(%d, %d) = %d primes, want %d", tc.from, tc.to, len(got), len(want))
+			}
+		})
+	}
+}
+
+func TestGeneratePrimesStops(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	errStop := errors.New("stop")
+	err := generatePrimes(cancelled, 0, 100, func([]uint64) error { return nil })
+	if status.Code(err) != codes.Canceled || !strings.Contains(err.Error(), "prime generation") {
+		t.Errorf("cancelled: error = %v, want %v naming prime generation", err, codes.Canceled)
+	}
+	calls := 0
+	err = generatePrimes(context.Background(), 0, 10*sieveSegment, func([]uint64) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Errorf("emit error: error = %v after %d calls, want %v after 1", err, calls, errStop)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"math"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+)
+
+// Limits on a RunningStats config.
+const (
+	maxWindowEntries  = 1 << 20
+	minTick           = 10 * time.Millisecond
+	defaultEWMAAlpha  = 0.5
+	maxAggregateCount = 16
+)
+
+type windowEntry struct {
+	seq   uint64
+	value float64
+	at    time.Time
+}
+
+// window keeps the aggregates of a count or time window up to date in
+// amortised constant time per number: monotonic queues for min and max,
+// and a compensated running sum that is recomputed once per window's
+// worth of evictions so subtraction error cannot build up.
+type window struct {
+	size int           //count window, 0 if none
+	span time.Duration //time window, 0 if none
+
+	entries    []windowEntry
+	maxq, minq []windowEntry
+	seq        uint64
+	sum, c     float64
+	evicted    int
+
+	alpha   float64
+	ewma    float64
+	hasEWMA bool
+}
+
+func newWindow(cfg *calculatorpb.RunningStatsConfig) (*window, error) {
+	if len(cfg.GetAggregates()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one aggregate is required")
+	}
+	if len(cfg.GetAggregates()) > maxAggregateCount {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d aggregates are allowed", maxAggregateCount)
+	}
+	for _, a := range cfg.GetAggregates() {
+		if _, ok := calculatorpb.Aggregate_name[int32(a)]; !ok || a == calculatorpb.Aggregate_AGGREGATE_UNSPECIFIED {
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported aggregate %v", a)
+		}
+	}
+	if cfg.GetWindowSize() > 0 && cfg.GetWindowMs() > 0 {
+		return nil, status.Error(codes.InvalidArgument, "set window_size or window_ms, not both")
+	}
+	if cfg.GetWindowSize() > maxWindowEntries {
+		return nil, status.Errorf(codes.InvalidArgument, "window_size must be at most %d", maxWindowEntries)
+	}
+	if t := cfg.GetTickMs(); t > 0 && time.Duration(t)*time.Millisecond < minTick {
+		return nil, status.Errorf(codes.InvalidArgument, "tick_ms must be at least %d", minTick.Milliseconds())
+	}
+	alpha := cfg.GetEwmaAlpha()
+	if alpha == 0 {
+		alpha = defaultEWMAAlpha
+	}
+	if !(alpha > 0 && alpha <= 1) {
+		return nil, status.Errorf(codes.InvalidArgument, "ewma_alpha %v is outside (0, 1]", cfg.GetEwmaAlpha())
+	}
+	return &window{
+		size:  int(cfg.GetWindowSize()),
+		span:  time.Duration(cfg.GetWindowMs()) * time.Millisecond,
+		alpha: alpha,
+	}, nil
+}
+
+// addSum adds x to the compensated sum.
+func (w *window) addSum(x float64) {
+	t := w.sum + x
+	if math.Abs(w.sum) >= math.Abs(x) {
+		w.c += (w.sum - t) + x
+	} else {
+		w.c += (x - t) + w.sum
+	}
+	w.sum = t
+}
+
+func (w *window) add(x float64, now time.Time) error {
+	if w.hasEWMA {
+		w.ewma += w.alpha * (x - w.ewma)
+	} else {
+		w.ewma, w.hasEWMA = x, true
+	}
+
+	w.seq++
+	e := windowEntry{seq: w.seq, value: x, at: now}
+	//Without a window only the queue heads matter, so entries is not kept.
+	if w.size > 0 || w.span > 0 {
+		w.entries = append(w.entries, e)
+	}
+	for len(w.maxq) > 0 && w.maxq[len(w.maxq)-1].value <= x {
+		w.maxq = w.maxq[:len(w.maxq)-1]
+	}
+	w.maxq = append(w.maxq, e)
+	for len(w.minq) > 0 && w.minq[len(w.minq)-1].value >= x {
+		w.minq = w.minq[:len(w.minq)-1]
+	}
+	w.minq = append(w.minq, e)
+	if w.size == 0 && w.span == 0 {
+		w.maxq, w.minq = w.maxq[:1], w.minq[:1]
+	}
+	w.addSum(x)
+
+	if w.size > 0 && len(w.entries) > w.size {
+		w.evict()
+	}
+	w.expire(now)
+	if len(w.entries) > maxWindowEntries {
+		return status.Errorf(codes.ResourceExhausted, "window holds more than %d numbers", maxWindowEntries)
+	}
+	return nil
+}
+
+// expire drops numbers that have aged out of a time window.
+func (w *window) expire(now time.Time) {
+	for w.span > 0 && len(w.entries) > 0 && now.Sub(w.entries[0].at) >= w.span {
+		w.evict()
+	}
+}
+
+func (w *window) evict() {
+	e := w.entries[0]
+	w.entries = w.entries[1:]
+	if w.maxq[0].seq == e.seq {
+		w.maxq = w.maxq[1:]
+	}
+	if w.minq[0].seq == e.seq {
+		w.minq = w.minq[1:]
+	}
+	w.addSum(-e.value)
+	if w.evicted++; w.evicted >= len(w.entries) {
+		w.evicted = 0
+		w.sum, w.c = 0, 0
+		for _, e := range w.entries {
+			w.addSum(e.value)
+		}
+	}
+}
+
+func (w *window) count() uint64 {
+	if w.size > 0 || w.span > 0 {
+		return uint64(len(w.entries))
+	}
+	return w.seq
+}
+
+// response reports the aggregates of the window as of now.
+func (w *window) response(aggregates []calculatorpb.Aggregate, now time.Time) *calculatorpb.RunningStatsResponse {
+	w.expire(now)
+	resp := &calculatorpb.RunningStatsResponse{Count: w.count()}
+	if resp.Count == 0 {
+		return resp
+	}
+	sum := w.sum + w.c
+	for _, a := range aggregates {
+		v := &calculatorpb.AggregateValue{Aggregate: a}
+		switch a {
+		case calculatorpb.Aggregate_AGGREGATE_MAX:
+			v.Value = w.maxq[0].value
+		case calculatorpb.Aggregate_AGGREGATE_MIN:
+			v.Value = w.minq[0].value
+		case calculatorpb.Aggregate_AGGREGATE_MEAN:
+			v.Value = sum / float64(resp.Count)
+		case calculatorpb.Aggregate_AGGREGATE_SUM:
+			v.Value = sum
+		case calculatorpb.Aggregate_AGGREGATE_EWMA:
+			v.Value = w.ewma
+		}
+		resp.Values = append(resp.Values, v)
+	}
+	return resp
+}
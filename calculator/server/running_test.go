@@ -0,0 +1,158 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+)
+
+var allAggregates = []calculatorpb.Aggregate{
+	calculatorpb.Aggregate_AGGREGATE_MAX,
+	calculatorpb.Aggregate_AGGREGATE_MIN,
+	calculatorpb.Aggregate_AGGREGATE_MEAN,
+	calculatorpb.Aggregate_AGGREGATE_SUM,
+	calculatorpb.Aggregate_AGGREGATE_EWMA,
+}
+
+func TestNewWindow(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *calculatorpb.RunningStatsConfig
+		code codes.Code
+	}{
+		{"no window", &calculatorpb.RunningStatsConfig{Aggregates: allAggregates}, codes.OK},
+		{"count window", &calculatorpb.RunningStatsConfig{Aggregates: allAggregates, WindowSize: maxWindowEntries}, codes.OK},
+		{"time window", &calculatorpb.RunningStatsConfig{Aggregates: allAggregates, WindowMs: 1000, TickMs: 10}, codes.OK},
+		{"alpha of one", &calculatorpb.RunningStatsConfig{Aggregates: allAggregates, EwmaAlpha: 1}, codes.OK},
+		{"no aggregates", &calculatorpb.RunningStatsConfig{}, codes.InvalidArgument},
+		{"too many aggregates", &calculatorpb.RunningStatsConfig{Aggregates: make([]calculatorpb.Aggregate, maxAggregateCount+1)}, codes.InvalidArgument},
+		{"unspecified aggregate", &calculatorpb.RunningStatsConfig{Aggregates: []calculatorpb.Aggregate{0}}, codes.InvalidArgument},
+		{"unknown aggregate", &calculatorpb.RunningStatsConfig{Aggregates: []calculatorpb.Aggregate{99}}, codes.InvalidArgument},
+		{"both windows", &calculatorpb.RunningStatsConfig{Aggregates: allAggregates, WindowSize: 2, WindowMs: 2}, codes.InvalidArgument},
+		{"window too large", &calculatorpb.RunningStatsConfig{Aggregates: allAggregates, WindowSize: maxWindowEntries + 1}, codes.InvalidArgument},
+		{"tick too short", &calculatorpb.RunningStatsConfig{Aggregates: allAggregates, TickMs: 9}, codes.InvalidArgument},
+		{"negative alpha", &calculatorpb.RunningStatsConfig{Aggregates: allAggregates, EwmaAlpha: -0.5}, codes.InvalidArgument},
+		{"alpha above one", &calculatorpb.RunningStatsConfig{Aggregates: allAggregates, EwmaAlpha: 1.5}, codes.InvalidArgument},
+		{"NaN alpha", &calculatorpb.RunningStatsConfig{Aggregates: allAggregates, EwmaAlpha: math.NaN()}, codes.InvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := newWindow(tc.cfg); status.Code(err) != tc.code {
+				t.Errorf("newWindow error = %v, want %v", err, tc.code)
+			}
+		})
+	}
+}
+
+// TestWindowCount compares count windows of several sizes with a brute
+// force recomputation after every number.
+func TestWindowCount(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	now := time.Now()
+	for _, size := range []uint32{0, 1, 2, 7, 100} {
+		w, err := newWindow(&calculatorpb.RunningStatsConfig{Aggregates: allAggregates, WindowSize: size})
+		if err != nil {
+			t.Fatalf("newWindow: %v", err)
+		}
+		var all []float64
+		ewma := 0.0
+		for i := 0; i < 3000; i++ {
+			x := (rng.Float64() - 0.5) * 1e6
+			if i%500 < 100 {
+				//A falling run fills the min queue.
+				x = -float64(i)
+			}
+			if i == 0 {
+				ewma = x
+			} else {
+				ewma += defaultEWMAAlpha * (x - ewma)
+			}
+			all = append(all, x)
+			if err := w.add(x, now); err != nil {
+				t.Fatalf("add: %v", err)
+			}
+
+			win := all
+			if size > 0 && len(win) > int(size) {
+				win = win[len(win)-int(size):]
+			}
+			mx, mn, sum := math.Inf(-1), math.Inf(1), 0.0
+			for _, v := range win {
+				mx, mn, sum = math.Max(mx, v), math.Min(mn, v), sum+v
+			}
+			want := []float64{mx, mn, sum / float64(len(win)), sum, ewma}
+			resp := w.response(allAggregates, now)
+			if resp.GetCount() != uint64(len(win)) {
+				t.Fatalf("size %d after %d: count = %d, want %d", size, i+1, resp.GetCount(), len(win))
+			}
+			for j, v := range resp.GetValues() {
+				if math.Abs(v.GetValue()-want[j]) > 1e-6*math.Max(1, math.Abs(want[j])) {
+					t.Fatalf("size %d after %d: %v = %v, want %v", size, i+1, v.GetAggregate(), v.GetValue(), want[j])
+				}
+			}
+		}
+	}
+}
+
+func TestWindowTime(t *testing.T) {
+	t0 := time.Now()
+	at := func(ms int) time.Time { return t0.Add(time.Duration(ms) * time.Millisecond) }
+	w, err := newWindow(&calculatorpb.RunningStatsConfig{Aggregates: allAggregates[:4], WindowMs: 100})
+	if err != nil {
+		t.Fatalf("newWindow: %v", err)
+	}
+	steps := []struct {
+		name  string
+		add   bool
+		value float64
+		at    int
+		count uint64
+		want  []float64 //max, min, mean, sum
+	}{
+		{"first", true, 5, 0, 1, []float64{5, 5, 5, 5}},
+		{"second", true, 1, 50, 2, []float64{5, 1, 3, 6}},
+		{"just before expiry", false, 0, 99, 2, []float64{5, 1, 3, 6}},
+		{"first expires", false, 0, 100, 1, []float64{1, 1, 1, 1}},
+		{"third", true, 3, 120, 2, []float64{3, 1, 2, 4}},
+		{"all expire", false, 0, 500, 0, nil},
+		{"after empty", true, -2, 510, 1, []float64{-2, -2, -2, -2}},
+	}
+	for _, s := range steps {
+		if s.add {
+			if err := w.add(s.value, at(s.at)); err != nil {
+				t.Fatalf("%s: add: %v", s.name, err)
+			}
+		}
+		resp := w.response(allAggregates[:4], at(s.at))
+		if resp.GetCount() != s.count || len(resp.GetValues()) != len(s.want) {
+			t.Fatalf("%s: response = %v, want count %d and %d values", s.name, resp, s.count, len(s.want))
+		}
+		for j, v := range resp.GetValues() {
+			if v.GetValue() != s.want[j] {
+				t.Errorf("%s: %v = %v, want %v", s.name, v.GetAggregate(), v.GetValue(), s.want[j])
+			}
+		}
+	}
+}
+
+func TestWindowFull(t *testing.T) {
+	w, err := newWindow(&calculatorpb.RunningStatsConfig{Aggregates: allAggregates, WindowMs: uint64(time.Hour.Milliseconds())})
+	if err != nil {
+		t.Fatalf("newWindow: %v", err)
+	}
+	now := time.Now()
+	for i := 0; i < maxWindowEntries; i++ {
+		if err := w.add(float64(i), now); err != nil {
+			t.Fatalf("add %d: %v", i, err)
+		}
+	}
+	if err := w.add(0, now); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("add past the limit: error = %v, want %v", err, codes.ResourceExhausted)
+	}
+}
@@ -2,30 +2,58 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"flag"
 	"io"
 	"log"
+	"log/slog"
 	"math"
-	"net"
-
-	"google.golang.org/grpc/reflection"
+	"math/big"
+	"os"
+	"time"
 
 	"google.golang.org/grpc/codes"
 
 	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
-	"google.golang.org/grpc"
+	"github.com/jwfrizzell/grpc-go-course/calculator/units"
+	"github.com/jwfrizzell/grpc-go-course/logging"
+	"github.com/jwfrizzell/grpc-go-course/ratelimit"
+	"github.com/jwfrizzell/grpc-go-course/serverutil"
 	"google.golang.org/grpc/status"
 )
 
-type server struct{}
+// defaultRateLimits keeps a single client from monopolising the expensive
+// streaming RPCs.
+var defaultRateLimits = &ratelimit.Config{
+	Default: ratelimit.Rule{Rate: 50, Burst: 100},
+	Methods: map[string]ratelimit.Rule{
+		"/calculator.CalculatorService/CalculatePrimeDecomposition": {Rate: 2, Burst: 5},
+		"/calculator.CalculatorService/FindMax":                     {Rate: 1, Burst: 3},
+		"/calculator.CalculatorService/RunningStats":                {Rate: 1, Burst: 3},
+		"/calculator.CalculatorService/GeneratePrimes":              {Rate: 2, Burst: 5},
+	},
+	MaxStreams: 4,
+}
+
+type server struct {
+	log *slog.Logger
+	//factorBudget caps the time spent on one prime decomposition, zero for
+	//no limit.
+	factorBudget time.Duration
+	units        *units.Registry
+}
+
+// logger returns the request scoped logger for ctx.
+func (s *server) logger(ctx context.Context) *slog.Logger {
+	return logging.FromContext(ctx, s.log)
+}
 
-func (s *server) SquareRoot(CTX context.Context, req *calculatorpb.SquareRootRequest) (*calculatorpb.SquareRootResponse, error) {
-	fmt.Println("Invoking SquareRoot() Function...")
+func (s *server) SquareRoot(ctx context.Context, req *calculatorpb.SquareRootRequest) (*calculatorpb.SquareRootResponse, error) {
+	s.logger(ctx).Debug("Invoking SquareRoot() Function...")
 
 	number := req.GetNumber()
 	if number < 0 {
 		return nil, status.Errorf(codes.InvalidArgument,
-			fmt.Sprintf("Received a negative number %v.", number))
+			"Received a negative number %v.", number)
 	}
 	resp := &calculatorpb.SquareRootResponse{
 		Number: math.Sqrt(number),
@@ -34,11 +62,14 @@ func (s *server) SquareRoot(CTX context.Context, req *calculatorpb.SquareRootReq
 }
 
 func (s *server) CalculateSum(ctx context.Context, req *calculatorpb.CalculatorRequest) (*calculatorpb.CalculatorResponse, error) {
-	fmt.Println("Invoking CalculateSum() Function...")
+	s.logger(ctx).Debug("Invoking CalculateSum() Function...")
 
 	fn := req.GetCalculator().GetFirstNumber()
 	ln := req.GetCalculator().GetLastNumber()
-	sum := fn + ln
+	sum, err := addInt64(fn, ln)
+	if err != nil {
+		return nil, err
+	}
 
 	resp := &calculatorpb.CalculatorResponse{
 		Result: sum,
@@ -47,71 +78,431 @@ func (s *server) CalculateSum(ctx context.Context, req *calculatorpb.CalculatorR
 	return resp, nil
 }
 
+func (s *server) Calculate(ctx context.Context, req *calculatorpb.CalculateRequest) (*calculatorpb.CalculateResponse, error) {
+	s.logger(ctx).Debug("Invoking Calculate() Function...", "operation", req.GetOperation())
+
+	result, err := calculate(req)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.CalculateResponse{Result: result}, nil
+}
+
+func (s *server) CalculateBig(ctx context.Context, req *calculatorpb.CalculateBigRequest) (*calculatorpb.CalculateBigResponse, error) {
+	s.logger(ctx).Debug("Invoking CalculateBig() Function...", "operation", req.GetOperation(), "operands", len(req.GetOperands()))
+
+	return calculateBig(req)
+}
+
+func (s *server) GCD(ctx context.Context, req *calculatorpb.IntegerListRequest) (*calculatorpb.IntegerResponse, error) {
+	s.logger(ctx).Debug("Invoking GCD() Function...", "numbers", len(req.GetNumbers()))
+
+	result, err := gcd(req)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.IntegerResponse{Result: result}, nil
+}
+
+func (s *server) LCM(ctx context.Context, req *calculatorpb.IntegerListRequest) (*calculatorpb.IntegerResponse, error) {
+	s.logger(ctx).Debug("Invoking LCM() Function...", "numbers", len(req.GetNumbers()))
+
+	result, err := lcm(req)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.IntegerResponse{Result: result}, nil
+}
+
+func (s *server) ModPow(ctx context.Context, req *calculatorpb.ModPowRequest) (*calculatorpb.IntegerResponse, error) {
+	s.logger(ctx).Debug("Invoking ModPow() Function...")
+
+	result, err := modPow(req)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.IntegerResponse{Result: result}, nil
+}
+
+func (s *server) ModInverse(ctx context.Context, req *calculatorpb.ModInverseRequest) (*calculatorpb.IntegerResponse, error) {
+	s.logger(ctx).Debug("Invoking ModInverse() Function...")
+
+	result, err := modInverse(req)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.IntegerResponse{Result: result}, nil
+}
+
+func (s *server) ExtendedGCD(ctx context.Context, req *calculatorpb.ExtendedGCDRequest) (*calculatorpb.ExtendedGCDResponse, error) {
+	s.logger(ctx).Debug("Invoking ExtendedGCD() Function...")
+
+	return extendedGCD(req)
+}
+
+func (s *server) DotProduct(ctx context.Context, req *calculatorpb.DotProductRequest) (*calculatorpb.DotProductResponse, error) {
+	s.logger(ctx).Debug("Invoking DotProduct() Function...", "length", len(req.GetA().GetValues()))
+
+	result, err := dotProduct(req)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.DotProductResponse{Result: result}, nil
+}
+
+func (s *server) MatrixMultiply(ctx context.Context, req *calculatorpb.MatrixMultiplyRequest) (*calculatorpb.MatrixResponse, error) {
+	s.logger(ctx).Debug("Invoking MatrixMultiply() Function...")
+
+	m, err := matrixMultiply(req)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.MatrixResponse{Result: m.proto()}, nil
+}
+
+func (s *server) Transpose(ctx context.Context, req *calculatorpb.MatrixRequest) (*calculatorpb.MatrixResponse, error) {
+	s.logger(ctx).Debug("Invoking Transpose() Function...")
+
+	m, err := transpose(req.GetMatrix())
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.MatrixResponse{Result: m.proto()}, nil
+}
+
+func (s *server) Determinant(ctx context.Context, req *calculatorpb.MatrixRequest) (*calculatorpb.DeterminantResponse, error) {
+	s.logger(ctx).Debug("Invoking Determinant() Function...")
+
+	det, err := determinant(req.GetMatrix())
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.DeterminantResponse{Determinant: det}, nil
+}
+
+func (s *server) Inverse(ctx context.Context, req *calculatorpb.MatrixRequest) (*calculatorpb.MatrixResponse, error) {
+	s.logger(ctx).Debug("Invoking Inverse() Function...")
+
+	m, err := inverse(req.GetMatrix())
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.MatrixResponse{Result: m.proto()}, nil
+}
+
+func (s *server) Solve(ctx context.Context, req *calculatorpb.SolveRequest) (*calculatorpb.SolveResponse, error) {
+	s.logger(ctx).Debug("Invoking Solve() Function...")
+
+	x, err := solve(req)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.SolveResponse{X: &calculatorpb.Vector{Values: x}}, nil
+}
+
+func (s *server) Evaluate(ctx context.Context, req *calculatorpb.EvaluateRequest) (*calculatorpb.EvaluateResponse, error) {
+	s.logger(ctx).Debug("Invoking Evaluate() Function...", "expression", req.GetExpression())
+
+	result, err := evaluate(req)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.EvaluateResponse{Result: result}, nil
+}
+
+func (s *server) Convert(ctx context.Context, req *calculatorpb.ConvertRequest) (*calculatorpb.ConvertResponse, error) {
+	s.logger(ctx).Debug("Invoking Convert() Function...", "from", req.GetFrom(), "to", req.GetTo())
+
+	return convert(s.units, req)
+}
+
+func (s *server) ListUnits(ctx context.Context, req *calculatorpb.ListUnitsRequest) (*calculatorpb.ListUnitsResponse, error) {
+	s.logger(ctx).Debug("Invoking ListUnits() Function...", "dimension", req.GetDimension())
+
+	return listUnits(s.units, req)
+}
+
 func (s *server) CalculatePrimeDecomposition(req *calculatorpb.PrimeRequest, stream calculatorpb.CalculatorService_CalculatePrimeDecompositionServer) error {
-	fmt.Println("Invoking CalculatePrimeDecomposition()...")
+	logger := s.logger(stream.Context())
+	logger.Debug("Invoking CalculatePrimeDecomposition()...")
 
-	n := req.GetPrime().GetNumber()
-	k := int64(2)
+	n, isBig, err := primeInput(req)
+	if err != nil {
+		return err
+	}
 
-	for n > 1 {
-		if n%k == 0 {
-			stream.Send(&calculatorpb.PrimeResponse{
-				Number: k,
-			})
-			n = n / k
-		} else {
-			k++
-			fmt.Println("Divisor: ", k)
+	ctx := stream.Context()
+	if s.factorBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.factorBudget)
+		defer cancel()
+	}
+
+	count := 0
+	err = factor(ctx, n, func(p *big.Int) error {
+		resp := &calculatorpb.PrimeResponse{}
+		if p.IsInt64() {
+			resp.Number = p.Int64()
 		}
+		if isBig {
+			resp.BigNumber = p.String()
+		}
+		if err := stream.Send(resp); err != nil {
+			return logging.StreamError(stream.Context(), s.log, err, "CalculatePrimeDecomposition Send Error")
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		//Report the client going away rather than our own budget running out.
+		if stream.Context().Err() != nil {
+			return logging.StreamError(stream.Context(), s.log, stream.Context().Err(), "CalculatePrimeDecomposition Cancelled")
+		}
+		if status.Code(err) == codes.DeadlineExceeded {
+			logger.Warn("Prime decomposition over budget", "number", n.String(), "budget", s.factorBudget)
+			return status.Errorf(codes.DeadlineExceeded, "prime decomposition took longer than the server's %v budget", s.factorBudget)
+		}
+		return err
 	}
+	logger.Debug("Prime decomposition complete", "factors", count)
 	return nil
 }
 
+func (s *server) IsPrime(ctx context.Context, req *calculatorpb.IsPrimeRequest) (*calculatorpb.IsPrimeResponse, error) {
+	s.logger(ctx).Debug("Invoking IsPrime() Function...", "number", req.GetNumber())
+
+	return &calculatorpb.IsPrimeResponse{IsPrime: isPrime64(req.GetNumber())}, nil
+}
+
+func (s *server) NextPrime(ctx context.Context, req *calculatorpb.NextPrimeRequest) (*calculatorpb.NextPrimeResponse, error) {
+	s.logger(ctx).Debug("Invoking NextPrime() Function...", "number", req.GetNumber())
+
+	p, err := nextPrime(req.GetNumber())
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.NextPrimeResponse{Prime: p}, nil
+}
+
+func (s *server) GeneratePrimes(req *calculatorpb.GeneratePrimesRequest, stream calculatorpb.CalculatorService_GeneratePrimesServer) error {
+	logger := s.logger(stream.Context())
+	logger.Debug("Invoking GeneratePrimes()...", "from", req.GetFrom(), "to", req.GetTo())
+
+	if req.GetTo() < req.GetFrom() {
+		return status.Errorf(codes.InvalidArgument, "to %d is less than from %d", req.GetTo(), req.GetFrom())
+	}
+	err := generatePrimes(stream.Context(), req.GetFrom(), req.GetTo(), func(primes []uint64) error {
+		if err := stream.Send(&calculatorpb.GeneratePrimesResponse{Primes: primes}); err != nil {
+			return logging.StreamError(stream.Context(), s.log, err, "GeneratePrimes Send Error")
+		}
+		return nil
+	})
+	if err != nil && stream.Context().Err() != nil {
+		return logging.StreamError(stream.Context(), s.log, stream.Context().Err(), "GeneratePrimes Cancelled")
+	}
+	return err
+}
+
+// primeInput returns the number a PrimeRequest asks to factor and whether
+// it came from big_number.
+func primeInput(req *calculatorpb.PrimeRequest) (*big.Int, bool, error) {
+	if req.GetBigNumber() == "" {
+		n := req.GetPrime().GetNumber()
+		if n <= 0 {
+			return nil, false, status.Errorf(codes.InvalidArgument, "number must be positive, got %d", n)
+		}
+		return big.NewInt(n), false, nil
+	}
+	if req.GetPrime().GetNumber() != 0 {
+		return nil, false, status.Error(codes.InvalidArgument, "set prime.number or big_number, not both")
+	}
+	if len(req.GetBigNumber()) > maxFactorBits/3 {
+		return nil, false, status.Errorf(codes.InvalidArgument, "big_number must be at most %d bits", maxFactorBits)
+	}
+	n, ok := new(big.Int).SetString(req.GetBigNumber(), 10)
+	if !ok {
+		return nil, false, status.Errorf(codes.InvalidArgument, "big_number %q is not a decimal integer", req.GetBigNumber())
+	}
+	if n.Sign() <= 0 {
+		return nil, false, status.Errorf(codes.InvalidArgument, "number must be positive, got %s", n)
+	}
+	if n.BitLen() > maxFactorBits {
+		return nil, false, status.Errorf(codes.InvalidArgument, "big_number must be at most %d bits", maxFactorBits)
+	}
+	return n, true, nil
+}
+
 func (s *server) CalculateAverage(stream calculatorpb.CalculatorService_CalculateAverageServer) error {
-	fmt.Println("Invoking CalculateAverage()...")
+	logger := s.logger(stream.Context())
+	logger.Debug("Invoking CalculateAverage()...")
 
 	var a int64
 	var i float64
 	for {
 		rec, err := stream.Recv()
 		if err == io.EOF {
+			if i == 0 {
+				return status.Error(codes.InvalidArgument, "no numbers received")
+			}
 			return stream.SendAndClose(&calculatorpb.AverageResponse{
 				Number: float64(a) / i,
 			})
 		}
 		if err != nil {
-			log.Fatalf("CalculateAverage() Client Stream Failure: %v\n", err)
+			return logging.StreamError(stream.Context(), s.log, err, "CalculateAverage Client Stream Failure")
+		}
+		if a, err = addInt64(a, rec.GetNumber()); err != nil {
+			return err
 		}
-		a += rec.GetNumber()
 		i++
-		fmt.Printf("Number: %d - Iteration: %f\n", a, i)
+		logger.Debug("Running total", "sum", a, "count", i)
 	}
 }
 
-func (s *server) FindMax(stream calculatorpb.CalculatorService_FindMaxServer) error {
-	fmt.Println("Invoking FindMax() Server...")
+func (s *server) ComputeStatistics(stream calculatorpb.CalculatorService_ComputeStatisticsServer) error {
+	logger := s.logger(stream.Context())
+	logger.Debug("Invoking ComputeStatistics()...")
 
-	max := float64(0)
+	var stats statistics
+	var percentiles []float64
+	for first := true; ; first = false {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			resp, err := stats.response(percentiles)
+			if err != nil {
+				return err
+			}
+			logger.Debug("Statistics complete", "count", resp.GetCount(), "approximate", resp.GetApproximate())
+			return stream.SendAndClose(resp)
+		}
+		if err != nil {
+			return logging.StreamError(stream.Context(), s.log, err, "ComputeStatistics Client Stream Failure")
+		}
+
+		if first {
+			percentiles = req.GetPercentiles()
+			if err := checkPercentiles(percentiles); err != nil {
+				return err
+			}
+		} else if len(req.GetPercentiles()) > 0 {
+			return status.Error(codes.InvalidArgument, "percentiles are only read from the first message")
+		}
+		for _, n := range req.GetNumbers() {
+			if math.IsInf(n, 0) || math.IsNaN(n) {
+				return status.Errorf(codes.InvalidArgument, "number %d is not finite", stats.count+1)
+			}
+			stats.add(n)
+		}
+	}
+}
+
+func (s *server) RunningStats(stream calculatorpb.CalculatorService_RunningStatsServer) error {
+	ctx := stream.Context()
+	logger := s.logger(ctx)
+	logger.Debug("Invoking RunningStats()...")
+
+	req, err := stream.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return logging.StreamError(ctx, s.log, err, "RunningStats Recv Error")
+	}
+	cfg := req.GetConfig()
+	if cfg == nil {
+		return status.Error(codes.InvalidArgument, "the first message must be a config")
+	}
+	w, err := newWindow(cfg)
+	if err != nil {
+		return err
+	}
+
+	//Recv runs in its own goroutine so ticks can be sent while the client
+	//is quiet. Only this goroutine calls Send.
+	reqs := make(chan *calculatorpb.RunningStatsRequest)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case reqs <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	var tick <-chan time.Time
+	if cfg.GetTickMs() > 0 {
+		t := time.NewTicker(time.Duration(cfg.GetTickMs()) * time.Millisecond)
+		defer t.Stop()
+		tick = t.C
+	}
+
+	send := func(now time.Time) error {
+		if err := stream.Send(w.response(cfg.GetAggregates(), now)); err != nil {
+			return logging.StreamError(ctx, s.log, err, "RunningStats Send Error")
+		}
+		return nil
+	}
 	for {
+		select {
+		case req := <-reqs:
+			item, ok := req.GetItem().(*calculatorpb.RunningStatsRequest_Number)
+			if !ok {
+				return status.Error(codes.InvalidArgument, "only the first message may be a config")
+			}
+			if math.IsInf(item.Number, 0) || math.IsNaN(item.Number) {
+				return status.Errorf(codes.InvalidArgument, "number %v is not finite", item.Number)
+			}
+			now := time.Now()
+			if err := w.add(item.Number, now); err != nil {
+				return err
+			}
+			if tick == nil {
+				if err := send(now); err != nil {
+					return err
+				}
+			}
+		case now := <-tick:
+			if err := send(now); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			if err == io.EOF {
+				return nil
+			}
+			return logging.StreamError(ctx, s.log, err, "RunningStats Recv Error")
+		}
+	}
+}
+
+func (s *server) FindMax(stream calculatorpb.CalculatorService_FindMaxServer) error {
+	logger := s.logger(stream.Context())
+	logger.Debug("Invoking FindMax() Server...")
+
+	var max float64
+	for first := true; ; first = false {
 		req, err := stream.Recv()
 		if err == io.EOF {
 			return nil
 		}
 		if err != nil {
-			log.Fatalf("FindMax Recv Error: %v", err)
-			return err
+			return logging.StreamError(stream.Context(), s.log, err, "FindMax Recv Error")
 		}
 
 		n := req.GetNumber()
-		fmt.Println("Numbers sent from client: ", n)
-		if n > max {
+		logger.Debug("Number sent from client", "number", n)
+		if first || n > max {
 			max = n
 			err = stream.Send(&calculatorpb.FindMaxResponse{
 				Number: max,
 			})
 			if err != nil {
-				log.Fatalf("FindMax Send Error: %v", err)
-				return err
+				return logging.StreamError(stream.Context(), s.log, err, "FindMax Send Error")
 			}
 		}
 
@@ -119,21 +510,41 @@ func (s *server) FindMax(stream calculatorpb.CalculatorService_FindMaxServer) er
 }
 
 func main() {
-	fmt.Println("Starting Client Server...")
+	flags := serverutil.RegisterFlags(flag.CommandLine, serverutil.Defaults{
+		ServiceName: "calculator-server",
+		Addr:        "0.0.0.0:50051",
+		RateLimits:  defaultRateLimits,
+	})
+	factorBudget := flag.Duration("factor-budget", time.Minute, "longest one prime decomposition may run, 0 for no limit")
+	unitConfig := flag.String("units", "", "unit config file adding to the built-in units, empty for none")
+	flag.Parse()
 
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
+	srv, err := serverutil.Setup(flags)
 	if err != nil {
-		log.Fatalf("Server Failure: %v\n", err)
+		log.Fatalf("Server Setup Failure: %v\n", err)
 	}
+	defer srv.Close(context.Background())
+	logger := srv.Logger
+	logger.Info("Starting Calculator Server...")
 
-	s := grpc.NewServer()
-	calculatorpb.RegisterCalculatorServiceServer(s, &server{})
+	unitRegistry := units.NewRegistry()
+	if *unitConfig != "" {
+		cfg, err := units.LoadConfig(*unitConfig)
+		if err == nil {
+			err = unitRegistry.AddConfig(cfg)
+		}
+		if err != nil {
+			logger.Error("Unit Setup Failure", "error", err)
+			os.Exit(1)
+		}
+	}
 
-	reflection.Register(s)
+	s := srv.NewGRPCServer(serverutil.Interceptors{})
+	calculatorpb.RegisterCalculatorServiceServer(s, &server{log: logger, factorBudget: *factorBudget, units: unitRegistry})
 
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("Server Listen Failure: %v\n", err)
+	if err := srv.Serve(); err != nil {
+		logger.Error("Server Listen Failure", "error", err)
+		os.Exit(1)
 	}
-	fmt.Println("Server is running...")
 
 }
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+	"github.com/jwfrizzell/grpc-go-course/calculator/units"
+)
+
+// startServer serves the calculator over an in-memory listener. The
+// returned channel receives the error every stream handler returns.
+func startServer(t *testing.T) (calculatorpb.CalculatorServiceClient, <-chan error) {
+	t.Helper()
+	handled := make(chan error, 16)
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(grpc.ChainStreamInterceptor(
+		func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			err := handler(srv, ss)
+			handled <- err
+			return err
+		},
+	))
+	calculatorpb.RegisterCalculatorServiceServer(s, &server{
+		log:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		units: units.NewRegistry(),
+	})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	cc, err := grpc.Dial("passthrough:///bufconn",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return calculatorpb.NewCalculatorServiceClient(cc), handled
+}
+
+// handlerError waits for the next stream handler to return.
+func handlerError(t *testing.T, handled <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-handled:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream handler did not return")
+		return nil
+	}
+}
+
+// streamCases open a stream, send one message and leave it open, then
+// finish a second stream normally.
+var streamCases = []struct {
+	name     string
+	open     func(ctx context.Context, c calculatorpb.CalculatorServiceClient) error
+	complete func(ctx context.Context, c calculatorpb.CalculatorServiceClient) error
+}{
+	{
+		name: "CalculateAverage",
+		open: func(ctx context.Context, c calculatorpb.CalculatorServiceClient) error {
+			stream, err := c.CalculateAverage(ctx)
+			if err != nil {
+				return err
+			}
+			return stream.Send(&calculatorpb.AverageRequest{Number: 4})
+		},
+		complete: func(ctx context.Context, c calculatorpb.CalculatorServiceClient) error {
+			stream, err := c.CalculateAverage(ctx)
+			if err != nil {
+				return err
+			}
+			for _, n := range []int64{1, 2, 3, 4} {
+				if err := stream.Send(&calculatorpb.AverageRequest{Number: n}); err != nil {
+					return err
+				}
+			}
+			resp, err := stream.CloseAndRecv()
+			if err != nil {
+				return err
+			}
+			if resp.GetNumber() != 2.5 {
+				return status.Errorf(codes.Internal, "average = %v, want 2.5", resp.GetNumber())
+			}
+			return nil
+		},
+	},
+	{
+		name: "FindMax",
+		open: func(ctx context.Context, c calculatorpb.CalculatorServiceClient) error {
+			stream, err := c.FindMax(ctx)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(&calculatorpb.FindMaxRequest{Number: 7}); err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		},
+		complete: func(ctx context.Context, c calculatorpb.CalculatorServiceClient) error {
+			stream, err := c.FindMax(ctx)
+			if err != nil {
+				return err
+			}
+			for _, n := range []float64{-3, -5, 2} {
+				if err := stream.Send(&calculatorpb.FindMaxRequest{Number: n}); err != nil {
+					return err
+				}
+			}
+			stream.CloseSend()
+			var got []float64
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return err
+				}
+				got = append(got, resp.GetNumber())
+			}
+			if len(got) != 2 || got[0] != -3 || got[1] != 2 {
+				return status.Errorf(codes.Internal, "maxima = %v, want [-3 2]", got)
+			}
+			return nil
+		},
+	},
+}
+
+func TestStreamDisconnect(t *testing.T) {
+	for _, tc := range streamCases {
+		t.Run(tc.name+"/cancel", func(t *testing.T) {
+			c, handled := startServer(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			if err := tc.open(ctx, c); err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			cancel()
+			if code := status.Code(handlerError(t, handled)); code != codes.Canceled {
+				t.Errorf("handler returned %v, want Canceled", code)
+			}
+
+			if err := tc.complete(context.Background(), c); err != nil {
+				t.Errorf("following call: %v", err)
+			}
+			if err := handlerError(t, handled); err != nil {
+				t.Errorf("following handler returned %v", err)
+			}
+		})
+
+		t.Run(tc.name+"/deadline", func(t *testing.T) {
+			c, handled := startServer(t)
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			if err := tc.open(ctx, c); err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			//The client resets the stream when its deadline passes, which
+			//usually beats the server's copy of the deadline.
+			if code := status.Code(handlerError(t, handled)); code != codes.Canceled && code != codes.DeadlineExceeded {
+				t.Errorf("handler returned %v, want Canceled or DeadlineExceeded", code)
+			}
+
+			if err := tc.complete(context.Background(), c); err != nil {
+				t.Errorf("following call: %v", err)
+			}
+			if _, err := c.CalculateSum(context.Background(), &calculatorpb.CalculatorRequest{
+				Calculator: &calculatorpb.Calculator{FirstNumber: 2, LastNumber: 3},
+			}); err != nil {
+				t.Errorf("following unary call: %v", err)
+			}
+		})
+	}
+}
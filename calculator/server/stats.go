@@ -0,0 +1,217 @@
+package main
+
+import (
+	"math"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+)
+
+const (
+	//exactLimit is how many values ComputeStatistics keeps for exact
+	//percentiles before switching to a t-digest.
+	exactLimit = 10000
+	//digestCompression trades t-digest size for accuracy.
+	digestCompression = 200
+	//maxPercentiles caps the percentiles one request may ask for.
+	maxPercentiles = 100
+)
+
+// statistics accumulates a stream of doubles. Moments use Welford's
+// algorithm and the sum is compensated, so neither loses precision as the
+// stream grows. Percentiles are exact for the first exactLimit values and
+// come from a t-digest after that.
+type statistics struct {
+	count    int64
+	sum, c   float64 //Neumaier sum and its running compensation
+	mean, m2 float64
+	min, max float64
+
+	values []float64 //nil once digest takes over
+	digest *tdigest
+}
+
+func (s *statistics) add(x float64) {
+	s.count++
+	if s.count == 1 {
+		s.min, s.max = x, x
+	}
+	s.min, s.max = math.Min(s.min, x), math.Max(s.max, x)
+
+	t := s.sum + x
+	if math.Abs(s.sum) >= math.Abs(x) {
+		s.c += (s.sum - t) + x
+	} else {
+		s.c += (x - t) + s.sum
+	}
+	s.sum = t
+
+	d := x - s.mean
+	s.mean += d / float64(s.count)
+	s.m2 += d * (x - s.mean)
+
+	if s.digest != nil {
+		s.digest.add(x)
+		return
+	}
+	s.values = append(s.values, x)
+	if len(s.values) > exactLimit {
+		s.digest = newTDigest(digestCompression)
+		for _, v := range s.values {
+			s.digest.add(v)
+		}
+		s.values = nil
+	}
+}
+
+// quantile returns the q quantile, 0 <= q <= 1.
+func (s *statistics) quantile(q float64) float64 {
+	if s.digest != nil {
+		return s.digest.quantile(q)
+	}
+	if !sort.Float64sAreSorted(s.values) {
+		sort.Float64s(s.values)
+	}
+	pos := q * float64(len(s.values)-1)
+	i := int(pos)
+	if i >= len(s.values)-1 {
+		return s.values[len(s.values)-1]
+	}
+	return s.values[i] + (pos-float64(i))*(s.values[i+1]-s.values[i])
+}
+
+// response summarises the stream so far.
+func (s *statistics) response(percentiles []float64) (*calculatorpb.StatisticsResponse, error) {
+	if s.count == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no numbers received")
+	}
+	n := float64(s.count)
+	resp := &calculatorpb.StatisticsResponse{
+		Count:       s.count,
+		Sum:         s.sum + s.c,
+		Mean:        s.mean,
+		Variance:    s.m2 / n,
+		Min:         s.min,
+		Max:         s.max,
+		Median:      s.quantile(0.5),
+		Approximate: s.digest != nil,
+	}
+	if s.count > 1 {
+		resp.SampleVariance = s.m2 / (n - 1)
+	}
+	resp.Stddev = math.Sqrt(resp.Variance)
+	resp.SampleStddev = math.Sqrt(resp.SampleVariance)
+	for _, p := range percentiles {
+		resp.Percentiles = append(resp.Percentiles, &calculatorpb.Percentile{
+			Percentile: p,
+			Value:      s.quantile(p / 100),
+		})
+	}
+	for _, v := range []float64{resp.Sum, resp.Mean, resp.Variance, resp.SampleVariance} {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return nil, status.Error(codes.OutOfRange, "statistics overflowed a double")
+		}
+	}
+	return resp, nil
+}
+
+// checkPercentiles validates the percentiles a request asks for.
+func checkPercentiles(ps []float64) error {
+	if len(ps) > maxPercentiles {
+		return status.Errorf(codes.InvalidArgument, "at most %d percentiles are allowed", maxPercentiles)
+	}
+	for _, p := range ps {
+		if !(p >= 0 && p <= 100) {
+			return status.Errorf(codes.InvalidArgument, "percentile %v is outside [0, 100]", p)
+		}
+	}
+	return nil
+}
+
+type centroid struct {
+	mean, weight float64
+}
+
+// tdigest is a merging t-digest (Dunning and Ertl). Values are buffered
+// and periodically merged into centroids whose size limit shrinks towards
+// the tails, which keeps extreme quantiles accurate.
+type tdigest struct {
+	compression float64
+	centroids   []centroid
+	buf         []centroid
+	count       float64
+	min, max    float64
+}
+
+func newTDigest(compression float64) *tdigest {
+	return &tdigest{compression: compression, min: math.Inf(1), max: math.Inf(-1)}
+}
+
+func (t *tdigest) add(x float64) {
+	t.buf = append(t.buf, centroid{x, 1})
+	t.count++
+	t.min, t.max = math.Min(t.min, x), math.Max(t.max, x)
+	if len(t.buf) >= 5*int(t.compression) {
+		t.merge()
+	}
+}
+
+func (t *tdigest) merge() {
+	if len(t.buf) == 0 {
+		return
+	}
+	all := append(t.centroids, t.buf...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+	t.buf = t.buf[:0]
+
+	out := make([]centroid, 0, len(t.centroids)+1)
+	cur := all[0]
+	soFar := 0.0
+	for _, c := range all[1:] {
+		q0 := soFar / t.count
+		q2 := (soFar + cur.weight + c.weight) / t.count
+		limit := 4 * t.count * math.Min(q0*(1-q0), q2*(1-q2)) / t.compression
+		if cur.weight+c.weight <= limit {
+			cur.weight += c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / cur.weight
+			continue
+		}
+		out = append(out, cur)
+		soFar += cur.weight
+		cur = c
+	}
+	t.centroids = append(out, cur)
+}
+
+// quantile interpolates between centroid centres, clamping to the exact
+// min and max at the ends.
+func (t *tdigest) quantile(q float64) float64 {
+	t.merge()
+	cs := t.centroids
+	target := q * t.count
+	if target <= cs[0].weight/2 {
+		return t.interpolate(t.min, cs[0].mean, 0, cs[0].weight/2, target)
+	}
+	soFar := 0.0
+	for i := 0; i < len(cs)-1; i++ {
+		left := soFar + cs[i].weight/2
+		right := soFar + cs[i].weight + cs[i+1].weight/2
+		if target <= right {
+			return t.interpolate(cs[i].mean, cs[i+1].mean, left, right, target)
+		}
+		soFar += cs[i].weight
+	}
+	last := cs[len(cs)-1]
+	return t.interpolate(last.mean, t.max, t.count-last.weight/2, t.count, target)
+}
+
+func (t *tdigest) interpolate(x0, x1, w0, w1, target float64) float64 {
+	if w1 <= w0 {
+		return x0
+	}
+	f := math.Max(0, math.Min(1, (target-w0)/(w1-w0)))
+	return x0 + f*(x1-x0)
+}
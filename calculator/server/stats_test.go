@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatisticsResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      []float64
+		percentiles []float64
+		want        map[string]float64
+		code        codes.Code
+	}{
+		{
+			name:        "small",
+			values:      []float64{4, 1, 3, 2},
+			percentiles: []float64{0, 25, 100},
+			want: map[string]float64{
+				"count": 4, "sum": 10, "mean": 2.5, "variance": 1.25, "sample variance": 5.0 / 3,
+				"min": 1, "max": 4, "median": 2.5, "p0": 1, "p25": 1.75, "p100": 4,
+			},
+		},
+		{
+			name:   "single value",
+			values: []float64{-7},
+			want:   map[string]float64{"count": 1, "mean": -7, "variance": 0, "sample variance": 0, "median": -7},
+		},
+		{
+			name:   "large offset keeps variance",
+			values: []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16},
+			want:   map[string]float64{"mean": 1e9 + 10, "variance": 22.5, "sample variance": 30},
+		},
+		{
+			name:   "compensated sum",
+			values: []float64{1, 1e100, 1, -1e100},
+			want:   map[string]float64{"sum": 2},
+		},
+		{
+			name:   "overflow",
+			values: []float64{math.MaxFloat64, math.MaxFloat64},
+			code:   codes.OutOfRange,
+		},
+		{
+			name: "empty",
+			code: codes.InvalidArgument,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var s statistics
+			for _, v := range tc.values {
+				s.add(v)
+			}
+			resp, err := s.response(tc.percentiles)
+			if status.Code(err) != tc.code {
+				t.Fatalf("response error = %v, want %v", err, tc.code)
+			}
+			if err != nil {
+				return
+			}
+			got := map[string]float64{
+				"count": float64(resp.GetCount()), "sum": resp.GetSum(), "mean": resp.GetMean(),
+				"variance": resp.GetVariance(), "sample variance": resp.GetSampleVariance(),
+				"min": resp.GetMin(), "max": resp.GetMax(), "median": resp.GetMedian(),
+			}
+			for _, p := range resp.GetPercentiles() {
+				got[fmt.Sprint("p", p.GetPercentile())] = p.GetValue()
+			}
+			for k, want := range tc.want {
+				if math.Abs(got[k]-want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", k, got[k], want)
+				}
+			}
+			if resp.GetApproximate() {
+				t.Error("small stream reported approximate percentiles")
+			}
+		})
+	}
+}
+
+// TestStatisticsSwitchOver checks the change from exact percentiles to a
+// t-digest once more than exactLimit values have arrived.
+func TestStatisticsSwitchOver(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name   string
+		n      int
+		digest bool
+	}{
+		{"one below limit", exactLimit - 1, false},
+		{"at limit", exactLimit, false},
+		{"one past limit", exactLimit + 1, true},
+		{"far past limit", 50 * exactLimit, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var s statistics
+			all := make([]float64, 0, tc.n)
+			for i := 0; i < tc.n; i++ {
+				v := rng.NormFloat64()
+				s.add(v)
+				all = append(all, v)
+			}
+			if (s.digest != nil) != tc.digest || (s.values == nil) != tc.digest {
+				t.Fatalf("digest=%v values=%d, want digest=%v", s.digest != nil, len(s.values), tc.digest)
+			}
+			resp, err := s.response([]float64{0, 0.1, 1, 50, 99, 99.9, 100})
+			if err != nil {
+				t.Fatalf("response: %v", err)
+			}
+			if resp.GetApproximate() != tc.digest {
+				t.Errorf("approximate = %v, want %v", resp.GetApproximate(), tc.digest)
+			}
+			if resp.GetCount() != int64(tc.n) {
+				t.Errorf("count = %d, want %d", resp.GetCount(), tc.n)
+			}
+
+			sort.Float64s(all)
+			for _, p := range resp.GetPercentiles() {
+				q := p.GetPercentile() / 100
+				switch {
+				case q == 0 || q == 1:
+					//Both modes report the exact extremes.
+					if want := all[int(q)*(len(all)-1)]; p.GetValue() != want {
+						t.Errorf("p%v = %v, want %v", p.GetPercentile(), p.GetValue(), want)
+					}
+				case !tc.digest:
+					pos := q * float64(len(all)-1)
+					i := int(pos)
+					if want := all[i] + (pos-float64(i))*(all[i+1]-all[i]); p.GetValue() != want {
+						t.Errorf("p%v = %v, want %v", p.GetPercentile(), p.GetValue(), want)
+					}
+				default:
+					//The digest's error in rank shrinks towards the tails.
+					rank := float64(sort.SearchFloat64s(all, p.GetValue())) / float64(len(all))
+					if tol := 0.01 * math.Max(4*q*(1-q), 0.05); math.Abs(rank-q) > tol {
+						t.Errorf("p%v = %v has rank %v, want within %v", p.GetPercentile(), p.GetValue(), rank, tol)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCheckPercentiles(t *testing.T) {
+	tests := []struct {
+		name string
+		ps   []float64
+		code codes.Code
+	}{
+		{"none", nil, codes.OK},
+		{"bounds", []float64{0, 50, 100}, codes.OK},
+		{"negative", []float64{-0.1}, codes.InvalidArgument},
+		{"above 100", []float64{100.1}, codes.InvalidArgument},
+		{"NaN", []float64{math.NaN()}, codes.InvalidArgument},
+		{"too many", make([]float64, maxPercentiles+1), codes.InvalidArgument},
+		{"at most", make([]float64, maxPercentiles), codes.OK},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := checkPercentiles(tc.ps); status.Code(err) != tc.code {
+				t.Errorf("checkPercentiles error = %v, want %v", err, tc.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package units
+
+// Base units: metre, kilogram, second, kelvin, byte and metre per second.
+var builtin = []Unit{
+	//Length
+	{Name: "nanometre", Symbol: "nm", Dimension: "length", Factor: 1e-9, Aliases: []string{"nanometer"}},
+	{Name: "micrometre", Symbol: "µm", Dimension: "length", Factor: 1e-6, Aliases: []string{"micrometer", "um", "micron"}},
+	{Name: "millimetre", Symbol: "mm", Dimension: "length", Factor: 1e-3, Aliases: []string{"millimeter"}},
+	{Name: "centimetre", Symbol: "cm", Dimension: "length", Factor: 1e-2, Aliases: []string{"centimeter"}},
+	{Name: "metre", Symbol: "m", Dimension: "length", Factor: 1, Aliases: []string{"meter"}},
+	{Name: "kilometre", Symbol: "km", Dimension: "length", Factor: 1e3, Aliases: []string{"kilometer"}},
+	{Name: "inch", Symbol: "in", Dimension: "length", Factor: 0.0254, Aliases: []string{"inches"}},
+	{Name: "foot", Symbol: "ft", Dimension: "length", Factor: 0.3048, Aliases: []string{"feet"}},
+	{Name: "yard", Symbol: "yd", Dimension: "length", Factor: 0.9144},
+	{Name: "mile", Symbol: "mi", Dimension: "length", Factor: 1609.344},
+	{Name: "nautical_mile", Symbol: "nmi", Dimension: "length", Factor: 1852},
+
+	//Mass
+	{Name: "microgram", Symbol: "µg", Dimension: "mass", Factor: 1e-9, Aliases: []string{"ug"}},
+	{Name: "milligram", Symbol: "mg", Dimension: "mass", Factor: 1e-6},
+	{Name: "gram", Symbol: "g", Dimension: "mass", Factor: 1e-3},
+	{Name: "kilogram", Symbol: "kg", Dimension: "mass", Factor: 1},
+	{Name: "tonne", Symbol: "t", Dimension: "mass", Factor: 1e3, Aliases: []string{"metric_ton"}},
+	{Name: "ounce", Symbol: "oz", Dimension: "mass", Factor: 0.028349523125},
+	{Name: "pound", Symbol: "lb", Dimension: "mass", Factor: 0.45359237, Aliases: []string{"lbs"}},
+	{Name: "stone", Symbol: "st", Dimension: "mass", Factor: 6.35029318},
+
+	//Time
+	{Name: "nanosecond", Symbol: "ns", Dimension: "time", Factor: 1e-9},
+	{Name: "microsecond", Symbol: "µs", Dimension: "time", Factor: 1e-6, Aliases: []string{"us"}},
+	{Name: "millisecond", Symbol: "ms", Dimension: "time", Factor: 1e-3},
+	{Name: "second", Symbol: "s", Dimension: "time", Factor: 1, Aliases: []string{"sec"}},
+	{Name: "minute", Symbol: "min", Dimension: "time", Factor: 60},
+	{Name: "hour", Symbol: "h", Dimension: "time", Factor: 3600, Aliases: []string{"hr"}},
+	{Name: "day", Symbol: "d", Dimension: "time", Factor: 86400},
+	{Name: "week", Symbol: "wk", Dimension: "time", Factor: 604800},
+	//A Julian year of 365.25 days.
+	{Name: "year", Symbol: "yr", Dimension: "time", Factor: 31557600},
+
+	//Temperature
+	{Name: "kelvin", Symbol: "K", Dimension: "temperature", Factor: 1},
+	{Name: "celsius", Symbol: "°C", Dimension: "temperature", Factor: 1, Offset: 273.15, Aliases: []string{"C", "degC"}},
+	{Name: "fahrenheit", Symbol: "°F", Dimension: "temperature", Factor: 5.0 / 9, Offset: 459.67 * 5 / 9, Aliases: []string{"F", "degF"}},
+	{Name: "rankine", Symbol: "°R", Dimension: "temperature", Factor: 5.0 / 9, Aliases: []string{"R", "degR"}},
+
+	//Data size
+	{Name: "bit", Symbol: "b", Dimension: "data", Factor: 0.125},
+	{Name: "byte", Symbol: "B", Dimension: "data", Factor: 1},
+	{Name: "kilobit", Symbol: "kb", Dimension: "data", Factor: 125},
+	{Name: "kilobyte", Symbol: "kB", Dimension: "data", Factor: 1e3, Aliases: []string{"KB"}},
+	{Name: "kibibyte", Symbol: "KiB", Dimension: "data", Factor: 1 << 10},
+	{Name: "megabit", Symbol: "Mb", Dimension: "data", Factor: 125e3},
+	{Name: "megabyte", Symbol: "MB", Dimension: "data", Factor: 1e6},
+	{Name: "mebibyte", Symbol: "MiB", Dimension: "data", Factor: 1 << 20},
+	{Name: "gigabit", Symbol: "Gb", Dimension: "data", Factor: 125e6},
+	{Name: "gigabyte", Symbol: "GB", Dimension: "data", Factor: 1e9},
+	{Name: "gibibyte", Symbol: "GiB", Dimension: "data", Factor: 1 << 30},
+	{Name: "terabyte", Symbol: "TB", Dimension: "data", Factor: 1e12},
+	{Name: "tebibyte", Symbol: "TiB", Dimension: "data", Factor: 1 << 40},
+	{Name: "petabyte", Symbol: "PB", Dimension: "data", Factor: 1e15},
+	{Name: "pebibyte", Symbol: "PiB", Dimension: "data", Factor: 1 << 50},
+
+	//Speed
+	{Name: "kilometre_per_hour", Symbol: "km/h", Dimension: "speed", Factor: 1 / 3.6, Aliases: []string{"kilometer_per_hour", "kph"}},
+	{Name: "foot_per_second", Symbol: "ft/s", Dimension: "speed", Factor: 0.3048, Aliases: []string{"fps"}},
+	{Name: "mile_per_hour", Symbol: "mph", Dimension: "speed", Factor: 0.44704, Aliases: []string{"mi/h"}},
+	{Name: "knot", Symbol: "kn", Dimension: "speed", Factor: 1852.0 / 3600, Aliases: []string{"kt"}},
+	{Name: "metre_per_second", Symbol: "m/s", Dimension: "speed", Factor: 1, Aliases: []string{"meter_per_second"}},
+}
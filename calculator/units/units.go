@@ -0,0 +1,206 @@
+// Package units converts values between units of the same dimension using
+// a registry of linear conversions, which a JSON config can extend.
+package units
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Unit converts to its dimension's base unit as base = value*Factor + Offset.
+// Only temperatures need an Offset.
+type Unit struct {
+	Name      string   `json:"name"`
+	Symbol    string   `json:"symbol,omitempty"`
+	Dimension string   `json:"dimension"`
+	Factor    float64  `json:"factor"`
+	Offset    float64  `json:"offset,omitempty"`
+	Aliases   []string `json:"aliases,omitempty"`
+}
+
+// Config lists units to add to the built-in ones.
+type Config struct {
+	Units []Unit `json:"units"`
+}
+
+// LoadConfig reads a JSON encoded Config from path.
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("units: reading config: %v", err)
+	}
+	cfg := &Config{}
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("units: parsing config %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// Registry holds the known units. Add is not safe to call concurrently
+// with the other methods; the server fills the registry before serving.
+type Registry struct {
+	units []*Unit
+	//keys maps exact names, aliases and symbols; folded maps lower-cased
+	//names and spelled-out aliases so "Metre" and "METERS" resolve too.
+	//Symbols are never folded: "Kb" must not find kilobyte's "KB".
+	keys, folded map[string]*Unit
+}
+
+// NewRegistry returns a registry holding the built-in units.
+func NewRegistry() *Registry {
+	r := &Registry{keys: map[string]*Unit{}, folded: map[string]*Unit{}}
+	for _, u := range builtin {
+		if err := r.Add(u); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Add registers u. Its name, aliases and symbol must all be unused.
+func (r *Registry) Add(u Unit) error {
+	if u.Name == "" || u.Dimension == "" {
+		return fmt.Errorf("units: unit %q needs a name and a dimension", u.Name)
+	}
+	if !(u.Factor > 0) || math.IsInf(u.Factor, 0) || math.IsInf(u.Offset, 0) || math.IsNaN(u.Offset) {
+		return fmt.Errorf("units: unit %q needs a positive, finite factor and a finite offset", u.Name)
+	}
+	keys := append([]string{u.Name}, u.Aliases...)
+	if u.Symbol != "" {
+		keys = append(keys, u.Symbol)
+	}
+	for _, k := range keys {
+		if _, ok := r.keys[k]; ok {
+			return fmt.Errorf("units: %q is already defined", k)
+		}
+	}
+	p := &u
+	r.units = append(r.units, p)
+	for _, k := range keys {
+		r.keys[k] = p
+	}
+	for _, k := range append([]string{u.Name}, u.Aliases...) {
+		if k != u.Name && !spelledOut(k) {
+			continue
+		}
+		if _, ok := r.folded[strings.ToLower(k)]; !ok {
+			r.folded[strings.ToLower(k)] = p
+		}
+	}
+	return nil
+}
+
+// spelledOut reports whether an alias is a word, like "meter" or "feet",
+// rather than a symbol like "KB" or "kt" whose case carries meaning.
+func spelledOut(alias string) bool {
+	return len(alias) > 3 && alias == strings.ToLower(alias)
+}
+
+// AddConfig registers every unit of cfg.
+func (r *Registry) AddConfig(cfg *Config) error {
+	for _, u := range cfg.Units {
+		if err := r.Add(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lookup finds a unit by name, alias or symbol. Names and spelled-out
+// aliases also match case-insensitively and with a trailing plural "s";
+// symbols match only exactly, so "Mb" and "MB" stay distinct.
+func (r *Registry) Lookup(name string) (Unit, bool) {
+	if u, ok := r.keys[name]; ok {
+		return *u, true
+	}
+	lower := strings.ToLower(strings.TrimSpace(name))
+	if u, ok := r.folded[lower]; ok {
+		return *u, true
+	}
+	if u, ok := r.folded[strings.TrimSuffix(lower, "s")]; ok && len(lower) > 3 {
+		return *u, true
+	}
+	return Unit{}, false
+}
+
+// Convert converts v from one unit to another of the same dimension.
+func (r *Registry) Convert(v float64, from, to string) (float64, Unit, Unit, error) {
+	f, ok := r.Lookup(from)
+	if !ok {
+		return 0, f, Unit{}, fmt.Errorf("unknown unit %q", from)
+	}
+	t, ok := r.Lookup(to)
+	if !ok {
+		return 0, f, t, fmt.Errorf("unknown unit %q", to)
+	}
+	if f.Dimension != t.Dimension {
+		return 0, f, t, fmt.Errorf("cannot convert %s (%s) to %s (%s)", f.Name, f.Dimension, t.Name, t.Dimension)
+	}
+	if f.Name == t.Name {
+		return v, f, t, nil
+	}
+	out := (v*f.Factor + f.Offset - t.Offset) / t.Factor
+	if f.Offset != 0 || t.Offset != 0 {
+		scale := math.Max(math.Abs(v*f.Factor), math.Max(math.Abs(f.Offset), math.Abs(t.Offset)))
+		out = round(out, scale/t.Factor)
+	}
+	return out, f, t, nil
+}
+
+// round drops the digits of x that fall below the precision left after
+// adding and subtracting terms of magnitude scale, so -40 °C converts to
+// -40 °F rather than -40.000000000000064.
+func round(x, scale float64) float64 {
+	if scale == 0 || math.IsInf(x, 0) {
+		return x
+	}
+	decimals := 12 - int(math.Floor(math.Log10(scale)))
+	if decimals < 0 {
+		return x
+	}
+	r, err := strconv.ParseFloat(strconv.FormatFloat(x, 'f', decimals, 64), 64)
+	if err != nil {
+		return x
+	}
+	return r
+}
+
+// Units returns the units of dimension, or of every dimension when it is
+// empty, ordered by dimension and then size.
+func (r *Registry) Units(dimension string) []Unit {
+	var out []Unit
+	for _, u := range r.units {
+		if dimension == "" || u.Dimension == dimension {
+			out = append(out, *u)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Dimension != out[j].Dimension {
+			return out[i].Dimension < out[j].Dimension
+		}
+		if out[i].Factor != out[j].Factor {
+			return out[i].Factor < out[j].Factor
+		}
+		return out[i].Offset < out[j].Offset
+	})
+	return out
+}
+
+// Dimensions returns the dimensions the registry knows, sorted.
+func (r *Registry) Dimensions() []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, u := range r.units {
+		if !seen[u.Dimension] {
+			seen[u.Dimension] = true
+			out = append(out, u.Dimension)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,145 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	r := NewRegistry()
+	tests := []struct {
+		key  string
+		want string //unit name, "" for no match
+	}{
+		{"metre", "metre"},
+		{"Metre", "metre"},
+		{"METERS", "metre"},
+		{" meter ", "metre"},
+		{"m", "metre"},
+		{"M", ""},
+		{"Feet", "foot"},
+		{"INCHES", "inch"},
+		{"Micron", "micrometre"},
+		{"um", "micrometre"},
+		{"UM", ""},
+		{"µm", "micrometre"},
+		//Symbols are case-sensitive: bits and bytes differ only in case.
+		{"b", "bit"},
+		{"B", "byte"},
+		{"kb", "kilobit"},
+		{"kB", "kilobyte"},
+		{"KB", "kilobyte"},
+		{"Kb", ""},
+		{"Mb", "megabit"},
+		{"MB", "megabyte"},
+		{"mb", ""},
+		{"mB", ""},
+		{"KiB", "kibibyte"},
+		{"kib", ""},
+		{"Kilobit", "kilobit"},
+		{"KILOBYTES", "kilobyte"},
+		{"bits", "bit"},
+		{"Bytes", "byte"},
+		{"s", "second"},
+		{"S", ""},
+		{"Seconds", "second"},
+		{"sec", "second"},
+		{"SEC", ""},
+		{"ms", "millisecond"},
+		{"Ms", ""},
+		{"K", "kelvin"},
+		{"k", ""},
+		{"C", "celsius"},
+		{"c", ""},
+		{"degC", "celsius"},
+		{"DEGC", ""},
+		{"Celsius", "celsius"},
+		{"kt", "knot"},
+		{"KT", ""},
+		{"Knots", "knot"},
+		{"mi/h", "mile_per_hour"},
+		{"Metric_Ton", "tonne"},
+		{"", ""},
+		{"sss", ""},
+		{"furlong", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.key, func(t *testing.T) {
+			u, ok := r.Lookup(tc.key)
+			if ok != (tc.want != "") || u.Name != tc.want {
+				t.Errorf("Lookup(%q) = %q, %v, want %q", tc.key, u.Name, ok, tc.want)
+			}
+		})
+	}
+}
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		name string
+		unit Unit
+		ok   bool
+	}{
+		{"new", Unit{Name: "furlong", Symbol: "fur", Dimension: "length", Factor: 201.168, Aliases: []string{"furlongs"}}, true},
+		{"case differs from a symbol", Unit{Name: "megabyte_alt", Symbol: "mb", Dimension: "data", Factor: 1e6}, true},
+		{"no name", Unit{Dimension: "length", Factor: 1}, false},
+		{"no dimension", Unit{Name: "thing", Factor: 1}, false},
+		{"zero factor", Unit{Name: "thing", Dimension: "length"}, false},
+		{"negative factor", Unit{Name: "thing", Dimension: "length", Factor: -1}, false},
+		{"infinite factor", Unit{Name: "thing", Dimension: "length", Factor: math.Inf(1)}, false},
+		{"NaN offset", Unit{Name: "thing", Dimension: "length", Factor: 1, Offset: math.NaN()}, false},
+		{"duplicate name", Unit{Name: "metre", Dimension: "length", Factor: 1}, false},
+		{"duplicate symbol", Unit{Name: "thing", Symbol: "kB", Dimension: "data", Factor: 1}, false},
+		{"duplicate alias", Unit{Name: "thing", Dimension: "data", Factor: 1, Aliases: []string{"KB"}}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRegistry()
+			if err := r.Add(tc.unit); (err == nil) != tc.ok {
+				t.Fatalf("Add error = %v, want ok=%v", err, tc.ok)
+			}
+			if !tc.ok {
+				return
+			}
+			for _, k := range append([]string{tc.unit.Name, tc.unit.Symbol}, tc.unit.Aliases...) {
+				if u, ok := r.Lookup(k); !ok || u.Name != tc.unit.Name {
+					t.Errorf("Lookup(%q) = %q, %v after Add", k, u.Name, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	r := NewRegistry()
+	tests := []struct {
+		v        float64
+		from, to string
+		want     float64
+		ok       bool
+	}{
+		{1, "km", "m", 1000, true},
+		{1, "mile", "km", 1.609344, true},
+		{-40, "C", "F", -40, true},
+		{100, "Celsius", "fahrenheit", 212, true},
+		{0, "K", "C", -273.15, true},
+		{491.67, "R", "F", 32, true},
+		{1, "kB", "b", 8000, true},
+		{1, "KB", "kb", 8, true},
+		{1, "MiB", "KiB", 1024, true},
+		{3, "m", "metre", 3, true},
+		{1, "Kb", "b", 0, false},
+		{1, "m", "kg", 0, false},
+		{1, "m", "furlong", 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.from+"->"+tc.to, func(t *testing.T) {
+			got, _, _, err := r.Convert(tc.v, tc.from, tc.to)
+			if (err == nil) != tc.ok {
+				t.Fatalf("Convert error = %v, want ok=%v", err, tc.ok)
+			}
+			if tc.ok && math.Abs(got-tc.want) > 1e-12*math.Max(1, math.Abs(tc.want)) {
+				t.Errorf("Convert(%v, %q, %q) = %v, want %v", tc.v, tc.from, tc.to, got, tc.want)
+			}
+		})
+	}
+}
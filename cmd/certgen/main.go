@@ -0,0 +1,91 @@
+// Command certgen writes a development CA, a server certificate and client
+// certificates for mutual TLS into ssl/, the layout the servers and clients
+// expect by default.
+//
+//	go run ./cmd/certgen -hosts localhost,127.0.0.1 -clients joe,admin
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jwfrizzell/grpc-go-course/tlsconfig"
+)
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func main() {
+	out := flag.String("out", "ssl", "output directory")
+	hosts := flag.String("hosts", "localhost,127.0.0.1,::1", "comma separated DNS names and IPs for the server certificate")
+	clients := flag.String("clients", "client", "comma separated common names of client certificates to issue")
+	days := flag.Int("days", 365, "certificate validity in days")
+	force := flag.Bool("force", false, "overwrite an existing CA")
+	flag.Parse()
+
+	serverHosts := splitList(*hosts)
+	if len(serverHosts) == 0 {
+		log.Fatal("At least one host is required.")
+	}
+	validity := time.Duration(*days) * 24 * time.Hour
+
+	if err := os.MkdirAll(*out, 0700); err != nil {
+		log.Fatalf("Unable to create %s: %v", *out, err)
+	}
+	caCert := filepath.Join(*out, "ca.crt")
+	caKey := filepath.Join(*out, "ca.key")
+	if _, err := os.Stat(caCert); err == nil && !*force {
+		log.Fatalf("%s already exists. Use -force to replace the CA.", caCert)
+	}
+
+	ca, err := tlsconfig.GenerateCA("grpc-go-course dev CA", validity)
+	if err != nil {
+		log.Fatalf("CA Generation Failure: %v", err)
+	}
+	write(ca, caCert, caKey)
+
+	srv, err := ca.Issue(tlsconfig.IssueOptions{
+		CommonName: serverHosts[0],
+		Hosts:      serverHosts,
+		Server:     true,
+		Validity:   validity,
+	})
+	if err != nil {
+		log.Fatalf("Server Certificate Failure: %v", err)
+	}
+	write(srv, filepath.Join(*out, "server.crt"), filepath.Join(*out, "server.pem"))
+
+	for _, cn := range splitList(*clients) {
+		if strings.ContainsAny(cn, `/\`) || cn == "ca" || cn == "server" {
+			log.Fatalf("Invalid client name %q.", cn)
+		}
+		cli, err := ca.Issue(tlsconfig.IssueOptions{
+			CommonName: cn,
+			Client:     true,
+			Validity:   validity,
+		})
+		if err != nil {
+			log.Fatalf("Client Certificate Failure: %v", err)
+		}
+		write(cli, filepath.Join(*out, cn+".crt"), filepath.Join(*out, cn+".pem"))
+	}
+}
+
+func write(ck *tlsconfig.CertKey, certFile, keyFile string) {
+	if err := ck.WriteFiles(certFile, keyFile); err != nil {
+		log.Fatalf("Unable to write %s: %v", certFile, err)
+	}
+	fmt.Printf("Wrote %s and %s\n", certFile, keyFile)
+}
@@ -0,0 +1,495 @@
+// Command openapigen writes an OpenAPI v3 document for the services of a
+// compiled-in .proto file. Every method is documented as a Connect route on
+// the gRPC port; an optional HTTP rules file, in the shape of grpc-gateway's
+// external configuration, adds REST routes.
+//
+//	go run ./cmd/openapigen -proto blog/blogpb/blog.proto \
+//		-rules blog/blogpb/blog.http.json -out blog/blogpb/blog.openapi.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	_ "github.com/jwfrizzell/grpc-go-course/blog/blogpb"
+	_ "github.com/jwfrizzell/grpc-go-course/calculator/calculatorpb"
+	_ "github.com/jwfrizzell/grpc-go-course/greet/greetpb"
+)
+
+// HTTPRule binds a method to a REST route, as google.api.HttpRule does.
+// Exactly one of the verb fields is set.
+type HTTPRule struct {
+	Selector string `json:"selector"`
+	Get      string `json:"get,omitempty"`
+	Post     string `json:"post,omitempty"`
+	Put      string `json:"put,omitempty"`
+	Patch    string `json:"patch,omitempty"`
+	Delete   string `json:"delete,omitempty"`
+	//Body names the request field sent as the body, or "*" for all fields
+	//not bound by the path.
+	Body string `json:"body,omitempty"`
+}
+
+func (r HTTPRule) route() (verb, path string) {
+	switch {
+	case r.Get != "":
+		return "get", r.Get
+	case r.Post != "":
+		return "post", r.Post
+	case r.Put != "":
+		return "put", r.Put
+	case r.Patch != "":
+		return "patch", r.Patch
+	case r.Delete != "":
+		return "delete", r.Delete
+	}
+	return "", ""
+}
+
+type rulesFile struct {
+	HTTP struct {
+		Rules []HTTPRule `json:"rules"`
+	} `json:"http"`
+}
+
+func loadRules(path string) ([]HTTPRule, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &rulesFile{}
+	if err := json.Unmarshal(b, f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", path, err)
+	}
+	return f.HTTP.Rules, nil
+}
+
+func main() {
+	protoFile := flag.String("proto", "", "registered .proto path, e.g. blog/blogpb/blog.proto")
+	rulesPath := flag.String("rules", "", "HTTP rules file adding REST routes")
+	out := flag.String("out", "", "output file, default stdout")
+	restURL := flag.String("rest-url", "http://localhost:8080", "server URL of the REST routes")
+	grpcURL := flag.String("grpc-url", "http://localhost:50051", "server URL of the Connect routes")
+	flag.Parse()
+
+	fd, err := protoregistry.GlobalFiles.FindFileByPath(*protoFile)
+	if err != nil {
+		log.Fatalf("Unknown proto file %q: %v", *protoFile, err)
+	}
+	rules, err := loadRules(*rulesPath)
+	if err != nil {
+		log.Fatalf("Unable to load HTTP rules: %v", err)
+	}
+
+	g := newGenerator(fd)
+	if err := g.build(rules, *restURL, *grpcURL); err != nil {
+		log.Fatalf("Unable to build OpenAPI document: %v", err)
+	}
+	b, err := json.MarshalIndent(g.doc, "", "  ")
+	if err != nil {
+		log.Fatalf("Unable to encode OpenAPI document: %v", err)
+	}
+	b = append(b, '\n')
+	if *out == "" {
+		os.Stdout.Write(b)
+		return
+	}
+	if err := os.WriteFile(*out, b, 0644); err != nil {
+		log.Fatalf("Unable to write %s: %v", *out, err)
+	}
+}
+
+type object = map[string]interface{}
+
+type generator struct {
+	file    protoreflect.FileDescriptor
+	doc     object
+	paths   object
+	schemas object
+	//shapes maps a path template with its variables blanked out to the
+	//first template seen, since OpenAPI forbids equivalent paths.
+	shapes map[string]string
+}
+
+func newGenerator(fd protoreflect.FileDescriptor) *generator {
+	g := &generator{
+		file:    fd,
+		paths:   object{},
+		schemas: object{},
+		shapes:  map[string]string{},
+	}
+	g.doc = object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":       string(fd.Package()) + " API",
+			"version":     "v1",
+			"description": fmt.Sprintf("Generated from %s by cmd/openapigen. Do not edit.", fd.Path()),
+		},
+		"paths":      g.paths,
+		"components": object{"schemas": g.schemas},
+	}
+	return g
+}
+
+func (g *generator) build(rules []HTTPRule, restURL, grpcURL string) error {
+	connectServers := []object{{"url": grpcURL, "description": "gRPC port of a server started with -web"}}
+	if len(rules) > 0 {
+		g.doc["servers"] = []object{{"url": restURL, "description": "REST gateway"}}
+		g.schemas["GatewayError"] = object{
+			"type": "object",
+			"properties": object{
+				"code":    object{"type": "integer", "description": "gRPC status code"},
+				"status":  object{"type": "string"},
+				"message": object{"type": "string"},
+			},
+		}
+	} else {
+		g.doc["servers"] = connectServers
+	}
+	g.schemas["ConnectError"] = connectErrorSchema()
+
+	byName := map[string]protoreflect.MethodDescriptor{}
+	services := g.file.Services()
+	for i := 0; i < services.Len(); i++ {
+		methods := services.Get(i).Methods()
+		for j := 0; j < methods.Len(); j++ {
+			m := methods.Get(j)
+			byName[string(m.FullName())] = m
+			item := object{"post": g.connectOperation(m)}
+			if len(rules) > 0 {
+				item["servers"] = connectServers
+			}
+			g.paths["/"+string(m.Parent().FullName())+"/"+string(m.Name())] = item
+		}
+	}
+
+	for _, r := range rules {
+		m, ok := byName[r.Selector]
+		if !ok {
+			return fmt.Errorf("rule selector %q is not a method of %s", r.Selector, g.file.Path())
+		}
+		verb, path := r.route()
+		if verb == "" {
+			return fmt.Errorf("rule for %s has no route", r.Selector)
+		}
+		op, path, err := g.restOperation(m, r, verb, path)
+		if err != nil {
+			return err
+		}
+		item, _ := g.paths[path].(object)
+		if item == nil {
+			item = object{}
+			g.paths[path] = item
+		}
+		if _, dup := item[verb]; dup {
+			return fmt.Errorf("two rules for %s %s", strings.ToUpper(verb), path)
+		}
+		item[verb] = op
+	}
+	return nil
+}
+
+func connectErrorSchema() object {
+	return object{
+		"type": "object",
+		"properties": object{
+			"code": object{"type": "string", "enum": []string{
+				"canceled", "unknown", "invalid_argument", "deadline_exceeded", "not_found",
+				"already_exists", "permission_denied", "resource_exhausted", "failed_precondition",
+				"aborted", "out_of_range", "unimplemented", "internal", "unavailable",
+				"data_loss", "unauthenticated",
+			}},
+			"message": object{"type": "string"},
+			"details": object{"type": "array", "items": object{
+				"type": "object",
+				"properties": object{
+					"type":  object{"type": "string"},
+					"value": object{"type": "string", "format": "byte"},
+				},
+			}},
+		},
+	}
+}
+
+func streamKind(m protoreflect.MethodDescriptor) string {
+	switch {
+	case m.IsStreamingClient() && m.IsStreamingServer():
+		return "bidirectional streaming"
+	case m.IsStreamingClient():
+		return "client streaming"
+	case m.IsStreamingServer():
+		return "server streaming"
+	}
+	return ""
+}
+
+func (g *generator) connectOperation(m protoreflect.MethodDescriptor) object {
+	in, out := g.ref(m.Input()), g.ref(m.Output())
+	op := object{
+		"operationId": string(m.Parent().Name()) + "_" + string(m.Name()),
+		"tags":        []string{string(m.Parent().Name())},
+		"summary":     fmt.Sprintf("%s over the Connect protocol", m.Name()),
+	}
+	errResp := object{
+		"description": "Error",
+		"content":     object{"application/json": object{"schema": g.schemaRef("ConnectError")}},
+	}
+	kind := streamKind(m)
+	if kind == "" {
+		op["requestBody"] = object{
+			"required": true,
+			"content":  object{"application/json": object{"schema": in}},
+		}
+		op["responses"] = object{
+			"200": object{
+				"description": "OK",
+				"content":     object{"application/json": object{"schema": out}},
+			},
+			"default": errResp,
+		}
+		return op
+	}
+	op["description"] = fmt.Sprintf("A %s call. Bodies are length-prefixed JSON messages; "+
+		"the response ends with an end-of-stream message carrying any error. "+
+		"Over HTTP/1.1 the whole request is sent before the response starts.", kind)
+	op["requestBody"] = object{
+		"required": true,
+		"content":  object{"application/connect+json": object{"schema": in}},
+	}
+	op["responses"] = object{
+		"200": object{
+			"description": "Stream of messages",
+			"content":     object{"application/connect+json": object{"schema": out}},
+		},
+		"default": errResp,
+	}
+	return op
+}
+
+var pathVar = regexp.MustCompile(`\{([A-Za-z0-9_.]+)\}`)
+
+// restOperation documents m as served by rule. It returns the path key to
+// use, which differs from path when an equivalent template already exists.
+func (g *generator) restOperation(m protoreflect.MethodDescriptor, r HTTPRule, verb, path string) (object, string, error) {
+	input := m.Input()
+	bound := map[string]bool{}
+	var params []object
+
+	//Reuse the variable names of an equivalent template.
+	shape := pathVar.ReplaceAllString(path, "{}")
+	key, seen := g.shapes[shape]
+	if !seen {
+		key = path
+		g.shapes[shape] = path
+	}
+	names := pathVar.FindAllStringSubmatch(key, -1)
+	for i, v := range pathVar.FindAllStringSubmatch(path, -1) {
+		fd, err := lookupField(input, v[1])
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: %v", r.Selector, err)
+		}
+		bound[strings.Split(v[1], ".")[0]] = true
+		p := object{
+			"name":     names[i][1],
+			"in":       "path",
+			"required": true,
+			"schema":   g.fieldSchema(fd),
+		}
+		if names[i][1] != v[1] {
+			p["description"] = "Sets " + v[1] + "."
+		}
+		params = append(params, p)
+	}
+
+	op := object{
+		"operationId": string(m.Parent().Name()) + "_" + string(m.Name()) + "_REST",
+		"tags":        []string{string(m.Parent().Name())},
+		"summary":     string(m.Name()),
+	}
+
+	switch r.Body {
+	case "":
+	case "*":
+		op["requestBody"] = object{
+			"required": true,
+			"content":  object{"application/json": object{"schema": g.ref(input)}},
+		}
+		for i := 0; i < input.Fields().Len(); i++ {
+			bound[string(input.Fields().Get(i).Name())] = true
+		}
+	default:
+		fd := input.Fields().ByName(protoreflect.Name(r.Body))
+		if fd == nil {
+			return nil, "", fmt.Errorf("%s: body field %q not found", r.Selector, r.Body)
+		}
+		op["requestBody"] = object{
+			"required": true,
+			"content":  object{"application/json": object{"schema": g.fieldSchema(fd)}},
+		}
+		bound[r.Body] = true
+	}
+
+	//Unbound scalar fields are read from the query string.
+	for i := 0; i < input.Fields().Len(); i++ {
+		fd := input.Fields().Get(i)
+		if bound[string(fd.Name())] || fd.Kind() == protoreflect.MessageKind || fd.IsMap() {
+			continue
+		}
+		params = append(params, object{
+			"name":   string(fd.Name()),
+			"in":     "query",
+			"schema": g.fieldSchema(fd),
+		})
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	ok := object{
+		"description": "OK",
+		"content":     object{"application/json": object{"schema": g.ref(m.Output())}},
+	}
+	if m.IsStreamingServer() {
+		ok = object{
+			"description": fmt.Sprintf("Newline delimited JSON, one %s per line. "+
+				`An error after the first line is sent as a final {"error": GatewayError} line.`, m.Output().Name()),
+			"content": object{"application/x-ndjson": object{"schema": g.ref(m.Output())}},
+		}
+	}
+	op["responses"] = object{
+		"200": ok,
+		"default": object{
+			"description": "Error",
+			"content":     object{"application/json": object{"schema": g.schemaRef("GatewayError")}},
+		},
+	}
+	return op, key, nil
+}
+
+// lookupField resolves a dotted field path such as "blog.id" in md.
+func lookupField(md protoreflect.MessageDescriptor, path string) (protoreflect.FieldDescriptor, error) {
+	var fd protoreflect.FieldDescriptor
+	for _, name := range strings.Split(path, ".") {
+		if md == nil {
+			return nil, fmt.Errorf("field path %q descends into a scalar", path)
+		}
+		if fd = md.Fields().ByName(protoreflect.Name(name)); fd == nil {
+			return nil, fmt.Errorf("field %q not found in %s", name, md.FullName())
+		}
+		md = fd.Message()
+	}
+	if fd.Kind() == protoreflect.MessageKind || fd.IsList() {
+		return nil, fmt.Errorf("path variable %q must be a scalar field", path)
+	}
+	return fd, nil
+}
+
+func (g *generator) schemaRef(name string) object {
+	return object{"$ref": "#/components/schemas/" + name}
+}
+
+// ref returns a reference to md's schema, adding the schema and those of
+// the messages it uses on first sight.
+func (g *generator) ref(md protoreflect.MessageDescriptor) object {
+	if s := wellKnown(md); s != nil {
+		return s
+	}
+	name := string(md.FullName())
+	if _, ok := g.schemas[name]; !ok {
+		props := object{}
+		schema := object{"type": "object", "properties": props}
+		g.schemas[name] = schema
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			s := g.fieldSchema(fd)
+			if o := fd.ContainingOneof(); o != nil && !o.IsSynthetic() {
+				s = object{"allOf": []object{s}, "description": fmt.Sprintf("Member of oneof %s; set at most one.", o.Name())}
+			}
+			props[fd.JSONName()] = s
+		}
+	}
+	return g.schemaRef(name)
+}
+
+func (g *generator) fieldSchema(fd protoreflect.FieldDescriptor) object {
+	if fd.IsMap() {
+		return object{"type": "object", "additionalProperties": g.valueSchema(fd.MapValue())}
+	}
+	s := g.valueSchema(fd)
+	if fd.IsList() {
+		return object{"type": "array", "items": s}
+	}
+	return s
+}
+
+// valueSchema follows the proto3 JSON mapping: 64-bit integers are strings
+// and enums are their value names.
+func (g *generator) valueSchema(fd protoreflect.FieldDescriptor) object {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return object{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return object{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return object{"type": "integer", "format": "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return object{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return object{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return object{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return object{"type": "number", "format": "double"}
+	case protoreflect.StringKind:
+		return object{"type": "string"}
+	case protoreflect.BytesKind:
+		return object{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return object{"type": "string", "enum": names}
+	}
+	return g.ref(fd.Message())
+}
+
+// wellKnown returns the JSON mapping of the google.protobuf types that do
+// not encode as plain objects.
+func wellKnown(md protoreflect.MessageDescriptor) object {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return object{"type": "string", "format": "date-time"}
+	case "google.protobuf.Duration":
+		return object{"type": "string", "example": "1.5s"}
+	case "google.protobuf.FieldMask":
+		return object{"type": "string"}
+	case "google.protobuf.Empty", "google.protobuf.Struct":
+		return object{"type": "object"}
+	case "google.protobuf.Value":
+		return object{}
+	case "google.protobuf.StringValue", "google.protobuf.BytesValue",
+		"google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return object{"type": "string", "nullable": true}
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value":
+		return object{"type": "integer", "nullable": true}
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue":
+		return object{"type": "number", "nullable": true}
+	case "google.protobuf.BoolValue":
+		return object{"type": "boolean", "nullable": true}
+	}
+	return nil
+}
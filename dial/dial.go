@@ -0,0 +1,42 @@
+// Package dial creates client connections with a gRPC service config that
+// retries idempotent calls, hedges cheap ones and bounds every call with a
+// timeout. Each client supplies its own defaults, which a service config
+// file can replace. Every connection is traced with the global tracer
+// provider, so calls link to the server side spans. RegisterFlags and Setup
+// hold the flags and connection setup shared by the course clients.
+package dial
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	"github.com/jwfrizzell/grpc-go-course/tracing"
+)
+
+// Dial connects to target using sc as the default service config. A nil
+// sc dials without one. opts are applied after the service config and the
+// tracing option.
+func Dial(target string, sc *ServiceConfig, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{tracing.DialOption()}, opts...)
+	if sc != nil {
+		js, err := sc.JSON()
+		if err != nil {
+			return nil, err
+		}
+		opts = append([]grpc.DialOption{grpc.WithDefaultServiceConfig(js)}, opts...)
+	}
+	cc, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("dial: %s: %v", target, err)
+	}
+	return cc, nil
+}
+
+// Load returns the service config in path, or fallback when path is empty.
+func Load(path string, fallback *ServiceConfig) (*ServiceConfig, error) {
+	if path == "" {
+		return fallback, nil
+	}
+	return LoadServiceConfig(path)
+}
@@ -0,0 +1,137 @@
+package dial
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/jwfrizzell/grpc-go-course/auth"
+	"github.com/jwfrizzell/grpc-go-course/logging"
+	"github.com/jwfrizzell/grpc-go-course/tlsconfig"
+	"github.com/jwfrizzell/grpc-go-course/tracing"
+)
+
+// Defaults holds the per-client defaults of the shared flags.
+type Defaults struct {
+	//ServiceName names the client in traces.
+	ServiceName string
+	//TLS makes -tls default to true.
+	TLS bool
+	//ServiceConfig applies when -service-config is empty.
+	ServiceConfig *ServiceConfig
+}
+
+// Flags are the values of the shared client flags once the flag set is
+// parsed.
+type Flags struct {
+	TraceExporter string
+	OTLPEndpoint  string
+	Token         string
+
+	TLS           bool
+	TLSCA         string
+	TLSCert       string
+	TLSKey        string
+	TLSServerName string
+
+	ServiceConfig string
+
+	defaults Defaults
+}
+
+// RegisterFlags defines the shared client flags on fs and returns the Flags
+// they fill in once fs is parsed.
+func RegisterFlags(fs *flag.FlagSet, d Defaults) *Flags {
+	f := &Flags{defaults: d}
+	fs.StringVar(&f.TraceExporter, "trace-exporter", tracing.ExporterNone, "trace exporter: none, stdout or otlp")
+	fs.StringVar(&f.OTLPEndpoint, "otlp-endpoint", tracing.DefaultOTLPEndpoint, "OTLP collector address")
+	fs.StringVar(&f.Token, "token", "", "bearer token sent with every call")
+	fs.BoolVar(&f.TLS, "tls", d.TLS, "connect over TLS")
+	fs.StringVar(&f.TLSCA, "tls-ca", "ssl/ca.crt", "CA bundle used to verify the server")
+	fs.StringVar(&f.TLSCert, "tls-cert", "", "client certificate for mutual TLS")
+	fs.StringVar(&f.TLSKey, "tls-key", "", "client private key for mutual TLS")
+	fs.StringVar(&f.TLSServerName, "tls-server-name", "", "override the server name checked against its certificate")
+	fs.StringVar(&f.ServiceConfig, "service-config", "", "service config file with retry and timeout policies, empty for the built-in one")
+	return f
+}
+
+// DialOptions returns the transport credentials, bearer token and logging
+// interceptors described by f.
+func (f *Flags) DialOptions() ([]grpc.DialOption, error) {
+	transport := grpc.WithTransportCredentials(insecure.NewCredentials())
+	tokenCreds := auth.InsecureBearerToken
+	if f.TLS {
+		tlsClient, err := tlsconfig.NewClient(tlsconfig.ClientConfig{
+			CAFile:     f.TLSCA,
+			CertFile:   f.TLSCert,
+			KeyFile:    f.TLSKey,
+			ServerName: f.TLSServerName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("dial: loading client TLS files: %v", err)
+		}
+		transport = grpc.WithTransportCredentials(tlsClient.Credentials())
+		tokenCreds = auth.BearerToken
+	}
+	opts := []grpc.DialOption{
+		transport,
+		grpc.WithChainUnaryInterceptor(logging.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(logging.StreamClientInterceptor()),
+	}
+	if f.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCreds(f.Token)))
+	}
+	return opts, nil
+}
+
+// Client is a connection set up from Flags together with the tracing it
+// started.
+type Client struct {
+	Conn *grpc.ClientConn
+
+	tracing func(context.Context) error
+}
+
+// Setup starts tracing, loads the service config and connects to target as
+// described by f. Close releases what Setup started.
+func Setup(target string, f *Flags) (*Client, error) {
+	shutdown, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName: f.defaults.ServiceName,
+		Exporter:    f.TraceExporter,
+		Endpoint:    f.OTLPEndpoint,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dial: %v", err)
+	}
+	c := &Client{tracing: shutdown}
+	opts, err := f.DialOptions()
+	if err != nil {
+		c.Close(context.Background())
+		return nil, err
+	}
+	sc, err := Load(f.ServiceConfig, f.defaults.ServiceConfig)
+	if err != nil {
+		c.Close(context.Background())
+		return nil, err
+	}
+	if c.Conn, err = Dial(target, sc, opts...); err != nil {
+		c.Close(context.Background())
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close closes the connection and flushes the traces.
+func (c *Client) Close(ctx context.Context) error {
+	var err error
+	if c.Conn != nil {
+		err = c.Conn.Close()
+	}
+	if terr := c.tracing(ctx); err == nil {
+		err = terr
+	}
+	return err
+}
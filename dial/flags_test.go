@@ -0,0 +1,56 @@
+package dial
+
+import (
+	"context"
+	"flag"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegisterFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults Defaults
+		args     []string
+		wantTLS  bool
+		wantErr  string
+	}{
+		{name: "plaintext", args: []string{"-token=secret"}},
+		{name: "TLS by default", defaults: Defaults{TLS: true}, args: []string{"-tls-ca=" + filepath.Join("testdata", "missing.crt")}, wantTLS: true, wantErr: "client TLS files"},
+		{name: "TLS turned off", defaults: Defaults{TLS: true}, args: []string{"-tls=false"}},
+		{name: "bad service config", args: []string{"-service-config=" + filepath.Join("testdata", "missing.json")}, wantErr: "service config"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			tc.defaults.ServiceName = "test-client"
+			tc.defaults.ServiceConfig = methodConfig(MethodConfig{RetryPolicy: DefaultRetryPolicy()})
+			f := RegisterFlags(fs, tc.defaults)
+			if err := fs.Parse(tc.args); err != nil {
+				t.Fatal(err)
+			}
+			if f.TLS != tc.wantTLS {
+				t.Errorf("TLS = %v, want %v", f.TLS, tc.wantTLS)
+			}
+
+			c, err := Setup("passthrough:///test", f)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("Setup error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Setup: %v", err)
+			}
+			if c.Conn == nil {
+				t.Fatal("Setup returned no connection")
+			}
+			if err := c.Close(context.Background()); err != nil {
+				t.Errorf("Close: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package dial
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServiceConfig is the subset of the gRPC service config the clients use.
+// It marshals to the JSON grpc expects; see
+// https://github.com/grpc/grpc/blob/master/doc/service_config.md.
+type ServiceConfig struct {
+	MethodConfig    []MethodConfig   `json:"methodConfig,omitempty"`
+	RetryThrottling *RetryThrottling `json:"retryThrottling,omitempty"`
+}
+
+// Name selects the methods a MethodConfig applies to. An empty Method
+// matches every method of Service.
+type Name struct {
+	Service string `json:"service"`
+	Method  string `json:"method,omitempty"`
+}
+
+// MethodConfig sets the call behaviour for a group of methods. At most one
+// of RetryPolicy and HedgingPolicy may be set.
+type MethodConfig struct {
+	Name          []Name         `json:"name"`
+	WaitForReady  bool           `json:"waitForReady,omitempty"`
+	Timeout       Duration       `json:"timeout,omitempty"`
+	RetryPolicy   *RetryPolicy   `json:"retryPolicy,omitempty"`
+	HedgingPolicy *HedgingPolicy `json:"hedgingPolicy,omitempty"`
+}
+
+// RetryPolicy retries a failed call with exponential backoff. Only use it
+// on idempotent methods.
+type RetryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       Duration `json:"initialBackoff"`
+	MaxBackoff           Duration `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+// HedgingPolicy sends extra copies of a call every HedgingDelay until one
+// succeeds. Only use it on methods that are cheap and side effect free.
+type HedgingPolicy struct {
+	MaxAttempts         int      `json:"maxAttempts"`
+	HedgingDelay        Duration `json:"hedgingDelay,omitempty"`
+	NonFatalStatusCodes []string `json:"nonFatalStatusCodes,omitempty"`
+}
+
+// RetryThrottling stops retries and hedges once too many calls fail.
+type RetryThrottling struct {
+	MaxTokens  int     `json:"maxTokens"`
+	TokenRatio float64 `json:"tokenRatio"`
+}
+
+// Duration is a time.Duration that reads "250ms" style strings and writes
+// the fractional seconds form ("0.25s") the service config requires.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatFloat(time.Duration(d).Seconds(), 'f', -1, 64) + "s")
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Unavailable is the status code retried by DefaultRetryPolicy.
+const Unavailable = "UNAVAILABLE"
+
+// DefaultRetryPolicy retries Unavailable up to three times, backing off
+// from 100ms to 2s.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:          4,
+		InitialBackoff:       Duration(100 * time.Millisecond),
+		MaxBackoff:           Duration(2 * time.Second),
+		BackoffMultiplier:    2,
+		RetryableStatusCodes: []string{Unavailable},
+	}
+}
+
+// DefaultRetryThrottling disables retries once more than half of the recent
+// calls have failed.
+func DefaultRetryThrottling() *RetryThrottling {
+	return &RetryThrottling{MaxTokens: 10, TokenRatio: 0.1}
+}
+
+// Validate reports configuration errors grpc would otherwise only surface
+// as a failed Dial.
+func (sc *ServiceConfig) Validate() error {
+	for i, mc := range sc.MethodConfig {
+		if len(mc.Name) == 0 {
+			return fmt.Errorf("dial: methodConfig[%d] has no name", i)
+		}
+		if mc.RetryPolicy != nil && mc.HedgingPolicy != nil {
+			return fmt.Errorf("dial: methodConfig[%d] sets both retryPolicy and hedgingPolicy", i)
+		}
+		if p := mc.RetryPolicy; p != nil {
+			if p.MaxAttempts < 2 {
+				return fmt.Errorf("dial: methodConfig[%d] retryPolicy.maxAttempts must be at least 2", i)
+			}
+			if p.InitialBackoff <= 0 || p.MaxBackoff <= 0 || p.BackoffMultiplier <= 0 {
+				return fmt.Errorf("dial: methodConfig[%d] retryPolicy backoff must be positive", i)
+			}
+			if len(p.RetryableStatusCodes) == 0 {
+				return fmt.Errorf("dial: methodConfig[%d] retryPolicy.retryableStatusCodes is empty", i)
+			}
+		}
+		if p := mc.HedgingPolicy; p != nil && p.MaxAttempts < 2 {
+			return fmt.Errorf("dial: methodConfig[%d] hedgingPolicy.maxAttempts must be at least 2", i)
+		}
+	}
+	return nil
+}
+
+// JSON returns the service config in the form accepted by
+// grpc.WithDefaultServiceConfig. Status codes are upper cased so the file
+// may spell them either way.
+func (sc *ServiceConfig) JSON() (string, error) {
+	if err := sc.Validate(); err != nil {
+		return "", err
+	}
+	out := *sc
+	out.MethodConfig = make([]MethodConfig, len(sc.MethodConfig))
+	for i, mc := range sc.MethodConfig {
+		if mc.RetryPolicy != nil {
+			p := *mc.RetryPolicy
+			p.RetryableStatusCodes = upper(p.RetryableStatusCodes)
+			mc.RetryPolicy = &p
+		}
+		if mc.HedgingPolicy != nil {
+			p := *mc.HedgingPolicy
+			p.NonFatalStatusCodes = upper(p.NonFatalStatusCodes)
+			mc.HedgingPolicy = &p
+		}
+		out.MethodConfig[i] = mc
+	}
+	b, err := json.Marshal(&out)
+	if err != nil {
+		return "", fmt.Errorf("dial: encoding service config: %v", err)
+	}
+	return string(b), nil
+}
+
+func upper(codes []string) []string {
+	out := make([]string, len(codes))
+	for i, c := range codes {
+		out[i] = strings.ToUpper(c)
+	}
+	return out
+}
+
+// LoadServiceConfig reads a JSON encoded ServiceConfig from path.
+func LoadServiceConfig(path string) (*ServiceConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("dial: reading service config: %v", err)
+	}
+	sc := &ServiceConfig{}
+	if err := json.Unmarshal(b, sc); err != nil {
+		return nil, fmt.Errorf("dial: parsing service config %s: %v", path, err)
+	}
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
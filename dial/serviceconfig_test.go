@@ -0,0 +1,154 @@
+package dial
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// grpcParse runs js through grpc's own service config parser, which
+// NewClient applies to a default service config.
+func grpcParse(js string) error {
+	cc, err := grpc.NewClient("passthrough:///test",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(js))
+	if err != nil {
+		return err
+	}
+	return cc.Close()
+}
+
+func methodConfig(mc MethodConfig) *ServiceConfig {
+	mc.Name = append(mc.Name, Name{Service: "test.Service"})
+	return &ServiceConfig{MethodConfig: []MethodConfig{mc}, RetryThrottling: DefaultRetryThrottling()}
+}
+
+func TestServiceConfigJSON(t *testing.T) {
+	retry := func(edit func(*RetryPolicy)) *RetryPolicy {
+		p := DefaultRetryPolicy()
+		edit(p)
+		return p
+	}
+	hedge := &HedgingPolicy{MaxAttempts: 3, HedgingDelay: Duration(200 * time.Millisecond), NonFatalStatusCodes: []string{Unavailable}}
+
+	tests := []struct {
+		name    string
+		sc      *ServiceConfig
+		wantErr string
+		//grpcRejects is set when grpc's parser also refuses the config,
+		//so Validate only reports the failure earlier.
+		grpcRejects bool
+	}{
+		{name: "retry", sc: methodConfig(MethodConfig{Timeout: Duration(5 * time.Second), RetryPolicy: DefaultRetryPolicy()})},
+		{name: "hedging", sc: methodConfig(MethodConfig{HedgingPolicy: hedge})},
+		{name: "timeout only", sc: methodConfig(MethodConfig{Timeout: Duration(1500 * time.Millisecond), WaitForReady: true})},
+		{name: "lower case codes", sc: methodConfig(MethodConfig{RetryPolicy: retry(func(p *RetryPolicy) {
+			p.RetryableStatusCodes = []string{"unavailable", "Resource_Exhausted"}
+		})}), grpcRejects: true},
+		//grpc-go ignores hedgingPolicy, so only Validate catches this.
+		{name: "retry and hedging", sc: methodConfig(MethodConfig{RetryPolicy: DefaultRetryPolicy(), HedgingPolicy: hedge}), wantErr: "both retryPolicy and hedgingPolicy"},
+		{name: "no name", sc: &ServiceConfig{MethodConfig: []MethodConfig{{RetryPolicy: DefaultRetryPolicy()}}}, wantErr: "has no name"},
+		{name: "one attempt", sc: methodConfig(MethodConfig{RetryPolicy: retry(func(p *RetryPolicy) { p.MaxAttempts = 1 })}), wantErr: "maxAttempts", grpcRejects: true},
+		{name: "zero backoff", sc: methodConfig(MethodConfig{RetryPolicy: retry(func(p *RetryPolicy) { p.InitialBackoff = 0 })}), wantErr: "backoff", grpcRejects: true},
+		{name: "zero multiplier", sc: methodConfig(MethodConfig{RetryPolicy: retry(func(p *RetryPolicy) { p.BackoffMultiplier = 0 })}), wantErr: "backoff", grpcRejects: true},
+		{name: "no retryable codes", sc: methodConfig(MethodConfig{RetryPolicy: retry(func(p *RetryPolicy) { p.RetryableStatusCodes = nil })}), wantErr: "retryableStatusCodes", grpcRejects: true},
+		{name: "one hedge", sc: methodConfig(MethodConfig{HedgingPolicy: &HedgingPolicy{MaxAttempts: 1}}), wantErr: "hedgingPolicy.maxAttempts"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			js, err := tc.sc.JSON()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("JSON error = %v, want %q", err, tc.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("JSON: %v", err)
+				}
+				if err := grpcParse(js); err != nil {
+					t.Fatalf("grpc rejected %s: %v", js, err)
+				}
+			}
+
+			//The same config as written, without Validate or the code
+			//upper casing in JSON.
+			raw, err := json.Marshal(tc.sc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := grpcParse(string(raw)); (err != nil) != tc.grpcRejects {
+				t.Errorf("grpc parsing %s: error %v, want error %v", raw, err, tc.grpcRejects)
+			}
+		})
+	}
+}
+
+func TestDurationJSON(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{100 * time.Millisecond, `"0.1s"`},
+		{2 * time.Second, `"2s"`},
+		{1500 * time.Microsecond, `"0.0015s"`},
+	}
+	for _, tc := range tests {
+		b, err := json.Marshal(Duration(tc.d))
+		if err != nil || string(b) != tc.want {
+			t.Errorf("Marshal(%v) = %s, %v, want %s", tc.d, b, err, tc.want)
+		}
+		var d Duration
+		if err := json.Unmarshal([]byte(`"`+tc.d.String()+`"`), &d); err != nil || time.Duration(d) != tc.d {
+			t.Errorf("Unmarshal(%q) = %v, %v", tc.d.String(), time.Duration(d), err)
+		}
+	}
+}
+
+func TestLoadServiceConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	good := write("good.json", `{"methodConfig":[{"name":[{"service":"test.Service"}],"timeout":"250ms",
+		"retryPolicy":{"maxAttempts":3,"initialBackoff":"100ms","maxBackoff":"1s","backoffMultiplier":2,"retryableStatusCodes":["unavailable"]}}]}`)
+	both := write("both.json", `{"methodConfig":[{"name":[{"service":"test.Service"}],
+		"retryPolicy":{"maxAttempts":3,"initialBackoff":"100ms","maxBackoff":"1s","backoffMultiplier":2,"retryableStatusCodes":["UNAVAILABLE"]},
+		"hedgingPolicy":{"maxAttempts":2}}]}`)
+
+	sc, err := Load(good, nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	js, err := sc.JSON()
+	if err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	if !strings.Contains(js, `"timeout":"0.25s"`) || !strings.Contains(js, `"UNAVAILABLE"`) {
+		t.Errorf("JSON = %s", js)
+	}
+	if err := grpcParse(js); err != nil {
+		t.Errorf("grpc rejected %s: %v", js, err)
+	}
+
+	for _, path := range []string{both, write("bad.json", `{"methodConfig":`), filepath.Join(dir, "missing.json")} {
+		if _, err := Load(path, nil); err == nil {
+			t.Errorf("Load(%s) succeeded", filepath.Base(path))
+		}
+	}
+
+	fallback := methodConfig(MethodConfig{RetryPolicy: DefaultRetryPolicy()})
+	if sc, err := Load("", fallback); sc != fallback || err != nil {
+		t.Errorf("Load with no path = %v, %v, want the fallback", sc, err)
+	}
+}
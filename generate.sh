@@ -0,0 +1,11 @@
+#!/bin/sh
+# Regenerates the gRPC code and the OpenAPI documents. Run from the repo root.
+set -e
+
+for proto in greet/greetpb/greet.proto calculator/calculatorpb/calculator.proto blog/blogpb/blog.proto; do
+	protoc "$proto" --go_out=plugins=grpc:.
+done
+
+go run ./cmd/openapigen -proto greet/greetpb/greet.proto -out greet/greetpb/greet.openapi.json
+go run ./cmd/openapigen -proto calculator/calculatorpb/calculator.proto -out calculator/calculatorpb/calculator.openapi.json
+go run ./cmd/openapigen -proto blog/blogpb/blog.proto -rules blog/blogpb/blog.http.json -out blog/blogpb/blog.openapi.json
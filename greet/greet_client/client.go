@@ -2,43 +2,51 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"time"
 
-	"google.golang.org/grpc/credentials"
-
 	"google.golang.org/grpc/codes"
 
 	"google.golang.org/grpc/status"
 
+	"github.com/jwfrizzell/grpc-go-course/dial"
 	"github.com/jwfrizzell/grpc-go-course/greet/greetpb"
-
-	"google.golang.org/grpc"
 )
 
 type options struct{}
 
+// defaultServiceConfig retries Greet, which has no side effects.
+// GreetWithDeadline is left alone so its deadline demo still fails.
+var defaultServiceConfig = &dial.ServiceConfig{
+	MethodConfig: []dial.MethodConfig{
+		{
+			Name:        []dial.Name{{Service: "greet.GreetService", Method: "Greet"}},
+			Timeout:     dial.Duration(5 * time.Second),
+			RetryPolicy: dial.DefaultRetryPolicy(),
+		},
+	},
+	RetryThrottling: dial.DefaultRetryThrottling(),
+}
+
 func main() {
-	fmt.Println("Establishing Client Connection...")
+	flags := dial.RegisterFlags(flag.CommandLine, dial.Defaults{
+		ServiceName:   "greet-client",
+		TLS:           true,
+		ServiceConfig: defaultServiceConfig,
+	})
+	flag.Parse()
 
-	tls := true
-	opts := grpc.WithInsecure()
-	if tls {
-		creds, err := credentials.NewClientTLSFromFile("ssl/ca.crt", "")
-		if err != nil {
-			log.Fatalf("Unable to create client from file. Error: %v", err)
-			return
-		}
-		opts = grpc.WithTransportCredentials(creds)
-	}
+	fmt.Println("Establishing Client Connection...")
 
-	cc, err := grpc.Dial("localhost:50051", opts)
-	defer cc.Close()
+	conn, err := dial.Setup("localhost:50051", flags)
 	if err != nil {
 		log.Fatalf("Connection Failed: %s", err)
 	}
+	defer conn.Close(context.Background())
+	cc := conn.Conn
 
 	c := greetpb.NewGreetServiceClient(cc)
 
@@ -50,6 +58,8 @@ func main() {
 
 	//doBiDirectionalStreaming(c)
 
+	//doStreamingDisconnect(c)
+
 	// doUnaryWithDeadline(c, time.Second*5)
 	// doUnaryWithDeadline(c, time.Second)
 }
@@ -241,6 +251,46 @@ func doBiDirectionalStreaming(c greetpb.GreetServiceClient) {
 	<-waitc
 }
 
+// doStreamingDisconnect abandons LongGreet and GreetEveryone mid-stream and
+// then checks that the server still answers other calls.
+func doStreamingDisconnect(c greetpb.GreetServiceClient) {
+	fmt.Println("Start Mid-Stream Disconnect...")
+	g := &greetpb.Greeting{
+		FirstName: "Joe",
+		LastName:  "Frizzell",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lg, err := c.LongGreet(ctx)
+	if err != nil {
+		log.Fatalf("LongGreet() Failure: %v", err)
+	}
+	if err := lg.Send(&greetpb.LongGreetRequest{Greeting: g}); err != nil {
+		log.Fatalf("LongGreet() Send Failure: %v", err)
+	}
+	cancel()
+	_, err = lg.CloseAndRecv()
+	fmt.Printf("LongGreet after cancel: %v\n", status.Code(err))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	ge, err := c.GreetEveryone(ctx)
+	if err != nil {
+		log.Fatalf("GreetEveryone() Failure: %v", err)
+	}
+	if err := ge.Send(&greetpb.GreetEveryoneRequest{Greeting: g}); err != nil {
+		log.Fatalf("GreetEveryone() Send Failure: %v", err)
+	}
+	if _, err := ge.Recv(); err != nil {
+		log.Fatalf("GreetEveryone() Recv Failure: %v", err)
+	}
+	cancel()
+	_, err = ge.Recv()
+	fmt.Printf("GreetEveryone after cancel: %v\n", status.Code(err))
+
+	//The server must still be up for everyone else.
+	doUnary(c)
+}
+
 func doUnaryWithDeadline(c greetpb.GreetServiceClient, time time.Duration) {
 	fmt.Println("Start Dealine Unary Streaming...")
 
@@ -2,26 +2,37 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"log"
-	"net"
+	"log/slog"
+	"os"
 	"time"
 
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/reflection"
-
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/jwfrizzell/grpc-go-course/greet/greetpb"
-	"google.golang.org/grpc"
+	"github.com/jwfrizzell/grpc-go-course/logging"
+	"github.com/jwfrizzell/grpc-go-course/serverutil"
+	"github.com/jwfrizzell/grpc-go-course/tlsconfig"
 )
 
-type server struct{}
+type server struct {
+	log *slog.Logger
+}
+
+// logger returns the request scoped logger for ctx.
+func (s *server) logger(ctx context.Context) *slog.Logger {
+	return logging.FromContext(ctx, s.log)
+}
 
 func (s *server) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
-	fmt.Printf("Invoking Greet() function. Request: %v\n", req)
+	s.logger(ctx).Debug("Invoking Greet() function...")
+	if id, ok := tlsconfig.ClientIdentity(ctx); ok {
+		s.logger(ctx).Debug("Verified client certificate", "common_name", id.CommonName, "serial", id.Serial)
+	}
 
 	firstName := req.GetGreeting().GetFirstName()
 	lastName := req.GetGreeting().GetLastName()
@@ -34,7 +45,7 @@ func (s *server) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb
 }
 
 func (s *server) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreetService_GreetManyTimesServer) error {
-	fmt.Printf("Invoking GreetManyTimes() function. Request: %v\n", req)
+	s.logger(stream.Context()).Debug("Invoking GreetManyTimes() function...")
 
 	firstName := req.GetGreeting().GetLastName()
 	lastName := req.GetGreeting().GetLastName()
@@ -44,14 +55,16 @@ func (s *server) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greet
 		resp := &greetpb.GreetManyTimesResponse{
 			Result: fmt.Sprintf("Hello %s %s. You are number %d.", firstName, lastName, i),
 		}
-		stream.Send(resp)
+		if err := stream.Send(resp); err != nil {
+			return logging.StreamError(stream.Context(), s.log, err, "GreetManyTimes Send Failure")
+		}
 		time.Sleep(time.Second)
 	}
 	return nil
 }
 
 func (s *server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
-	fmt.Printf("Invoking LongGreet() function...")
+	s.logger(stream.Context()).Debug("Invoking LongGreet() function...")
 	result := "Hi %s %s! "
 	var a string
 	for {
@@ -62,7 +75,7 @@ func (s *server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
 			})
 		}
 		if err != nil {
-			log.Fatalf("LongGreet Stream Failure: %v", err)
+			return logging.StreamError(stream.Context(), s.log, err, "LongGreet Stream Failure")
 		}
 
 		fn := req.GetGreeting().GetFirstName()
@@ -73,7 +86,7 @@ func (s *server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
 }
 
 func (s *server) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer) error {
-	fmt.Println("Invoking GreetEveryone() function...")
+	s.logger(stream.Context()).Debug("Invoking GreetEveryone() function...")
 	fs := "Hello %s %s! "
 	for {
 		ger, err := stream.Recv()
@@ -81,28 +94,26 @@ func (s *server) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer)
 			return nil
 		}
 		if err != nil {
-			log.Fatalf("Receive Stream Failure: %v", err)
-			return err
+			return logging.StreamError(stream.Context(), s.log, err, "GreetEveryone Receive Failure")
 		}
 		result := fmt.Sprintf(fs, ger.GetGreeting().GetFirstName(), ger.GetGreeting().GetLastName())
 		err = stream.Send(&greetpb.GreetEveryoneResponse{
 			Result: result,
 		})
 		if err != nil {
-			log.Fatalf("Error when sending streaming data to client. Error: %v", err)
-			return err
+			return logging.StreamError(stream.Context(), s.log, err, "GreetEveryone Send Failure")
 		}
 
 	}
 }
 
 func (s *server) GreetWithDeadline(ctx context.Context, req *greetpb.GreetWithDeadlineRequest) (*greetpb.GreetWithDeadlineResponse, error) {
-	fmt.Println("Invoking GreetWithDealine() function...")
+	s.logger(ctx).Debug("Invoking GreetWithDeadline() function...")
 
 	for i := 0; i < 3; i++ {
 		if ctx.Err() == context.Canceled {
 			//Client cancelled request.
-			fmt.Println("Client cancelled request...")
+			s.logger(ctx).Info("Client cancelled request...")
 			return nil, status.Error(codes.DeadlineExceeded, "Client has cancelled the request.")
 		}
 		time.Sleep(time.Second)
@@ -118,32 +129,26 @@ func (s *server) GreetWithDeadline(ctx context.Context, req *greetpb.GreetWithDe
 }
 
 func main() {
-	fmt.Println("GRPC Server has started...")
-
-	tls := true
-	opts := []grpc.ServerOption{}
-	if tls {
-		certfile := "ssl/server.crt"
-		keyFile := "ssl/server.pem"
-		creds, err := credentials.NewServerTLSFromFile(certfile, keyFile)
-		if err != nil {
-			log.Fatalf("Unable to create New Client TLS From File. SSL Error: %v", err)
-			return
-		}
-		opts = append(opts, grpc.Creds(creds))
-	}
-	s := grpc.NewServer(opts...)
-	lis, err := net.Listen("tcp", "localhost:50051")
+	flags := serverutil.RegisterFlags(flag.CommandLine, serverutil.Defaults{
+		ServiceName: "greet-server",
+		Addr:        "localhost:50051",
+		TLS:         true,
+	})
+	flag.Parse()
+
+	srv, err := serverutil.Setup(flags)
 	if err != nil {
-		log.Fatalf("Listen Failure: %s", err)
+		log.Fatalf("Server Setup Failure: %v", err)
 	}
+	defer srv.Close(context.Background())
+	srv.Logger.Info("GRPC Server has started...")
 
-	greetpb.RegisterGreetServiceServer(s, &server{})
-	reflection.Register(s)
+	s := srv.NewGRPCServer(serverutil.Interceptors{})
+	greetpb.RegisterGreetServiceServer(s, &server{log: srv.Logger})
 
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("Serve Failure: %s", err)
+	if err := srv.Serve(); err != nil {
+		srv.Logger.Error("Serve Failure", "error", err)
+		os.Exit(1)
 	}
-	fmt.Println("Server running...")
 
 }
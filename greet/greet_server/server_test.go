@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/jwfrizzell/grpc-go-course/greet/greetpb"
+)
+
+// startServer serves greet over an in-memory listener. The returned channel
+// receives the error every stream handler returns.
+func startServer(t *testing.T) (greetpb.GreetServiceClient, <-chan error) {
+	t.Helper()
+	handled := make(chan error, 16)
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(grpc.ChainStreamInterceptor(
+		func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			err := handler(srv, ss)
+			handled <- err
+			return err
+		},
+	))
+	greetpb.RegisterGreetServiceServer(s, &server{log: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	cc, err := grpc.Dial("passthrough:///bufconn",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return greetpb.NewGreetServiceClient(cc), handled
+}
+
+// handlerError waits for the next stream handler to return.
+func handlerError(t *testing.T, handled <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-handled:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream handler did not return")
+		return nil
+	}
+}
+
+func greeting(first string) *greetpb.Greeting {
+	return &greetpb.Greeting{FirstName: first, LastName: "Test"}
+}
+
+// streamCases open a stream, send one message and leave it open, then
+// finish a second stream normally.
+var streamCases = []struct {
+	name     string
+	open     func(ctx context.Context, c greetpb.GreetServiceClient) error
+	complete func(ctx context.Context, c greetpb.GreetServiceClient) error
+}{
+	{
+		name: "LongGreet",
+		open: func(ctx context.Context, c greetpb.GreetServiceClient) error {
+			stream, err := c.LongGreet(ctx)
+			if err != nil {
+				return err
+			}
+			return stream.Send(&greetpb.LongGreetRequest{Greeting: greeting("Ann")})
+		},
+		complete: func(ctx context.Context, c greetpb.GreetServiceClient) error {
+			stream, err := c.LongGreet(ctx)
+			if err != nil {
+				return err
+			}
+			for _, n := range []string{"Ann", "Bob"} {
+				if err := stream.Send(&greetpb.LongGreetRequest{Greeting: greeting(n)}); err != nil {
+					return err
+				}
+			}
+			resp, err := stream.CloseAndRecv()
+			if err != nil {
+				return err
+			}
+			if want := "Hi Ann Test! Hi Bob Test! "; resp.GetResult() != want {
+				return status.Errorf(codes.Internal, "result = %q, want %q", resp.GetResult(), want)
+			}
+			return nil
+		},
+	},
+	{
+		name: "GreetEveryone",
+		open: func(ctx context.Context, c greetpb.GreetServiceClient) error {
+			stream, err := c.GreetEveryone(ctx)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(&greetpb.GreetEveryoneRequest{Greeting: greeting("Ann")}); err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		},
+		complete: func(ctx context.Context, c greetpb.GreetServiceClient) error {
+			stream, err := c.GreetEveryone(ctx)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(&greetpb.GreetEveryoneRequest{Greeting: greeting("Bob")}); err != nil {
+				return err
+			}
+			stream.CloseSend()
+			resp, err := stream.Recv()
+			if err != nil {
+				return err
+			}
+			if want := "Hello Bob Test! "; resp.GetResult() != want {
+				return status.Errorf(codes.Internal, "result = %q, want %q", resp.GetResult(), want)
+			}
+			if _, err := stream.Recv(); err != io.EOF {
+				return status.Errorf(codes.Internal, "expected end of stream, got %v", err)
+			}
+			return nil
+		},
+	},
+}
+
+func TestStreamDisconnect(t *testing.T) {
+	for _, tc := range streamCases {
+		t.Run(tc.name+"/cancel", func(t *testing.T) {
+			c, handled := startServer(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			if err := tc.open(ctx, c); err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			cancel()
+			if code := status.Code(handlerError(t, handled)); code != codes.Canceled {
+				t.Errorf("handler returned %v, want Canceled", code)
+			}
+
+			if err := tc.complete(context.Background(), c); err != nil {
+				t.Errorf("following call: %v", err)
+			}
+			if err := handlerError(t, handled); err != nil {
+				t.Errorf("following handler returned %v", err)
+			}
+		})
+
+		t.Run(tc.name+"/deadline", func(t *testing.T) {
+			c, handled := startServer(t)
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			if err := tc.open(ctx, c); err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			//The client resets the stream when its deadline passes, which
+			//usually beats the server's copy of the deadline.
+			if code := status.Code(handlerError(t, handled)); code != codes.Canceled && code != codes.DeadlineExceeded {
+				t.Errorf("handler returned %v, want Canceled or DeadlineExceeded", code)
+			}
+
+			if err := tc.complete(context.Background(), c); err != nil {
+				t.Errorf("following call: %v", err)
+			}
+			if _, err := c.Greet(context.Background(), &greetpb.GreetRequest{Greeting: greeting("Cy")}); err != nil {
+				t.Errorf("following unary call: %v", err)
+			}
+		})
+	}
+}
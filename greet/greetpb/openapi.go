@@ -0,0 +1,9 @@
+package greetpb
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI v3 document for the services in greet.proto,
+// written by generate.sh.
+//
+//go:embed greet.openapi.json
+var OpenAPI []byte
@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDKey is the metadata key used to carry request IDs between processes.
+const RequestIDKey = "x-request-id"
+
+type requestIDKey struct{}
+
+// NewRequestID returns a random 128 bit hex identifier.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// WithRequestID returns a copy of ctx carrying id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// incomingRequestID reuses the caller's x-request-id or assigns a new one.
+func incomingRequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(RequestIDKey); len(v) > 0 && v[0] != "" {
+			return v[0]
+		}
+	}
+	return NewRequestID()
+}
+
+// prepare tags ctx with a request ID and a logger scoped to the call.
+func prepare(ctx context.Context, logger *slog.Logger, method string) (context.Context, *slog.Logger) {
+	id := incomingRequestID(ctx)
+	addr := "unknown"
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+	l := logger.With(
+		slog.String("request_id", id),
+		slog.String("method", method),
+		slog.String("peer", addr),
+	)
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, id))
+	ctx = WithRequestID(ctx, id)
+	return NewContext(ctx, l), l
+}
+
+func logCall(l *slog.Logger, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+	}
+	l.LogAttrs(context.Background(), level, "rpc finished",
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+// UnaryServerInterceptor assigns or propagates a request ID and logs each unary call.
+func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx, l := prepare(ctx, logger, info.FullMethod)
+		resp, err := handler(ctx, req)
+		logCall(l, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor assigns or propagates a request ID and logs each stream.
+func StreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, l := prepare(ss.Context(), logger, info.FullMethod)
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCall(l, start, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor forwards the request ID in ctx, creating one if needed.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoing(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor forwards the request ID in ctx, creating one if needed.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoing(ctx), desc, cc, method, opts...)
+	}
+}
+
+func outgoing(ctx context.Context) context.Context {
+	id := RequestID(ctx)
+	if id == "" {
+		id = NewRequestID()
+	}
+	return metadata.AppendToOutgoingContext(ctx, RequestIDKey, id)
+}
+
+// serverStream overrides Context so handlers see the tagged context.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StreamError converts a failed Recv or Send into the status error a stream
+// handler returns to grpc, logging it with the request scoped logger from
+// ctx. A stream whose context ended, or whose transport reported a deadline
+// or cancellation, is logged at info level; anything else is a warning and
+// becomes Internal. Only the failing stream is torn down.
+//
+// The code comes from ctx.Err() when the context has ended, and from err
+// otherwise. A client whose own deadline passes resets the stream before
+// the server's copy of that deadline fires, so it usually shows up here as
+// Canceled; the server cannot tell it from any other reset.
+func StreamError(ctx context.Context, fallback *slog.Logger, err error, msg string) error {
+	logger := FromContext(ctx, fallback)
+	code := status.Code(err)
+	if ctx.Err() != nil {
+		code = status.FromContextError(ctx.Err()).Code()
+	} else if code == codes.Unknown {
+		//Handlers pass ctx.Err() itself once the context is done.
+		code = status.FromContextError(err).Code()
+	}
+	switch code {
+	case codes.DeadlineExceeded:
+		logger.Info(msg, "reason", "deadline exceeded")
+		return status.Errorf(codes.DeadlineExceeded, "%s: deadline exceeded", msg)
+	case codes.Canceled:
+		logger.Info(msg, "reason", "client cancelled stream")
+		return status.Errorf(codes.Canceled, "%s: client cancelled the stream", msg)
+	}
+	logger.Warn(msg, "error", err)
+	return status.Errorf(codes.Internal, "%s: %v", msg, err)
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStreamError(t *testing.T) {
+	live := func(*testing.T) context.Context { return context.Background() }
+	cancelled := func(*testing.T) context.Context {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		return ctx
+	}
+	expired := func(t *testing.T) context.Context {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		t.Cleanup(cancel)
+		return ctx
+	}
+	//A cancel just before the deadline is still a cancel.
+	cancelledNearDeadline := func(*testing.T) context.Context {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(5*time.Millisecond))
+		cancel()
+		return ctx
+	}
+	//What Recv returns when the client resets the stream; an ended
+	//context decides the code over it.
+	rst := status.Error(codes.Canceled, "stream terminated by RST_STREAM")
+
+	tests := []struct {
+		name  string
+		ctx   func(*testing.T) context.Context
+		err   error
+		code  codes.Code
+		level string
+	}{
+		{"cancelled context", cancelled, rst, codes.Canceled, "INFO"},
+		{"cancelled near deadline", cancelledNearDeadline, rst, codes.Canceled, "INFO"},
+		{"expired context", expired, rst, codes.DeadlineExceeded, "INFO"},
+		{"context error passed in", expired, context.DeadlineExceeded, codes.DeadlineExceeded, "INFO"},
+		{"deadline status", live, status.Error(codes.DeadlineExceeded, "deadline"), codes.DeadlineExceeded, "INFO"},
+		{"cancel status", live, rst, codes.Canceled, "INFO"},
+		{"raw cancel", live, context.Canceled, codes.Canceled, "INFO"},
+		{"wrapped deadline", live, errors.Join(errors.New("send"), context.DeadlineExceeded), codes.DeadlineExceeded, "INFO"},
+		{"transport failure", live, io.ErrUnexpectedEOF, codes.Internal, "WARN"},
+		{"other status", live, status.Error(codes.Unavailable, "gone"), codes.Internal, "WARN"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, nil))
+			err := StreamError(tc.ctx(t), logger, tc.err, "Test Stream")
+			if status.Code(err) != tc.code {
+				t.Errorf("StreamError = %v, want %v", err, tc.code)
+			}
+			if !strings.Contains(status.Convert(err).Message(), "Test Stream") {
+				t.Errorf("message %q does not name the stream", status.Convert(err).Message())
+			}
+			if !strings.Contains(buf.String(), "level="+tc.level) {
+				t.Errorf("logged %q, want level %s", buf.String(), tc.level)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+// Package ratelimit throttles callers with a token bucket per client and
+// method, and caps how many streams a client may hold open at once.
+// Clients are keyed by authenticated principal, falling back to the peer
+// address.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/jwfrizzell/grpc-go-course/auth"
+)
+
+// RetryAfterKey is the trailer telling a throttled client how many seconds
+// to wait before retrying.
+const RetryAfterKey = "retry-after"
+
+// Rule is a token bucket: Rate tokens per second, up to Burst at once.
+// A zero Rate disables limiting.
+type Rule struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// Config holds the per-method rules. Method names are full gRPC method
+// names such as "/calculator.CalculatorService/FindMax".
+type Config struct {
+	//Default applies to methods without their own rule.
+	Default Rule            `json:"default"`
+	Methods map[string]Rule `json:"methods"`
+	//MaxStreams caps concurrent streams per client. Zero means no cap.
+	MaxStreams int `json:"max_streams"`
+	//IdleTimeout drops state for clients not seen for this long.
+	IdleTimeout Duration `json:"idle_timeout"`
+}
+
+// Duration is a time.Duration that reads "30s" style strings from JSON.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// LoadConfig reads a JSON encoded Config from path.
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ratelimit: reading config: %v", err)
+	}
+	cfg := &Config{}
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("ratelimit: parsing config %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+const defaultIdleTimeout = 10 * time.Minute
+
+type bucketKey struct {
+	client, method string
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter enforces a Config. Create it with New.
+type Limiter struct {
+	cfg  Config
+	idle time.Duration
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	streams   map[string]int
+	lastSweep time.Time
+}
+
+// New returns a Limiter enforcing cfg.
+func New(cfg Config) *Limiter {
+	idle := time.Duration(cfg.IdleTimeout)
+	if idle <= 0 {
+		idle = defaultIdleTimeout
+	}
+	return &Limiter{
+		cfg:       cfg,
+		idle:      idle,
+		buckets:   make(map[bucketKey]*bucket),
+		streams:   make(map[string]int),
+		lastSweep: time.Now(),
+	}
+}
+
+// clientKey identifies the caller: the authenticated subject when there is
+// one, otherwise the peer's IP address.
+func clientKey(ctx context.Context) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		return "principal:" + p.Subject
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		addr := p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+		return "peer:" + addr
+	}
+	return "unknown"
+}
+
+func (l *Limiter) rule(method string) Rule {
+	if r, ok := l.cfg.Methods[method]; ok {
+		return r
+	}
+	return l.cfg.Default
+}
+
+// sweep drops buckets that have been idle for longer than l.idle.
+// The caller holds l.mu.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.idle {
+		return
+	}
+	for k, b := range l.buckets {
+		if now.Sub(b.lastSeen) > l.idle {
+			delete(l.buckets, k)
+		}
+	}
+	l.lastSweep = now
+}
+
+// take spends one token for client on method. It returns how long the
+// client should wait when no token is available.
+func (l *Limiter) take(client, method string) (time.Duration, bool) {
+	r := l.rule(method)
+	if r.Rate <= 0 {
+		return 0, true
+	}
+	now := time.Now()
+
+	l.mu.Lock()
+	l.sweep(now)
+	k := bucketKey{client, method}
+	b, ok := l.buckets[k]
+	if !ok {
+		burst := r.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(r.Rate), burst)}
+		l.buckets[k] = b
+	}
+	b.lastSeen = now
+	l.mu.Unlock()
+
+	if b.limiter.AllowN(now, 1) {
+		return 0, true
+	}
+	res := b.limiter.ReserveN(now, 1)
+	wait := res.DelayFrom(now)
+	res.CancelAt(now)
+	return wait, false
+}
+
+// exhausted builds the ResourceExhausted error and retry-after trailer.
+func exhausted(ctx context.Context, method string, wait time.Duration) error {
+	secs := int64((wait + time.Second - 1) / time.Second)
+	grpc.SetTrailer(ctx, metadata.Pairs(RetryAfterKey, strconv.FormatInt(secs, 10)))
+	st := status.Newf(codes.ResourceExhausted, "rate limit exceeded for %s, retry in %v", method, wait.Round(time.Millisecond))
+	if d, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)}); err == nil {
+		st = d
+	}
+	return st.Err()
+}
+
+func (l *Limiter) acquireStream(client string) bool {
+	if l.cfg.MaxStreams <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.streams[client] >= l.cfg.MaxStreams {
+		return false
+	}
+	l.streams[client]++
+	return true
+}
+
+func (l *Limiter) releaseStream(client string) {
+	if l.cfg.MaxStreams <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.streams[client]--; l.streams[client] <= 0 {
+		delete(l.streams, client)
+	}
+}
+
+// UnaryServerInterceptor throttles unary calls. Place it after the auth
+// interceptor so callers are keyed by principal.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if wait, ok := l.take(clientKey(ctx), info.FullMethod); !ok {
+			return nil, exhausted(ctx, info.FullMethod, wait)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor throttles stream creation and caps the number of
+// streams a client may have open at once.
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		client := clientKey(ctx)
+		if wait, ok := l.take(client, info.FullMethod); !ok {
+			return exhausted(ctx, info.FullMethod, wait)
+		}
+		if !l.acquireStream(client) {
+			return status.Errorf(codes.ResourceExhausted, "too many concurrent streams, limit is %d", l.cfg.MaxStreams)
+		}
+		defer l.releaseStream(client)
+		return handler(srv, ss)
+	}
+}
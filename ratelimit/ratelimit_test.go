@@ -0,0 +1,226 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/jwfrizzell/grpc-go-course/auth"
+)
+
+const (
+	methodCheck = "/grpc.health.v1.Health/Check"
+	methodList  = "/grpc.health.v1.Health/List"
+)
+
+// userKey is the test metadata naming the principal, standing in for the
+// auth interceptor.
+const userKey = "x-test-user"
+
+func withUser(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if u := md.Get(userKey); len(u) > 0 {
+		return auth.NewContext(ctx, &auth.Principal{Subject: u[0]})
+	}
+	return ctx
+}
+
+type userStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *userStream) Context() context.Context {
+	return s.ctx
+}
+
+// startServer serves the health service behind l.
+func startServer(t *testing.T, l *Limiter) healthpb.HealthClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				return handler(withUser(ctx), req)
+			},
+			l.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(
+			func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				return handler(srv, &userStream{ServerStream: ss, ctx: withUser(ss.Context())})
+			},
+			l.StreamServerInterceptor()),
+	)
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	cc, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return healthpb.NewHealthClient(cc)
+}
+
+func as(user string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), userKey, user)
+}
+
+func TestUnaryBuckets(t *testing.T) {
+	l := New(Config{
+		Default: Rule{Rate: 0.001, Burst: 1},
+		Methods: map[string]Rule{methodCheck: {Rate: 0.001, Burst: 2}},
+	})
+	client := startServer(t, l)
+	check := func(user string) error {
+		_, err := client.Check(as(user), &healthpb.HealthCheckRequest{})
+		return err
+	}
+	list := func(user string) error {
+		_, err := client.List(as(user), &healthpb.HealthListRequest{})
+		return err
+	}
+
+	steps := []struct {
+		name string
+		call func(string) error
+		user string
+		want codes.Code
+	}{
+		{name: "ann first check", call: check, user: "ann"},
+		{name: "ann second check", call: check, user: "ann"},
+		{name: "ann over the method burst", call: check, user: "ann", want: codes.ResourceExhausted},
+		{name: "bob has a separate bucket", call: check, user: "bob"},
+		{name: "ann list uses the default rule", call: list, user: "ann"},
+		{name: "ann over the default burst", call: list, user: "ann", want: codes.ResourceExhausted},
+		{name: "bob list", call: list, user: "bob"},
+	}
+	for _, s := range steps {
+		if got := status.Code(s.call(s.user)); got != s.want {
+			t.Errorf("%s: code = %v, want %v", s.name, got, s.want)
+		}
+	}
+}
+
+func TestRetryInfo(t *testing.T) {
+	l := New(Config{Methods: map[string]Rule{methodCheck: {Rate: 0.5, Burst: 1}}})
+	client := startServer(t, l)
+
+	if _, err := client.Check(as("ann"), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	var trailer metadata.MD
+	_, err := client.Check(as("ann"), &healthpb.HealthCheckRequest{}, grpc.Trailer(&trailer))
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("code = %v (%v), want ResourceExhausted", st.Code(), err)
+	}
+
+	var info *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			info = ri
+		}
+	}
+	if info == nil {
+		t.Fatalf("details = %v, want RetryInfo", st.Details())
+	}
+	//One token every two seconds.
+	delay := info.GetRetryDelay().AsDuration()
+	if delay <= 0 || delay > 2*time.Second {
+		t.Errorf("RetryDelay = %v, want in (0, 2s]", delay)
+	}
+
+	got := trailer.Get(RetryAfterKey)
+	if len(got) != 1 {
+		t.Fatalf("%s trailer = %v", RetryAfterKey, got)
+	}
+	secs, err := strconv.Atoi(got[0])
+	if err != nil || secs < 1 || secs > 2 || time.Duration(secs)*time.Second < delay {
+		t.Errorf("%s = %q, want whole seconds covering %v", RetryAfterKey, got[0], delay)
+	}
+}
+
+func TestMaxStreams(t *testing.T) {
+	l := New(Config{MaxStreams: 1})
+	client := startServer(t, l)
+
+	//open starts a Watch stream and waits for its first message, or the
+	//error that refused it.
+	open := func(user string) (context.CancelFunc, error) {
+		ctx, cancel := context.WithCancel(as(user))
+		stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if err != nil {
+			cancel()
+		}
+		return cancel, err
+	}
+
+	stop, err := open("ann")
+	if err != nil {
+		t.Fatalf("first stream: %v", err)
+	}
+	if _, err := open("ann"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second stream: %v, want ResourceExhausted", err)
+	}
+	otherStop, err := open("bob")
+	if err != nil {
+		t.Fatalf("another client's stream: %v", err)
+	}
+	defer otherStop()
+
+	//Closing the stream releases the slot once the handler returns.
+	stop()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		stop, err := open("ann")
+		if err == nil {
+			stop()
+			break
+		}
+		if status.Code(err) != codes.ResourceExhausted || time.Now().After(deadline) {
+			t.Fatalf("stream after release: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	tcp := &net.TCPAddr{IP: net.ParseIP("192.0.2.7"), Port: 4242}
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "principal", ctx: peer.NewContext(auth.NewContext(context.Background(), &auth.Principal{Subject: "ann"}), &peer.Peer{Addr: tcp}), want: "principal:ann"},
+		{name: "peer without port", ctx: peer.NewContext(context.Background(), &peer.Peer{Addr: tcp}), want: "peer:192.0.2.7"},
+		{name: "unknown", ctx: context.Background(), want: "unknown"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := clientKey(tc.ctx); got != tc.want {
+				t.Errorf("clientKey = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Package recovery turns handler panics into codes.Internal errors so one bad
+// request cannot bring down a server.
+package recovery
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/logging"
+	"github.com/jwfrizzell/grpc-go-course/tracing"
+)
+
+// newPanicCounter creates the panic counter against the meter provider
+// installed by tracing.Init, so the interceptors must be built after it runs.
+func newPanicCounter() metric.Int64Counter {
+	panics, err := tracing.Meter("github.com/jwfrizzell/grpc-go-course/recovery").Int64Counter(
+		"rpc.server.panics",
+		metric.WithDescription("Number of panics recovered in gRPC handlers."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		return nil
+	}
+	return panics
+}
+
+// handle logs the panic with its stack trace, counts it and builds the error
+// returned to the caller. The correlation ID is the request ID when one is set.
+func handle(ctx context.Context, logger *slog.Logger, panics metric.Int64Counter, method string, p interface{}) error {
+	id := logging.RequestID(ctx)
+	if id == "" {
+		id = logging.NewRequestID()
+	}
+	logging.FromContext(ctx, logger).Error("panic in handler",
+		"correlation_id", id,
+		"method", method,
+		"panic", p,
+		"stack", string(debug.Stack()),
+	)
+	if panics != nil {
+		panics.Add(ctx, 1, metric.WithAttributes(attribute.String("rpc.method", method)))
+	}
+	return status.Errorf(codes.Internal, "internal error (correlation id %s)", id)
+}
+
+// UnaryServerInterceptor recovers panics raised by unary handlers.
+func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	panics := newPanicCounter()
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				resp, err = nil, handle(ctx, logger, panics, info.FullMethod, p)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor recovers panics raised by streaming handlers.
+func StreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	panics := newPanicCounter()
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = handle(ss.Context(), logger, panics, info.FullMethod, p)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
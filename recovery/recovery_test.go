@@ -0,0 +1,148 @@
+package recovery
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/logging"
+)
+
+// useMeterReader installs a meter provider backed by a manual reader for
+// the rest of the test.
+func useMeterReader(t *testing.T) *sdkmetric.ManualReader {
+	t.Helper()
+	prev := otel.GetMeterProvider()
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(prev) })
+	return reader
+}
+
+// panicCounts returns rpc.server.panics by method.
+func panicCounts(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "rpc.server.panics" {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				t.Fatalf("rpc.server.panics is %T, want an int64 sum", m.Data)
+			}
+			for _, dp := range sum.DataPoints {
+				method, _ := dp.Attributes.Value(attribute.Key("rpc.method"))
+				counts[method.AsString()] += dp.Value
+			}
+		}
+	}
+	return counts
+}
+
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestRecover(t *testing.T) {
+	reader := useMeterReader(t)
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	unary := UnaryServerInterceptor(logger)
+	stream := StreamServerInterceptor(logger)
+
+	const unaryMethod = "/test.Service/Unary"
+	const streamMethod = "/test.Service/Stream"
+	callUnary := func(ctx context.Context, h grpc.UnaryHandler) error {
+		_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: unaryMethod}, h)
+		return err
+	}
+	callStream := func(ctx context.Context, h grpc.StreamHandler) error {
+		return stream(nil, &testStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: streamMethod}, h)
+	}
+	failed := status.Error(codes.NotFound, "missing")
+
+	tests := []struct {
+		name      string
+		requestID string
+		call      func(context.Context) error
+		want      codes.Code
+	}{
+		{name: "unary panic", requestID: "req-1", want: codes.Internal, call: func(ctx context.Context) error {
+			return callUnary(ctx, func(context.Context, interface{}) (interface{}, error) { panic("boom") })
+		}},
+		{name: "unary panic without request id", want: codes.Internal, call: func(ctx context.Context) error {
+			return callUnary(ctx, func(context.Context, interface{}) (interface{}, error) {
+				var m map[string]int
+				m["x"] = 1
+				return nil, nil
+			})
+		}},
+		{name: "unary error passes through", want: codes.NotFound, call: func(ctx context.Context) error {
+			return callUnary(ctx, func(context.Context, interface{}) (interface{}, error) { return nil, failed })
+		}},
+		{name: "unary success", want: codes.OK, call: func(ctx context.Context) error {
+			return callUnary(ctx, func(context.Context, interface{}) (interface{}, error) { return "ok", nil })
+		}},
+		{name: "stream panic", requestID: "req-2", want: codes.Internal, call: func(ctx context.Context) error {
+			return callStream(ctx, func(interface{}, grpc.ServerStream) error { panic("boom") })
+		}},
+		{name: "stream error passes through", want: codes.NotFound, call: func(ctx context.Context) error {
+			return callStream(ctx, func(interface{}, grpc.ServerStream) error { return failed })
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logs.Reset()
+			ctx := context.Background()
+			if tc.requestID != "" {
+				ctx = logging.WithRequestID(ctx, tc.requestID)
+			}
+			err := tc.call(ctx)
+			if got := status.Code(err); got != tc.want {
+				t.Fatalf("code = %v (%v), want %v", got, err, tc.want)
+			}
+			if tc.want != codes.Internal {
+				if logs.Len() != 0 {
+					t.Errorf("logged without a panic: %s", logs.String())
+				}
+				return
+			}
+			if !strings.Contains(logs.String(), `"msg":"panic in handler"`) || !strings.Contains(logs.String(), `"stack":`) {
+				t.Errorf("log = %s, want the panic with its stack", logs.String())
+			}
+			if tc.requestID != "" && !strings.Contains(status.Convert(err).Message(), tc.requestID) {
+				t.Errorf("message %q does not carry request id %s", status.Convert(err).Message(), tc.requestID)
+			}
+		})
+	}
+
+	want := map[string]int64{unaryMethod: 2, streamMethod: 1}
+	got := panicCounts(t, reader)
+	for method, n := range want {
+		if got[method] != n {
+			t.Errorf("rpc.server.panics{rpc.method=%s} = %d, want %d", method, got[method], n)
+		}
+	}
+}
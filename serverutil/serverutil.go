@@ -0,0 +1,336 @@
+// Package serverutil holds the setup shared by the course servers: the
+// common flags, logging, tracing, authentication, TLS, admission control,
+// rate limiting and serving either native gRPC or the web protocols. Each
+// server only registers its own service on the grpc.Server it gets back.
+package serverutil
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/jwfrizzell/grpc-go-course/admission"
+	"github.com/jwfrizzell/grpc-go-course/auth"
+	"github.com/jwfrizzell/grpc-go-course/logging"
+	"github.com/jwfrizzell/grpc-go-course/ratelimit"
+	"github.com/jwfrizzell/grpc-go-course/recovery"
+	"github.com/jwfrizzell/grpc-go-course/tlsconfig"
+	"github.com/jwfrizzell/grpc-go-course/tracing"
+	"github.com/jwfrizzell/grpc-go-course/web"
+)
+
+// Defaults holds the per-service defaults of the shared flags.
+type Defaults struct {
+	//ServiceName names the service in traces.
+	ServiceName string
+	//Addr is the default listen address.
+	Addr string
+	//TLS makes -tls default to true.
+	TLS bool
+	//RateLimits apply when -rate-limits is empty. Nil disables rate
+	//limiting unless a file is given.
+	RateLimits *ratelimit.Config
+}
+
+// Flags are the values of the shared flags once the flag set is parsed.
+type Flags struct {
+	TraceExporter string
+	OTLPEndpoint  string
+	LogLevel      string
+	LogJSON       bool
+
+	AuthMode    string
+	APIKeys     string
+	JWKS        string
+	JWTIssuer   string
+	JWTAudience string
+
+	TLS         bool
+	TLSCert     string
+	TLSKey      string
+	TLSClientCA string
+	TLSReload   time.Duration
+	DevTLS      bool
+	DevTLSDir   string
+
+	RateLimits  string
+	Web         bool
+	CORSOrigins string
+
+	Admission *admission.Config
+
+	defaults Defaults
+	fs       *flag.FlagSet
+}
+
+// RegisterFlags defines the shared flags on fs and returns the Flags they
+// fill in once fs is parsed.
+func RegisterFlags(fs *flag.FlagSet, d Defaults) *Flags {
+	f := &Flags{defaults: d, fs: fs}
+	fs.StringVar(&f.TraceExporter, "trace-exporter", tracing.ExporterNone, "trace exporter: none, stdout or otlp")
+	fs.StringVar(&f.OTLPEndpoint, "otlp-endpoint", tracing.DefaultOTLPEndpoint, "OTLP collector address")
+	fs.StringVar(&f.LogLevel, "log-level", "info", "log level: debug, info, warn or error")
+	fs.BoolVar(&f.LogJSON, "log-json", false, "write logs as JSON")
+	fs.StringVar(&f.AuthMode, "auth", auth.ModeNone, "caller authentication: none, apikey or jwt")
+	fs.StringVar(&f.APIKeys, "api-keys", "api_keys.json", "API key file used with -auth=apikey")
+	fs.StringVar(&f.JWKS, "jwks", "jwks.json", "JWKS file used with -auth=jwt")
+	fs.StringVar(&f.JWTIssuer, "jwt-issuer", "", "required JWT issuer, empty to skip the check")
+	fs.StringVar(&f.JWTAudience, "jwt-audience", "", "required JWT audience, empty to skip the check")
+	fs.BoolVar(&f.TLS, "tls", d.TLS, "serve over TLS")
+	fs.StringVar(&f.TLSCert, "tls-cert", "ssl/server.crt", "server certificate")
+	fs.StringVar(&f.TLSKey, "tls-key", "ssl/server.pem", "server private key")
+	fs.StringVar(&f.TLSClientCA, "tls-client-ca", "", "CA bundle for client certificates, enables mutual TLS")
+	fs.DurationVar(&f.TLSReload, "tls-reload", tlsconfig.DefaultReloadInterval, "how often to check certificate files for changes")
+	fs.BoolVar(&f.DevTLS, "dev-tls", false, "serve TLS with freshly generated throwaway certificates")
+	fs.StringVar(&f.DevTLSDir, "dev-tls-dir", "ssl/dev", "where -dev-tls writes its CA and client certificate")
+	rateUsage := "rate limit config file, empty disables rate limiting"
+	if d.RateLimits != nil {
+		rateUsage = "rate limit config file, empty for the built-in limits"
+	}
+	fs.StringVar(&f.RateLimits, "rate-limits", "", rateUsage)
+	fs.BoolVar(&f.Web, "web", false, "also serve gRPC-Web and Connect clients on the gRPC port")
+	fs.StringVar(&f.CORSOrigins, "cors-origins", "", "comma separated origins allowed to call from a browser, * for any")
+	f.Admission = admission.RegisterFlags(fs)
+	return f
+}
+
+// Interceptors are extra interceptors a service adds to the shared chain.
+// They run after authentication and before rate limiting, which is where
+// authorization belongs.
+type Interceptors struct {
+	Unary  []grpc.UnaryServerInterceptor
+	Stream []grpc.StreamServerInterceptor
+}
+
+// Server is the shared state built from Flags.
+type Server struct {
+	Logger *slog.Logger
+	//Authn is nil when authentication is disabled.
+	Authn auth.Authenticator
+	//TLS is the server TLS config, nil when serving plaintext.
+	TLS *tls.Config
+
+	flags   *Flags
+	tls     *tlsconfig.Server
+	shedder *admission.Shedder
+	limiter *ratelimit.Limiter
+	tracing func(context.Context) error
+	stopTLS context.CancelFunc
+	lis     net.Listener
+	grpc    *grpc.Server
+	web     *http.Server
+}
+
+// Setup builds the logger, tracing, authentication, TLS and admission
+// control described by f and listens on the default address. Close
+// releases what Setup started.
+func Setup(f *Flags) (*Server, error) {
+	logger, err := logging.New(os.Stderr, logging.Config{Level: f.LogLevel, JSON: f.LogJSON})
+	if err != nil {
+		return nil, fmt.Errorf("serverutil: logger: %v", err)
+	}
+	s := &Server{Logger: logger, flags: f, stopTLS: func() {}}
+	s.tracing, err = tracing.Init(context.Background(), tracing.Config{
+		ServiceName: f.defaults.ServiceName,
+		Exporter:    f.TraceExporter,
+		Endpoint:    f.OTLPEndpoint,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("serverutil: %v", err)
+	}
+	if err := s.setup(); err != nil {
+		s.Close(context.Background())
+		return nil, err
+	}
+	return s, nil
+}
+
+// webUnsupported are the admission flags net/http has no equivalent for.
+// The client ping policy is enforced by grpc's own transport only.
+var webUnsupported = []string{"keepalive-min-time", "keepalive-permit-without-stream"}
+
+func (s *Server) setup() error {
+	f := s.flags
+	var err error
+	if f.Web {
+		var unsupported error
+		f.fs.Visit(func(fl *flag.Flag) {
+			if slices.Contains(webUnsupported, fl.Name) {
+				unsupported = fmt.Errorf("serverutil: -%s cannot be enforced together with -web", fl.Name)
+			}
+		})
+		if unsupported != nil {
+			return unsupported
+		}
+	}
+	s.Authn, err = auth.New(auth.Config{
+		Mode:        f.AuthMode,
+		APIKeysFile: f.APIKeys,
+		JWKSFile:    f.JWKS,
+		Issuer:      f.JWTIssuer,
+		Audience:    f.JWTAudience,
+	})
+	if err != nil {
+		return fmt.Errorf("serverutil: %v", err)
+	}
+
+	limits := f.defaults.RateLimits
+	if f.RateLimits != "" {
+		if limits, err = ratelimit.LoadConfig(f.RateLimits); err != nil {
+			return fmt.Errorf("serverutil: %v", err)
+		}
+	}
+	if limits != nil {
+		s.limiter = ratelimit.New(*limits)
+	}
+	s.shedder = admission.NewShedder(f.Admission.MaxInFlight)
+
+	if f.DevTLS {
+		files, err := tlsconfig.GenerateDev(f.DevTLSDir, []string{"localhost", "127.0.0.1", "::1"})
+		if err != nil {
+			return fmt.Errorf("serverutil: dev TLS: %v", err)
+		}
+		s.Logger.Info("Generated dev TLS certificates", "ca", files.CACert, "client_cert", files.ClientCert, "client_key", files.ClientKey)
+		f.TLS, f.TLSCert, f.TLSKey = true, files.ServerCert, files.ServerKey
+	}
+	if f.TLS {
+		s.tls, err = tlsconfig.NewServer(tlsconfig.ServerConfig{
+			CertFile:       f.TLSCert,
+			KeyFile:        f.TLSKey,
+			ClientCAFile:   f.TLSClientCA,
+			ReloadInterval: f.TLSReload,
+			OnReload: func(err error) {
+				if err != nil {
+					s.Logger.Warn("TLS Reload Failure", "error", err)
+					return
+				}
+				s.Logger.Info("TLS Certificates Reloaded")
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("serverutil: %v", err)
+		}
+		var ctx context.Context
+		ctx, s.stopTLS = context.WithCancel(context.Background())
+		go s.tls.Watch(ctx)
+		s.TLS = s.tls.TLSConfig()
+	}
+
+	if s.lis, err = net.Listen("tcp", f.defaults.Addr); err != nil {
+		return fmt.Errorf("serverutil: %v", err)
+	}
+	return nil
+}
+
+// ServerOptions returns the tracing, interceptor and admission options of
+// every server, without transport credentials. extra joins the interceptor
+// chain after authentication.
+func (s *Server) ServerOptions(extra Interceptors) []grpc.ServerOption {
+	unary := []grpc.UnaryServerInterceptor{
+		logging.UnaryServerInterceptor(s.Logger),
+		recovery.UnaryServerInterceptor(s.Logger),
+		s.shedder.UnaryServerInterceptor(),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		logging.StreamServerInterceptor(s.Logger),
+		recovery.StreamServerInterceptor(s.Logger),
+		s.shedder.StreamServerInterceptor(),
+	}
+	if s.Authn != nil {
+		unary = append(unary, auth.UnaryServerInterceptor(s.Authn))
+		stream = append(stream, auth.StreamServerInterceptor(s.Authn))
+	}
+	unary = append(unary, extra.Unary...)
+	stream = append(stream, extra.Stream...)
+	if s.limiter != nil {
+		unary = append(unary, s.limiter.UnaryServerInterceptor())
+		stream = append(stream, s.limiter.StreamServerInterceptor())
+	}
+
+	opts := []grpc.ServerOption{
+		tracing.ServerOption(),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	return append(opts, s.flags.Admission.ServerOptions()...)
+}
+
+// NewGRPCServer returns the grpc.Server to register services on, with
+// reflection enabled. Serve serves it.
+func (s *Server) NewGRPCServer(extra Interceptors) *grpc.Server {
+	opts := s.ServerOptions(extra)
+	if s.tls != nil {
+		opts = append(opts, grpc.Creds(s.tls.Credentials()))
+	}
+	s.grpc = grpc.NewServer(opts...)
+	reflection.Register(s.grpc)
+	if s.flags.Web {
+		admit := s.flags.Admission
+		s.web = web.NewServer(s.grpc, web.Config{
+			AllowedOrigins:        web.ParseOrigins(s.flags.CORSOrigins),
+			MaxConcurrentStreams:  admit.MaxConcurrentStreams,
+			MaxConnectionIdle:     admit.MaxConnectionIdle,
+			MaxConnectionAge:      admit.MaxConnectionAge,
+			MaxConnectionAgeGrace: admit.MaxConnectionAgeGrace,
+			KeepaliveTime:         admit.KeepaliveTime,
+			KeepaliveTimeout:      admit.KeepaliveTimeout,
+		}, s.TLS)
+	}
+	return s.grpc
+}
+
+// Addr returns the address Serve listens on.
+func (s *Server) Addr() net.Addr {
+	return s.lis.Addr()
+}
+
+// Serve serves the server from NewGRPCServer until Stop is called, after
+// which it returns nil. With -web it also serves gRPC-Web and Connect.
+func (s *Server) Serve() error {
+	if s.grpc == nil {
+		return errors.New("serverutil: Serve called before NewGRPCServer")
+	}
+	if s.web == nil {
+		return s.grpc.Serve(s.lis)
+	}
+	var err error
+	if s.TLS != nil {
+		err = s.web.ServeTLS(s.lis, "", "")
+	} else {
+		err = s.web.Serve(s.lis)
+	}
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Stop closes the listener and every connection.
+func (s *Server) Stop() {
+	if s.web != nil {
+		s.web.Close()
+	}
+	if s.grpc != nil {
+		s.grpc.Stop()
+	}
+	if s.lis != nil {
+		s.lis.Close()
+	}
+}
+
+// Close stops watching certificates and flushes telemetry.
+func (s *Server) Close(ctx context.Context) error {
+	s.stopTLS()
+	return s.tracing(ctx)
+}
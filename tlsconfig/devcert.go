@@ -0,0 +1,192 @@
+package tlsconfig
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CertKey is a certificate together with its private key.
+type CertKey struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+	der  []byte
+}
+
+// CertPEM returns the PEM encoded certificate.
+func (ck *CertKey) CertPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ck.der})
+}
+
+// KeyPEM returns the PEM encoded PKCS#8 private key.
+func (ck *CertKey) KeyPEM() ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(ck.Key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// WriteFiles writes the certificate to certFile and, if keyFile is not
+// empty, the private key to keyFile with owner-only permissions.
+func (ck *CertKey) WriteFiles(certFile, keyFile string) error {
+	if err := os.WriteFile(certFile, ck.CertPEM(), 0644); err != nil {
+		return err
+	}
+	if keyFile == "" {
+		return nil
+	}
+	key, err := ck.KeyPEM()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(keyFile, key, 0600)
+}
+
+// IssueOptions describes a leaf certificate.
+type IssueOptions struct {
+	CommonName string
+	//Hosts are DNS names or IP addresses placed in the SAN extension.
+	Hosts    []string
+	Server   bool
+	Client   bool
+	Validity time.Duration
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func newKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+func create(tmpl, parent *x509.Certificate, pub crypto.PublicKey, signer, key crypto.Signer) (*CertKey, error) {
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, signer)
+	if err != nil {
+		return nil, fmt.Errorf("tlsconfig: creating %s certificate: %v", tmpl.Subject.CommonName, err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CertKey{Cert: cert, Key: key, der: der}, nil
+}
+
+// GenerateCA creates a self-signed development CA.
+func GenerateCA(commonName string, validity time.Duration) (*CertKey, error) {
+	key, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"grpc-go-course dev"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		MaxPathLenZero:        true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	return create(tmpl, tmpl, &key.PublicKey, key, key)
+}
+
+// Issue signs a new leaf certificate with ca.
+func (ca *CertKey) Issue(opts IssueOptions) (*CertKey, error) {
+	key, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: opts.CommonName},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(opts.Validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	if opts.Server {
+		tmpl.ExtKeyUsage = append(tmpl.ExtKeyUsage, x509.ExtKeyUsageServerAuth)
+	}
+	if opts.Client {
+		tmpl.ExtKeyUsage = append(tmpl.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
+	}
+	for _, h := range opts.Hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+	return create(tmpl, ca.Cert, &key.PublicKey, ca.Key, key)
+}
+
+// DevFiles names the files written by GenerateDev.
+type DevFiles struct {
+	CACert     string
+	ServerCert string
+	ServerKey  string
+	ClientCert string
+	ClientKey  string
+}
+
+// devValidity keeps ephemeral certificates short lived.
+const devValidity = 24 * time.Hour
+
+// GenerateDev creates a throwaway CA, a server certificate for hosts and a
+// client certificate, and writes them to dir. Servers run in dev TLS mode
+// call it on every start; clients point -tls-ca at DevFiles.CACert.
+func GenerateDev(dir string, hosts []string) (*DevFiles, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	ca, err := GenerateCA("grpc-go-course dev CA", devValidity)
+	if err != nil {
+		return nil, err
+	}
+	srv, err := ca.Issue(IssueOptions{CommonName: hosts[0], Hosts: hosts, Server: true, Validity: devValidity})
+	if err != nil {
+		return nil, err
+	}
+	cli, err := ca.Issue(IssueOptions{CommonName: "dev-client", Client: true, Validity: devValidity})
+	if err != nil {
+		return nil, err
+	}
+	f := &DevFiles{
+		CACert:     filepath.Join(dir, "ca.crt"),
+		ServerCert: filepath.Join(dir, "server.crt"),
+		ServerKey:  filepath.Join(dir, "server.pem"),
+		ClientCert: filepath.Join(dir, "client.crt"),
+		ClientKey:  filepath.Join(dir, "client.pem"),
+	}
+	if err := ca.WriteFiles(f.CACert, ""); err != nil {
+		return nil, err
+	}
+	if err := srv.WriteFiles(f.ServerCert, f.ServerKey); err != nil {
+		return nil, err
+	}
+	if err := cli.WriteFiles(f.ClientCert, f.ClientKey); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
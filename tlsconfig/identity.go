@@ -0,0 +1,39 @@
+package tlsconfig
+
+import (
+	"context"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Identity describes the verified client certificate of an mTLS caller.
+type Identity struct {
+	CommonName string
+	DNSNames   []string
+	URIs       []string
+	Serial     string
+}
+
+// ClientIdentity returns the identity of the caller's verified client
+// certificate. It returns false on plaintext or server-only TLS connections.
+func ClientIdentity(ctx context.Context) (*Identity, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	leaf := info.State.VerifiedChains[0][0]
+	id := &Identity{
+		CommonName: leaf.Subject.CommonName,
+		DNSNames:   leaf.DNSNames,
+		Serial:     leaf.SerialNumber.String(),
+	}
+	for _, u := range leaf.URIs {
+		id.URIs = append(id.URIs, u.String())
+	}
+	return id, true
+}
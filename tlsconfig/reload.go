@@ -0,0 +1,176 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultReloadInterval is how often certificate files are checked for changes.
+const DefaultReloadInterval = 10 * time.Second
+
+// fileStamp identifies one version of a file on disk.
+type fileStamp struct {
+	mod  time.Time
+	size int64
+}
+
+func stamp(path string) (fileStamp, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{mod: fi.ModTime(), size: fi.Size()}, nil
+}
+
+// KeyPair holds a certificate and key loaded from disk and swaps them when
+// either file changes. It is safe for concurrent use by TLS handshakes.
+type KeyPair struct {
+	certFile, keyFile string
+
+	mu     sync.RWMutex
+	cert   *tls.Certificate
+	stamps [2]fileStamp
+}
+
+// NewKeyPair loads certFile and keyFile.
+func NewKeyPair(certFile, keyFile string) (*KeyPair, error) {
+	kp := &KeyPair{certFile: certFile, keyFile: keyFile}
+	if _, err := kp.Reload(); err != nil {
+		return nil, err
+	}
+	return kp, nil
+}
+
+// Reload reads the files again if they changed since the last load. It
+// reports whether a new certificate was installed. On error the previous
+// certificate stays in use.
+func (kp *KeyPair) Reload() (bool, error) {
+	cs, err := stamp(kp.certFile)
+	if err != nil {
+		return false, fmt.Errorf("tlsconfig: %v", err)
+	}
+	ks, err := stamp(kp.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("tlsconfig: %v", err)
+	}
+	kp.mu.RLock()
+	unchanged := kp.cert != nil && kp.stamps == [2]fileStamp{cs, ks}
+	kp.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(kp.certFile, kp.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("tlsconfig: loading %s: %v", kp.certFile, err)
+	}
+	kp.mu.Lock()
+	kp.cert = &cert
+	kp.stamps = [2]fileStamp{cs, ks}
+	kp.mu.Unlock()
+	return true, nil
+}
+
+// GetCertificate is a tls.Config.GetCertificate callback.
+func (kp *KeyPair) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	kp.mu.RLock()
+	defer kp.mu.RUnlock()
+	return kp.cert, nil
+}
+
+// GetClientCertificate is a tls.Config.GetClientCertificate callback.
+func (kp *KeyPair) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	kp.mu.RLock()
+	defer kp.mu.RUnlock()
+	return kp.cert, nil
+}
+
+// CAPool holds a certificate pool loaded from a PEM bundle and swaps it when
+// the file changes.
+type CAPool struct {
+	file string
+
+	mu    sync.RWMutex
+	pool  *x509.CertPool
+	stamp fileStamp
+}
+
+// NewCAPool loads the PEM bundle in file.
+func NewCAPool(file string) (*CAPool, error) {
+	p := &CAPool{file: file}
+	if _, err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload reads the bundle again if it changed since the last load.
+func (p *CAPool) Reload() (bool, error) {
+	s, err := stamp(p.file)
+	if err != nil {
+		return false, fmt.Errorf("tlsconfig: %v", err)
+	}
+	p.mu.RLock()
+	unchanged := p.pool != nil && p.stamp == s
+	p.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	b, err := os.ReadFile(p.file)
+	if err != nil {
+		return false, fmt.Errorf("tlsconfig: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return false, fmt.Errorf("tlsconfig: no certificates found in %s", p.file)
+	}
+	p.mu.Lock()
+	p.pool = pool
+	p.stamp = s
+	p.mu.Unlock()
+	return true, nil
+}
+
+// Pool returns the current certificate pool.
+func (p *CAPool) Pool() *x509.CertPool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.pool
+}
+
+// reloader is implemented by KeyPair and CAPool.
+type reloader interface {
+	Reload() (bool, error)
+}
+
+// watch polls every r until ctx is done. onReload, if set, is told about
+// each reload attempt that changed something or failed.
+func watch(ctx context.Context, interval time.Duration, onReload func(error), rs ...reloader) {
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		for _, r := range rs {
+			if r == nil {
+				continue
+			}
+			changed, err := r.Reload()
+			if onReload != nil && (changed || err != nil) {
+				onReload(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Package tlsconfig builds gRPC transport credentials for the course servers
+// and clients. It supports server TLS, mutual TLS against a client CA, and
+// picks up renewed certificates and CA bundles from disk without a restart.
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// ServerConfig describes the server side of a TLS connection.
+type ServerConfig struct {
+	CertFile string
+	KeyFile  string
+	//ClientCAFile enables mutual TLS. Clients must present a certificate
+	//signed by one of these CAs.
+	ClientCAFile string
+	//ReloadInterval is how often the files are checked for changes.
+	ReloadInterval time.Duration
+	//OnReload is called after every reload that changed a file or failed.
+	OnReload func(error)
+}
+
+// Server holds the reloadable material behind a server's credentials.
+type Server struct {
+	cfg      ServerConfig
+	keyPair  *KeyPair
+	clientCA *CAPool
+}
+
+// NewServer loads the files named in cfg.
+func NewServer(cfg ServerConfig) (*Server, error) {
+	kp, err := NewKeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{cfg: cfg, keyPair: kp}
+	if cfg.ClientCAFile != "" {
+		if s.clientCA, err = NewCAPool(cfg.ClientCAFile); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// TLSConfig returns a tls.Config that always serves the latest material.
+func (s *Server) TLSConfig() *tls.Config {
+	base := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		NextProtos:     []string{"h2"},
+		GetCertificate: s.keyPair.GetCertificate,
+	}
+	if s.clientCA == nil {
+		return base
+	}
+	//Resolve the CA pool per handshake so a rotated bundle is used at once.
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := base.Clone()
+		c.GetConfigForClient = nil
+		c.ClientAuth = tls.RequireAndVerifyClientCert
+		c.ClientCAs = s.clientCA.Pool()
+		return c, nil
+	}
+	return base
+}
+
+// Credentials returns gRPC transport credentials for s.
+func (s *Server) Credentials() credentials.TransportCredentials {
+	return credentials.NewTLS(s.TLSConfig())
+}
+
+// Watch reloads changed files until ctx is done.
+func (s *Server) Watch(ctx context.Context) {
+	rs := []reloader{s.keyPair}
+	if s.clientCA != nil {
+		rs = append(rs, s.clientCA)
+	}
+	watch(ctx, s.cfg.ReloadInterval, s.cfg.OnReload, rs...)
+}
+
+// ClientConfig describes the client side of a TLS connection.
+type ClientConfig struct {
+	//CAFile verifies the server. Empty uses the system roots.
+	CAFile string
+	//CertFile and KeyFile are presented to servers that require mTLS.
+	CertFile string
+	KeyFile  string
+	//ServerName overrides the name checked against the server certificate.
+	ServerName     string
+	ReloadInterval time.Duration
+	OnReload       func(error)
+}
+
+// Client holds the reloadable material behind a client's credentials.
+type Client struct {
+	cfg     ClientConfig
+	ca      *CAPool
+	keyPair *KeyPair
+}
+
+// NewClient loads the files named in cfg.
+func NewClient(cfg ClientConfig) (*Client, error) {
+	c := &Client{cfg: cfg}
+	var err error
+	if cfg.CAFile != "" {
+		if c.ca, err = NewCAPool(cfg.CAFile); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.CertFile != "" {
+		if c.keyPair, err = NewKeyPair(cfg.CertFile, cfg.KeyFile); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// TLSConfig returns a tls.Config for dialing. The CA pool and client
+// certificate are resolved on every handshake. With a CA file the server is
+// checked against ServerName, or the name the caller sets on the config.
+func (c *Client) TLSConfig() *tls.Config {
+	return c.tlsConfig(c.cfg.ServerName)
+}
+
+// tlsConfig is TLSConfig verifying the server against name.
+func (c *Client) tlsConfig(name string) *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: name,
+	}
+	if c.ca != nil {
+		//crypto/tls fixes RootCAs for the life of the config, so the chain
+		//is verified here against the live pool instead.
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyConnection = c.verifyServer(name)
+	}
+	if c.keyPair != nil {
+		cfg.GetClientCertificate = c.keyPair.GetClientCertificate
+	}
+	return cfg
+}
+
+// verifyServer checks the server chain against the current CA pool and its
+// name, DNS or IP, against name. An empty name falls back to the SNI name,
+// which crypto/tls leaves empty for IP addresses; with neither the
+// handshake fails rather than accept any certificate the CA signed.
+func (c *Client) verifyServer(name string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("tlsconfig: server sent no certificate")
+		}
+		host := name
+		if host == "" {
+			host = cs.ServerName
+		}
+		if host == "" {
+			return errors.New("tlsconfig: no server name to verify the certificate against")
+		}
+		opts := x509.VerifyOptions{
+			Roots:         c.ca.Pool(),
+			DNSName:       host,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := cs.PeerCertificates[0].Verify(opts)
+		return err
+	}
+}
+
+// Credentials returns gRPC transport credentials for c. Without a
+// ServerName the server is checked against the host being dialed.
+func (c *Client) Credentials() credentials.TransportCredentials {
+	return &clientCredentials{TransportCredentials: credentials.NewTLS(c.TLSConfig()), c: c}
+}
+
+// clientCredentials builds the tls.Config per handshake so verifyServer
+// knows the dialed host, including IP addresses that are never sent as SNI.
+type clientCredentials struct {
+	credentials.TransportCredentials
+	c *Client
+}
+
+func (cc *clientCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	name := cc.c.cfg.ServerName
+	if name == "" {
+		//Same as grpc: the authority is host:port, or just a host.
+		host, _, err := net.SplitHostPort(authority)
+		if err != nil {
+			host = authority
+		}
+		name = host
+	}
+	return credentials.NewTLS(cc.c.tlsConfig(name)).ClientHandshake(ctx, authority, conn)
+}
+
+func (cc *clientCredentials) Clone() credentials.TransportCredentials {
+	return &clientCredentials{TransportCredentials: cc.TransportCredentials.Clone(), c: cc.c}
+}
+
+// Watch reloads changed files until ctx is done.
+func (c *Client) Watch(ctx context.Context) {
+	var rs []reloader
+	if c.ca != nil {
+		rs = append(rs, c.ca)
+	}
+	if c.keyPair != nil {
+		rs = append(rs, c.keyPair)
+	}
+	watch(ctx, c.cfg.ReloadInterval, c.cfg.OnReload, rs...)
+}
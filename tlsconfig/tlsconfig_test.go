@@ -0,0 +1,328 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// testPKI is a CA with files in a temporary directory.
+type testPKI struct {
+	dir    string
+	ca     *CertKey
+	caFile string
+}
+
+func newPKI(t *testing.T) *testPKI {
+	t.Helper()
+	ca, err := GenerateCA("test CA", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testPKI{dir: t.TempDir(), ca: ca}
+	p.caFile = filepath.Join(p.dir, "ca.crt")
+	if err := ca.WriteFiles(p.caFile, ""); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// writeServer issues a server certificate for hosts and writes it to
+// server.crt and server.pem in dir, bumping the modification time so a
+// reload always sees the change.
+func (p *testPKI) writeServer(t *testing.T, dir string, hosts ...string) (certFile, keyFile string, leaf *CertKey) {
+	t.Helper()
+	leaf, err := p.ca.Issue(IssueOptions{CommonName: hosts[0], Hosts: hosts, Server: true, Validity: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile = filepath.Join(dir, "server.crt")
+	keyFile = filepath.Join(dir, "server.pem")
+	if err := leaf.WriteFiles(certFile, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	touch(t, certFile, keyFile)
+	return certFile, keyFile, leaf
+}
+
+var touched = time.Now()
+
+// touch moves the modification time of files forward.
+func touch(t *testing.T, files ...string) {
+	t.Helper()
+	touched = touched.Add(time.Second)
+	for _, f := range files {
+		if err := os.Chtimes(f, touched, touched); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// serve runs a health server with s's credentials and returns its port.
+func serve(t *testing.T, s *Server) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := grpc.NewServer(grpc.Creds(s.Credentials()))
+	healthpb.RegisterHealthServer(gs, health.NewServer())
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+	_, port, _ := net.SplitHostPort(lis.Addr().String())
+	return port
+}
+
+// check makes a health check against target with creds.
+func check(t *testing.T, target string, creds credentials.TransportCredentials) error {
+	t.Helper()
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestClientVerifiesServerName(t *testing.T) {
+	p := newPKI(t)
+	certFile, keyFile, _ := p.writeServer(t, p.dir, "localhost", "127.0.0.1")
+	srv, err := NewServer(ServerConfig{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := serve(t, srv)
+
+	//A second server whose certificate only names localhost.
+	dnsDir := t.TempDir()
+	dnsCert, dnsKey, _ := p.writeServer(t, dnsDir, "localhost")
+	dnsSrv, err := NewServer(ServerConfig{CertFile: dnsCert, KeyFile: dnsKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dnsPort := serve(t, dnsSrv)
+
+	other := newPKI(t)
+
+	tests := []struct {
+		name       string
+		target     string
+		caFile     string
+		serverName string
+		wantErr    bool
+	}{
+		{name: "good host", target: "localhost:" + port},
+		{name: "IP target", target: "127.0.0.1:" + port},
+		{name: "server name override", target: "127.0.0.1:" + dnsPort, serverName: "localhost"},
+		{name: "wrong server name", target: "localhost:" + port, serverName: "other.example", wantErr: true},
+		{name: "IP not in certificate", target: "127.0.0.1:" + dnsPort, wantErr: true},
+		{name: "other CA", target: "localhost:" + port, caFile: other.caFile, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			caFile := tc.caFile
+			if caFile == "" {
+				caFile = p.caFile
+			}
+			c, err := NewClient(ClientConfig{CAFile: caFile, ServerName: tc.serverName})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = check(t, tc.target, c.Credentials())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("health check error = %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+
+	t.Run("TLSConfig", func(t *testing.T) {
+		c, err := NewClient(ClientConfig{CAFile: p.caFile})
+		if err != nil {
+			t.Fatal(err)
+		}
+		dial := func(addr string, cfg *tls.Config) error {
+			cfg.NextProtos = []string{"h2"}
+			conn, err := tls.Dial("tcp", addr, cfg)
+			if err == nil {
+				conn.Close()
+			}
+			return err
+		}
+		if err := dial("localhost:"+dnsPort, c.TLSConfig()); err != nil {
+			t.Errorf("dialing localhost: %v", err)
+		}
+		//An IP address is not sent as SNI, so there is no name to check.
+		if err := dial("127.0.0.1:"+port, c.TLSConfig()); err == nil {
+			t.Error("dialing an IP without ServerName succeeded")
+		}
+		named, err := NewClient(ClientConfig{CAFile: p.caFile, ServerName: "127.0.0.1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := dial("127.0.0.1:"+port, named.TLSConfig()); err != nil {
+			t.Errorf("dialing an IP with ServerName: %v", err)
+		}
+		if err := dial("127.0.0.1:"+dnsPort, named.TLSConfig()); err == nil {
+			t.Error("dialing an IP missing from the certificate succeeded")
+		}
+	})
+}
+
+func TestKeyPairReload(t *testing.T) {
+	p := newPKI(t)
+	certFile, keyFile, first := p.writeServer(t, p.dir, "one.example")
+	kp, err := NewKeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial := func() string {
+		cert, _ := kp.GetCertificate(nil)
+		return cert.Leaf.SerialNumber.String()
+	}
+	if got := serial(); got != first.Cert.SerialNumber.String() {
+		t.Fatalf("loaded serial %s, want %s", got, first.Cert.SerialNumber)
+	}
+
+	if changed, err := kp.Reload(); changed || err != nil {
+		t.Fatalf("Reload of unchanged files = %v, %v", changed, err)
+	}
+
+	_, _, second := p.writeServer(t, p.dir, "two.example")
+	if changed, err := kp.Reload(); !changed || err != nil {
+		t.Fatalf("Reload after rotation = %v, %v", changed, err)
+	}
+	if got := serial(); got != second.Cert.SerialNumber.String() {
+		t.Fatalf("serial after rotation %s, want %s", got, second.Cert.SerialNumber)
+	}
+
+	//A broken file keeps the last good certificate.
+	if err := os.WriteFile(certFile, []byte("not a certificate"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	touch(t, certFile)
+	if _, err := kp.Reload(); err == nil {
+		t.Fatal("Reload of a broken certificate succeeded")
+	}
+	if got := serial(); got != second.Cert.SerialNumber.String() {
+		t.Errorf("serial after failed reload %s, want %s", got, second.Cert.SerialNumber)
+	}
+}
+
+func TestCAPoolReload(t *testing.T) {
+	p := newPKI(t)
+	pool, err := NewCAPool(p.caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := pool.Reload(); changed || err != nil {
+		t.Fatalf("Reload of unchanged bundle = %v, %v", changed, err)
+	}
+	before := pool.Pool()
+
+	next, err := GenerateCA("next CA", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := next.WriteFiles(p.caFile, ""); err != nil {
+		t.Fatal(err)
+	}
+	touch(t, p.caFile)
+	if changed, err := pool.Reload(); !changed || err != nil {
+		t.Fatalf("Reload after rotation = %v, %v", changed, err)
+	}
+	if pool.Pool() == before || pool.Pool().Equal(before) {
+		t.Error("pool unchanged after rotation")
+	}
+
+	if err := os.WriteFile(p.caFile, []byte("no PEM here"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	touch(t, p.caFile)
+	current := pool.Pool()
+	if _, err := pool.Reload(); err == nil {
+		t.Fatal("Reload of a bundle without certificates succeeded")
+	}
+	if pool.Pool() != current {
+		t.Error("failed reload replaced the pool")
+	}
+}
+
+// TestWatchRotation rotates the CA and the server certificate on disk and
+// checks that new handshakes use them without a restart.
+func TestWatchRotation(t *testing.T) {
+	p := newPKI(t)
+	certFile, keyFile, _ := p.writeServer(t, p.dir, "localhost")
+
+	var reloads atomic.Int32
+	//A reload may catch the certificate and key half written; the next
+	//tick fixes that, so only count the calls.
+	onReload := func(error) { reloads.Add(1) }
+	srv, err := NewServer(ServerConfig{CertFile: certFile, KeyFile: keyFile, ReloadInterval: 10 * time.Millisecond, OnReload: onReload})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, err := NewClient(ClientConfig{CAFile: p.caFile, ReloadInterval: 10 * time.Millisecond, OnReload: onReload})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Watch(ctx)
+	go cli.Watch(ctx)
+	target := "localhost:" + serve(t, srv)
+
+	if err := check(t, target, cli.Credentials()); err != nil {
+		t.Fatalf("before rotation: %v", err)
+	}
+
+	//Rotate to a new CA. The old client trust and the new server
+	//certificate only work together once both sides have reloaded.
+	next := newPKI(t)
+	_, _, leaf := next.writeServer(t, p.dir, "localhost")
+	if err := next.ca.WriteFiles(p.caFile, ""); err != nil {
+		t.Fatal(err)
+	}
+	touch(t, p.caFile)
+
+	dial := func() error {
+		cfg := cli.TLSConfig()
+		cfg.NextProtos = []string{"h2"}
+		conn, err := tls.Dial("tcp", target, cfg)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if got := conn.ConnectionState().PeerCertificates[0].SerialNumber; got.Cmp(leaf.Cert.SerialNumber) != 0 {
+			return fmt.Errorf("server presented serial %s, want the rotated %s", got, leaf.Cert.SerialNumber)
+		}
+		return nil
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for err := dial(); err != nil; err = dial() {
+		if time.Now().After(deadline) {
+			t.Fatalf("after rotation: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if reloads.Load() < 2 {
+		t.Errorf("OnReload called %d times, want at least 2", reloads.Load())
+	}
+	if err := check(t, target, cli.Credentials()); err != nil {
+		t.Errorf("health check after rotation: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+// Package tracing wires OpenTelemetry into the course servers and clients.
+// Traces and metrics can be shipped to a local collector over OTLP or printed
+// to stdout.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// Supported exporters.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// DefaultOTLPEndpoint is where a local collector listens for OTLP over gRPC.
+const DefaultOTLPEndpoint = "localhost:4317"
+
+// MetricInterval is how often metrics are pushed to the exporter.
+const MetricInterval = 30 * time.Second
+
+// Config selects the exporter used for a process.
+type Config struct {
+	ServiceName string
+	Exporter    string //none, stdout or otlp
+	Endpoint    string //OTLP collector address, defaults to DefaultOTLPEndpoint
+}
+
+// Init installs a global tracer provider, meter provider and W3C propagator.
+// The returned function flushes and stops both providers.
+func Init(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	var exp sdktrace.SpanExporter
+	var mexp sdkmetric.Exporter
+	var err error
+
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exp, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+		if err == nil {
+			mexp, err = stdoutmetric.New(stdoutmetric.WithWriter(os.Stdout), stdoutmetric.WithPrettyPrint())
+		}
+	case ExporterOTLP:
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = DefaultOTLPEndpoint
+		}
+		exp, err = otlptracegrpc.New(ctx,
+			otlptracegrpc.WithEndpoint(endpoint),
+			otlptracegrpc.WithInsecure(),
+		)
+		if err == nil {
+			mexp, err = otlpmetricgrpc.New(ctx,
+				otlpmetricgrpc.WithEndpoint(endpoint),
+				otlpmetricgrpc.WithInsecure(),
+			)
+		}
+	default:
+		return nil, fmt.Errorf("tracing: unknown exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tracing: creating %s exporter: %v", cfg.Exporter, err)
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName))
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+	)
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(mexp, sdkmetric.WithInterval(MetricInterval))),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetMeterProvider(mp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	return func(ctx context.Context) error {
+		return errors.Join(tp.Shutdown(ctx), mp.Shutdown(ctx))
+	}, nil
+}
+
+// ServerOption instruments every RPC handled by a grpc.Server.
+func ServerOption() grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler())
+}
+
+// DialOption instruments every RPC made on a client connection.
+func DialOption() grpc.DialOption {
+	return grpc.WithStatsHandler(otelgrpc.NewClientHandler())
+}
+
+// Tracer returns a named tracer from the global provider.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// Meter returns a named meter from the global provider. Instruments should be
+// created from it after Init has run.
+func Meter(name string) metric.Meter {
+	return otel.GetMeterProvider().Meter(name)
+}
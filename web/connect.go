@@ -0,0 +1,321 @@
+package web
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// connectContentType returns the codec named by a Connect content type, or
+// "" when ct is not a Connect type.
+func connectContentType(ct string) string {
+	ct, _, _ = strings.Cut(ct, ";")
+	switch strings.TrimSpace(ct) {
+	case "application/proto", "application/connect+proto":
+		return "proto"
+	case "application/json", "application/connect+json":
+		return "json"
+	}
+	return ""
+}
+
+// codec converts Connect messages to and from the protobuf wire format the
+// gRPC server speaks.
+type codec interface {
+	request([]byte) ([]byte, error)
+	response([]byte) ([]byte, error)
+}
+
+type protoCodec struct{}
+
+func (protoCodec) request(b []byte) ([]byte, error)  { return b, nil }
+func (protoCodec) response(b []byte) ([]byte, error) { return b, nil }
+
+// jsonCodec transcodes using the message types registered for a method.
+type jsonCodec struct {
+	in, out protoreflect.MessageType
+}
+
+func newJSONCodec(path string) (*jsonCodec, error) {
+	name := protoreflect.FullName(strings.ReplaceAll(strings.TrimPrefix(path, "/"), "/", "."))
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+	if err != nil {
+		return nil, status.Errorf(codes.Unimplemented, "unknown method %s", path)
+	}
+	md, ok := d.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "unknown method %s", path)
+	}
+	in, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "no type registered for %s", md.Input().FullName())
+	}
+	out, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "no type registered for %s", md.Output().FullName())
+	}
+	return &jsonCodec{in: in, out: out}, nil
+}
+
+func (c *jsonCodec) request(b []byte) ([]byte, error) {
+	m := c.in.New().Interface()
+	if len(bytes.TrimSpace(b)) > 0 {
+		if err := protojson.Unmarshal(b, m); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid JSON request: %v", err)
+		}
+	}
+	return proto.Marshal(m)
+}
+
+func (c *jsonCodec) response(b []byte) ([]byte, error) {
+	m := c.out.New().Interface()
+	if err := proto.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return protojson.Marshal(m)
+}
+
+// serveConnect handles a Connect call. application/proto and
+// application/json are unary; the connect+ types are streams of
+// length-prefixed messages ending in an end-of-stream message.
+func (h *handler) serveConnect(w http.ResponseWriter, r *http.Request, ct string) {
+	streaming := strings.HasPrefix(ct, "application/connect+")
+	fail := func(err error) {
+		if streaming {
+			w.Header().Set("Content-Type", ct)
+			w.WriteHeader(http.StatusOK)
+			w.Write(envelope(flagEndStream, endStream(status.Convert(err), nil)))
+			return
+		}
+		writeConnectError(w, status.Convert(err))
+	}
+
+	var c codec = protoCodec{}
+	if connectContentType(ct) == "json" {
+		jc, err := newJSONCodec(r.URL.Path)
+		if err != nil {
+			fail(err)
+			return
+		}
+		c = jc
+	}
+	encoding := r.Header.Get("Content-Encoding")
+	if streaming {
+		encoding = r.Header.Get("Connect-Content-Encoding")
+	}
+	if encoding != "" && encoding != "identity" {
+		fail(status.Errorf(codes.Unimplemented, "unsupported compression %q", encoding))
+		return
+	}
+	if t := r.Header.Get("Connect-Timeout-Ms"); t != "" {
+		ms, err := strconv.ParseUint(t, 10, 63)
+		if err != nil || len(t) > 10 {
+			fail(status.Errorf(codes.InvalidArgument, "invalid Connect-Timeout-Ms %q", t))
+			return
+		}
+		r.Header.Set("Grpc-Timeout", strconv.FormatUint(ms, 10)+"m")
+	}
+
+	if streaming {
+		h.serveConnectStream(w, r, ct, c)
+		return
+	}
+	h.serveConnectUnary(w, r, ct, c, fail)
+}
+
+func (h *handler) serveConnectUnary(w http.ResponseWriter, r *http.Request, ct string, c codec, fail func(error)) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err != nil {
+		fail(status.Errorf(codes.InvalidArgument, "reading request: %v", err))
+		return
+	}
+	msg, err := c.request(body)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	var header http.Header
+	var resp []byte
+	rec := newRecorder(
+		func(hdr http.Header) { header = hdr },
+		func(msg []byte) error { resp = msg; return nil },
+		func() {},
+	)
+	h.grpc.ServeHTTP(rec, grpcRequest(r, bytes.NewReader(envelope(0, msg))))
+
+	st, trailer := rec.result()
+	for k, vv := range header {
+		w.Header()[k] = vv
+	}
+	for k, vv := range trailer {
+		for _, v := range vv {
+			w.Header().Add("Trailer-"+k, v)
+		}
+	}
+	if st.Code() != codes.OK {
+		writeConnectError(w, st)
+		return
+	}
+	out, err := c.response(resp)
+	if err != nil {
+		writeConnectError(w, status.Convert(err))
+		return
+	}
+	w.Header().Set("Content-Type", ct)
+	w.Write(out)
+}
+
+func (h *handler) serveConnectStream(w http.ResponseWriter, r *http.Request, ct string, c codec) {
+	body, failed := transcode(http.MaxBytesReader(w, r.Body, maxRequestBytes), c)
+
+	flusher, _ := w.(http.Flusher)
+	rec := newRecorder(
+		func(hdr http.Header) {
+			for k, vv := range hdr {
+				w.Header()[k] = vv
+			}
+			w.Header().Set("Content-Type", ct)
+			w.WriteHeader(http.StatusOK)
+		},
+		func(msg []byte) error {
+			out, err := c.response(msg)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(envelope(0, out))
+			return err
+		},
+		func() {
+			if flusher != nil {
+				flusher.Flush()
+			}
+		},
+	)
+	h.grpc.ServeHTTP(rec, grpcRequest(r, body))
+
+	rec.WriteHeader(http.StatusOK)
+	st, trailer := rec.result()
+	if err := failed(); err != nil && st.Code() != codes.OK {
+		//Report the bad request rather than the read error grpc saw.
+		var ok bool
+		if st, ok = status.FromError(err); !ok {
+			st = status.Newf(codes.InvalidArgument, "reading request: %v", err)
+		}
+	}
+	w.Write(envelope(flagEndStream, endStream(st, trailer)))
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
+// transcode re-frames the Connect request stream in src as a gRPC request
+// stream, converting each message with c. grpc only sees a failed read, so
+// the returned func reports why converting failed.
+func transcode(src io.Reader, c codec) (io.Reader, func() error) {
+	if _, ok := c.(protoCodec); ok {
+		return src, func() error { return nil }
+	}
+	var mu sync.Mutex
+	var failed error
+	pr, pw := io.Pipe()
+	go func() {
+		for {
+			flags, msg, err := readEnvelope(src)
+			if err == io.EOF {
+				pw.Close()
+				return
+			}
+			if err == nil && flags&flagCompressed != 0 {
+				err = status.Error(codes.Unimplemented, "compressed messages are not supported")
+			}
+			if err == nil {
+				msg, err = c.request(msg)
+			}
+			if err == nil {
+				_, err = pw.Write(envelope(0, msg))
+			}
+			if err != nil {
+				mu.Lock()
+				failed = err
+				mu.Unlock()
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return pr, func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		return failed
+	}
+}
+
+type connectDetail struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type connectError struct {
+	Code    string          `json:"code"`
+	Message string          `json:"message,omitempty"`
+	Details []connectDetail `json:"details,omitempty"`
+}
+
+func newConnectError(st *status.Status) *connectError {
+	e := &connectError{Code: statusCodes[st.Code()].name, Message: st.Message()}
+	if e.Code == "" {
+		e.Code = "unknown"
+	}
+	for _, d := range st.Proto().GetDetails() {
+		e.Details = append(e.Details, connectDetail{
+			Type:  strings.TrimPrefix(d.GetTypeUrl(), "type.googleapis.com/"),
+			Value: base64.RawStdEncoding.EncodeToString(d.GetValue()),
+		})
+	}
+	return e
+}
+
+func writeConnectError(w http.ResponseWriter, st *status.Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(HTTPStatus(st.Code()))
+	json.NewEncoder(w).Encode(newConnectError(st))
+}
+
+// endStream builds the JSON payload of a Connect end-of-stream message.
+func endStream(st *status.Status, trailer metadata.MD) []byte {
+	var end struct {
+		Error    *connectError       `json:"error,omitempty"`
+		Metadata map[string][]string `json:"metadata,omitempty"`
+	}
+	if st.Code() != codes.OK {
+		end.Error = newConnectError(st)
+	}
+	if len(trailer) > 0 {
+		end.Metadata = trailer
+	}
+	b, _ := json.Marshal(&end)
+	return b
+}
+
+// marshalStatus encodes st for the grpc-status-details-bin trailer.
+func marshalStatus(st *status.Status) (string, error) {
+	b, err := proto.Marshal(st.Proto())
+	if err != nil {
+		return "", err
+	}
+	return base64.RawStdEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,95 @@
+package web
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// serveGRPCWeb handles a gRPC-Web call. The request body is already gRPC
+// framed; the response gets its trailers appended as a final frame. The
+// -text variants are base64 encoded in both directions.
+func (h *handler) serveGRPCWeb(w http.ResponseWriter, r *http.Request, ct string) {
+	text := strings.HasPrefix(ct, "application/grpc-web-text")
+	var body io.Reader = http.MaxBytesReader(w, r.Body, maxRequestBytes)
+	respType := "application/grpc-web+proto"
+	if text {
+		body = base64.NewDecoder(base64.StdEncoding, body)
+		respType = "application/grpc-web-text+proto"
+	}
+
+	flusher, _ := w.(http.Flusher)
+	write := func(frame []byte) error {
+		if text {
+			frame = []byte(base64.StdEncoding.EncodeToString(frame))
+		}
+		_, err := w.Write(frame)
+		return err
+	}
+	rec := newRecorder(
+		func(hdr http.Header) {
+			for k, vv := range hdr {
+				w.Header()[k] = vv
+			}
+			w.Header().Set("Content-Type", respType)
+			w.WriteHeader(http.StatusOK)
+		},
+		func(msg []byte) error {
+			return write(envelope(0, msg))
+		},
+		func() {
+			if flusher != nil {
+				flusher.Flush()
+			}
+		},
+	)
+	h.grpc.ServeHTTP(rec, grpcRequest(r, body))
+
+	rec.WriteHeader(http.StatusOK)
+	st, trailer := rec.result()
+	write(envelope(flagTrailer, grpcWebTrailer(st, trailer)))
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
+// grpcWebTrailer encodes the status and trailer metadata as the HTTP/1
+// style header block gRPC-Web sends in its trailer frame.
+func grpcWebTrailer(st *status.Status, trailer metadata.MD) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "grpc-status: %d\r\n", st.Code())
+	if m := st.Message(); m != "" {
+		fmt.Fprintf(&b, "grpc-message: %s\r\n", encodeMessage(m))
+	}
+	if p := st.Proto(); len(p.GetDetails()) > 0 {
+		if d, err := marshalStatus(st); err == nil {
+			fmt.Fprintf(&b, "grpc-status-details-bin: %s\r\n", d)
+		}
+	}
+	for k, vv := range trailer {
+		for _, v := range vv {
+			fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+		}
+	}
+	return b.Bytes()
+}
+
+// encodeMessage percent encodes m as grpc-message requires.
+func encodeMessage(m string) string {
+	var b strings.Builder
+	for i := 0; i < len(m); i++ {
+		c := m[i]
+		if c >= ' ' && c <= '~' && c != '%' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
@@ -0,0 +1,438 @@
+// Package web lets browsers call gRPC services. It serves gRPC-Web and the
+// Connect protocol next to native gRPC on one port by translating each
+// request into a call on the *grpc.Server, so every interceptor still
+// applies.
+package web
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Config configures the browser facing protocols.
+type Config struct {
+	//AllowedOrigins lists the origins allowed to make cross-origin calls.
+	//"*" allows any origin. Requests without an Origin header are always
+	//allowed.
+	AllowedOrigins []string
+	//MaxConcurrentStreams caps streams per HTTP/2 connection.
+	MaxConcurrentStreams uint
+
+	//The connection limits below mirror the grpc keepalive parameters.
+	//Zero leaves the net/http default.
+
+	//MaxConnectionIdle closes connections with no open requests.
+	MaxConnectionIdle time.Duration
+	//MaxConnectionAge closes connections once they are this old: idle
+	//ones straight away, busy ones when their requests finish or at the
+	//latest MaxConnectionAgeGrace later.
+	MaxConnectionAge      time.Duration
+	MaxConnectionAgeGrace time.Duration
+	//KeepaliveTime pings HTTP/2 clients that have sent nothing for this
+	//long and closes the connection if the ack takes KeepaliveTimeout.
+	KeepaliveTime    time.Duration
+	KeepaliveTimeout time.Duration
+}
+
+// ParseOrigins splits a comma separated origin list.
+func ParseOrigins(s string) []string {
+	var origins []string
+	for _, o := range strings.Split(s, ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			origins = append(origins, o)
+		}
+	}
+	return origins
+}
+
+// allowedHeaders are the request headers browsers may send.
+var allowedHeaders = []string{
+	"Authorization",
+	"Content-Type",
+	"Connect-Protocol-Version",
+	"Connect-Timeout-Ms",
+	"Connect-Content-Encoding",
+	"Connect-Accept-Encoding",
+	"Grpc-Timeout",
+	"X-Grpc-Web",
+	"X-User-Agent",
+	"X-Request-Id",
+}
+
+// exposedHeaders are the response headers browsers may read.
+var exposedHeaders = []string{
+	"Grpc-Status",
+	"Grpc-Message",
+	"Grpc-Status-Details-Bin",
+	"X-Request-Id",
+	"Retry-After",
+}
+
+// maxRequestBytes caps a buffered request body.
+const maxRequestBytes = 4 << 20
+
+// Handler returns an http.Handler serving native gRPC, gRPC-Web and Connect
+// requests for s.
+func Handler(s *grpc.Server, cfg Config) http.Handler {
+	return &handler{grpc: s, cfg: cfg}
+}
+
+// NewServer returns an http.Server running Handler. Plaintext connections
+// may use HTTP/1.1 or HTTP/2 without TLS (h2c), so native gRPC clients keep
+// working. tlsCfg may be nil.
+//
+// Connections are handled by net/http rather than grpc, so the keepalive
+// and connection age limits of grpc.ServerOption are applied through the
+// matching Config fields instead.
+func NewServer(s *grpc.Server, cfg Config, tlsCfg *tls.Config) *http.Server {
+	hs := &http.Server{
+		Handler:           Handler(s, cfg),
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       cfg.MaxConnectionIdle,
+		Protocols:         new(http.Protocols),
+		HTTP2: &http.HTTP2Config{
+			MaxConcurrentStreams: int(cfg.MaxConcurrentStreams),
+			SendPingTimeout:      cfg.KeepaliveTime,
+			PingTimeout:          cfg.KeepaliveTimeout,
+		},
+	}
+	if cfg.MaxConnectionAge > 0 {
+		ages := &connAges{age: cfg.MaxConnectionAge, grace: cfg.MaxConnectionAgeGrace, conns: map[net.Conn]*connAge{}}
+		hs.ConnState = ages.track
+	}
+	hs.Protocols.SetHTTP1(true)
+	if tlsCfg == nil {
+		hs.Protocols.SetUnencryptedHTTP2(true)
+		return hs
+	}
+	hs.Protocols.SetHTTP2(true)
+	hs.TLSConfig = tlsCfg.Clone()
+	if !slices.Contains(hs.TLSConfig.NextProtos, "http/1.1") {
+		hs.TLSConfig.NextProtos = append(hs.TLSConfig.NextProtos, "http/1.1")
+	}
+	return hs
+}
+
+// connAges enforces MaxConnectionAge. net/http cannot drain a single
+// connection, so an expired one is closed as soon as it has no request in
+// flight, or after the grace period regardless.
+type connAges struct {
+	age, grace time.Duration
+
+	mu    sync.Mutex
+	conns map[net.Conn]*connAge
+}
+
+type connAge struct {
+	idle, expired bool
+	timers        []*time.Timer
+}
+
+func (a *connAges) track(c net.Conn, state http.ConnState) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	switch state {
+	case http.StateNew:
+		ca := &connAge{}
+		a.conns[c] = ca
+		ca.timers = append(ca.timers,
+			time.AfterFunc(a.age, func() { a.expire(c) }),
+			time.AfterFunc(a.age+a.grace, func() { c.Close() }),
+		)
+	case http.StateActive:
+		if ca, ok := a.conns[c]; ok {
+			ca.idle = false
+		}
+	case http.StateIdle:
+		if ca, ok := a.conns[c]; ok {
+			ca.idle = true
+			if ca.expired {
+				c.Close()
+			}
+		}
+	case http.StateHijacked, http.StateClosed:
+		if ca, ok := a.conns[c]; ok {
+			for _, t := range ca.timers {
+				t.Stop()
+			}
+			delete(a.conns, c)
+		}
+	}
+}
+
+func (a *connAges) expire(c net.Conn) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if ca, ok := a.conns[c]; ok {
+		ca.expired = true
+		if ca.idle {
+			c.Close()
+		}
+	}
+}
+
+type handler struct {
+	grpc *grpc.Server
+	cfg  Config
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ct := r.Header.Get("Content-Type")
+	if r.ProtoMajor == 2 && strings.HasPrefix(ct, "application/grpc") && !strings.HasPrefix(ct, "application/grpc-web") {
+		h.grpc.ServeHTTP(w, r)
+		return
+	}
+	if !h.cors(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	switch {
+	case strings.HasPrefix(ct, "application/grpc-web"):
+		h.serveGRPCWeb(w, r, ct)
+	case connectContentType(ct) != "":
+		h.serveConnect(w, r, ct)
+	default:
+		http.Error(w, "unsupported content type "+ct, http.StatusUnsupportedMediaType)
+	}
+}
+
+func (h *handler) originAllowed(origin string) bool {
+	return slices.Contains(h.cfg.AllowedOrigins, "*") || slices.Contains(h.cfg.AllowedOrigins, origin)
+}
+
+// cors applies the CORS policy. It reports false when the request has been
+// fully handled, either as a preflight or as a rejected origin.
+func (h *handler) cors(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	w.Header().Add("Vary", "Origin")
+	if !h.originAllowed(origin) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Expose-Headers", strings.Join(exposedHeaders, ", "))
+	if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+		return true
+	}
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", strings.Join(allowedHeaders, ", "))
+	w.Header().Set("Access-Control-Max-Age", "7200")
+	w.WriteHeader(http.StatusNoContent)
+	return false
+}
+
+// grpcRequest turns r into a native gRPC request carrying body. Other
+// headers become call metadata as usual.
+func grpcRequest(r *http.Request, body io.Reader) *http.Request {
+	g := r.Clone(r.Context())
+	g.Proto, g.ProtoMajor, g.ProtoMinor = "HTTP/2.0", 2, 0
+	g.Body = io.NopCloser(body)
+	g.ContentLength = -1
+	g.Header.Del("Content-Length")
+	g.Header.Set("Content-Type", "application/grpc+proto")
+	g.Header.Set("Te", "trailers")
+	return g
+}
+
+// Message flags of the length-prefixed framing shared by gRPC, gRPC-Web
+// and Connect streams.
+const (
+	flagCompressed = 0x01
+	flagEndStream  = 0x02 //Connect end-of-stream message
+	flagTrailer    = 0x80 //gRPC-Web trailers
+)
+
+func envelope(flags byte, msg []byte) []byte {
+	b := make([]byte, 5+len(msg))
+	b[0] = flags
+	binary.BigEndian.PutUint32(b[1:5], uint32(len(msg)))
+	copy(b[5:], msg)
+	return b
+}
+
+// readEnvelope reads one length-prefixed message from r.
+func readEnvelope(r io.Reader) (byte, []byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return 0, nil, err
+	}
+	n := binary.BigEndian.Uint32(prefix[1:])
+	if n > maxRequestBytes {
+		return 0, nil, status.Errorf(codes.ResourceExhausted, "message of %d bytes exceeds the %d byte limit", n, maxRequestBytes)
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	return prefix[0], msg, nil
+}
+
+// recorder is the http.ResponseWriter handed to grpc.Server.ServeHTTP. It
+// splits the gRPC response into headers, messages and trailers and passes
+// each to the protocol being translated to.
+type recorder struct {
+	header      http.Header
+	wroteHeader bool
+	code        int
+
+	onHeader  func(http.Header)
+	onMessage func(msg []byte) error
+	onFlush   func()
+
+	buf []byte
+	err error
+}
+
+func newRecorder(onHeader func(http.Header), onMessage func([]byte) error, onFlush func()) *recorder {
+	return &recorder{header: http.Header{}, onHeader: onHeader, onMessage: onMessage, onFlush: onFlush}
+}
+
+func (rec *recorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *recorder) WriteHeader(code int) {
+	if rec.wroteHeader {
+		return
+	}
+	rec.wroteHeader, rec.code = true, code
+	rec.onHeader(responseHeaders(rec.header))
+}
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	rec.WriteHeader(http.StatusOK)
+	if rec.err != nil {
+		return 0, rec.err
+	}
+	rec.buf = append(rec.buf, p...)
+	for len(rec.buf) >= 5 {
+		n := int(binary.BigEndian.Uint32(rec.buf[1:5]))
+		if len(rec.buf) < 5+n {
+			break
+		}
+		if rec.err = rec.onMessage(bytes.Clone(rec.buf[5 : 5+n])); rec.err != nil {
+			return 0, rec.err
+		}
+		rec.buf = rec.buf[5+n:]
+	}
+	return len(p), nil
+}
+
+func (rec *recorder) Flush() {
+	rec.WriteHeader(http.StatusOK)
+	rec.onFlush()
+}
+
+// responseHeaders returns the call's header metadata, leaving out the
+// gRPC transport headers.
+func responseHeaders(h http.Header) http.Header {
+	out := http.Header{}
+	for k, vv := range h {
+		switch k {
+		case "Content-Type", "Trailer", "Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin":
+			continue
+		}
+		if !strings.HasPrefix(k, http.TrailerPrefix) {
+			out[k] = vv
+		}
+	}
+	return out
+}
+
+// result returns the call's status and trailer metadata once ServeHTTP has
+// returned. Trailer keys are lower case.
+func (rec *recorder) result() (*status.Status, metadata.MD) {
+	trailer := metadata.MD{}
+	for k, vv := range rec.header {
+		if name, ok := strings.CutPrefix(k, http.TrailerPrefix); ok {
+			trailer.Append(strings.ToLower(name), vv...)
+		}
+	}
+
+	code := rec.header.Get("Grpc-Status")
+	if code == "" {
+		if rec.code != 0 && rec.code != http.StatusOK {
+			return status.Newf(codes.Internal, "gRPC server answered HTTP %d", rec.code), trailer
+		}
+		return status.New(codes.Unknown, "gRPC response has no status"), trailer
+	}
+	if d := rec.header.Get("Grpc-Status-Details-Bin"); d != "" {
+		if b, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(d, "=")); err == nil {
+			p := &spb.Status{}
+			if proto.Unmarshal(b, p) == nil {
+				return status.FromProto(p), trailer
+			}
+		}
+	}
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return status.Newf(codes.Unknown, "invalid grpc-status %q", code), trailer
+	}
+	msg, err := url.PathUnescape(rec.header.Get("Grpc-Message"))
+	if err != nil {
+		msg = rec.header.Get("Grpc-Message")
+	}
+	return status.New(codes.Code(n), msg), trailer
+}
+
+var statusCodes = map[codes.Code]struct {
+	name string
+	http int
+}{
+	codes.Canceled:           {"canceled", 499},
+	codes.Unknown:            {"unknown", http.StatusInternalServerError},
+	codes.InvalidArgument:    {"invalid_argument", http.StatusBadRequest},
+	codes.DeadlineExceeded:   {"deadline_exceeded", http.StatusGatewayTimeout},
+	codes.NotFound:           {"not_found", http.StatusNotFound},
+	codes.AlreadyExists:      {"already_exists", http.StatusConflict},
+	codes.PermissionDenied:   {"permission_denied", http.StatusForbidden},
+	codes.ResourceExhausted:  {"resource_exhausted", http.StatusTooManyRequests},
+	codes.FailedPrecondition: {"failed_precondition", http.StatusBadRequest},
+	codes.Aborted:            {"aborted", http.StatusConflict},
+	codes.OutOfRange:         {"out_of_range", http.StatusBadRequest},
+	codes.Unimplemented:      {"unimplemented", http.StatusNotImplemented},
+	codes.Internal:           {"internal", http.StatusInternalServerError},
+	codes.Unavailable:        {"unavailable", http.StatusServiceUnavailable},
+	codes.DataLoss:           {"data_loss", http.StatusInternalServerError},
+	codes.Unauthenticated:    {"unauthenticated", http.StatusUnauthorized},
+}
+
+// HTTPStatus maps a gRPC code to the closest HTTP status, as the Connect
+// protocol and grpc-gateway do.
+func HTTPStatus(c codes.Code) int {
+	if c == codes.OK {
+		return http.StatusOK
+	}
+	if s, ok := statusCodes[c]; ok {
+		return s.http
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,551 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	protov1 "github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwfrizzell/grpc-go-course/blog/blogpb"
+	"github.com/jwfrizzell/grpc-go-course/greet/greetpb"
+)
+
+const (
+	methodGreet          = "/greet.GreetService/Greet"
+	methodGreetManyTimes = "/greet.GreetService/GreetManyTimes"
+	methodListBlog       = "/blog.BlogService/ListBlog"
+	allowedOrigin        = "https://app.example"
+)
+
+// greetServer greets by first name and fails without one.
+type greetServer struct {
+	greetpb.UnimplementedGreetServiceServer
+}
+
+func (greetServer) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
+	grpc.SetHeader(ctx, metadata.Pairs("x-greeter", "test"))
+	name := req.GetGreeting().GetFirstName()
+	if name == "" {
+		grpc.SetTrailer(ctx, metadata.Pairs("x-reason", "empty name"))
+		return nil, status.Error(codes.InvalidArgument, "first_name is required")
+	}
+	grpc.SetTrailer(ctx, metadata.Pairs("x-greeted", name))
+	return &greetpb.GreetResponse{Result: "Hello " + name}, nil
+}
+
+func (greetServer) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreetService_GreetManyTimesServer) error {
+	for i := 1; i <= 3; i++ {
+		res := &greetpb.GreetManyTimesResponse{Result: fmt.Sprintf("Hello %s number %d", req.GetGreeting().GetFirstName(), i)}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+	stream.SetTrailer(metadata.Pairs("x-sent", "3"))
+	return nil
+}
+
+// blogServer lists two blogs, then fails for the author "broken".
+type blogServer struct {
+	blogpb.UnimplementedBlogServiceServer
+}
+
+func (blogServer) ListBlog(req *blogpb.ListBlogRequest, stream blogpb.BlogService_ListBlogServer) error {
+	for _, id := range []string{"b1", "b2"} {
+		if err := stream.Send(&blogpb.ListBlogResponse{Blog: &blogpb.Blog{Id: id, AuthorId: req.GetAuthorId(), Title: "Title " + id}}); err != nil {
+			return err
+		}
+	}
+	stream.SetTrailer(metadata.Pairs("x-listed", "2"))
+	if req.GetAuthorId() == "broken" {
+		return status.Error(codes.Unavailable, "database went away")
+	}
+	return nil
+}
+
+func newTestServer(t *testing.T, origins ...string) *httptest.Server {
+	t.Helper()
+	s := grpc.NewServer()
+	greetpb.RegisterGreetServiceServer(s, &greetServer{})
+	blogpb.RegisterBlogServiceServer(s, &blogServer{})
+	ts := httptest.NewServer(Handler(s, Config{AllowedOrigins: origins}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func post(t *testing.T, ts *httptest.Server, method, ct string, body []byte, header http.Header) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, ts.URL+method, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, vv := range header {
+		req.Header[k] = vv
+	}
+	req.Header.Set("Content-Type", ct)
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", method, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func marshal(t *testing.T, m protov1.Message) []byte {
+	t.Helper()
+	b, err := protov1.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// readFrames splits a length-prefixed response body into its frames.
+func readFrames(t *testing.T, r io.Reader) (flags []byte, msgs [][]byte) {
+	t.Helper()
+	for {
+		f, msg, err := readEnvelope(r)
+		if err == io.EOF {
+			return flags, msgs
+		}
+		if err != nil {
+			t.Fatalf("reading frame: %v", err)
+		}
+		flags = append(flags, f)
+		msgs = append(msgs, msg)
+	}
+}
+
+// grpcWebCall makes a gRPC-Web call and returns the response messages and
+// the parsed trailer frame.
+func grpcWebCall(t *testing.T, ts *httptest.Server, method string, req protov1.Message, text bool) ([][]byte, map[string]string) {
+	t.Helper()
+	ct := "application/grpc-web+proto"
+	body := envelope(0, marshal(t, req))
+	if text {
+		ct = "application/grpc-web-text+proto"
+		body = []byte(base64.StdEncoding.EncodeToString(body))
+	}
+	resp := post(t, ts, method, ct, body, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("HTTP status %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Type"); got != ct {
+		t.Errorf("Content-Type = %q, want %q", got, ct)
+	}
+	var r io.Reader = resp.Body
+	if text {
+		//Each frame is base64 encoded on its own, padding included.
+		raw, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded []byte
+		for len(raw) > 0 {
+			n := base64.StdEncoding.DecodedLen(len(raw))
+			buf := make([]byte, n)
+			m, err := base64.StdEncoding.Decode(buf, raw)
+			if err == nil {
+				decoded = append(decoded, buf[:m]...)
+				break
+			}
+			//Decode up to the end of the first padded chunk.
+			end := bytes.IndexByte(raw, '=')
+			for end+1 < len(raw) && raw[end+1] == '=' {
+				end++
+			}
+			m, err = base64.StdEncoding.Decode(buf, raw[:end+1])
+			if err != nil {
+				t.Fatalf("decoding grpc-web-text: %v", err)
+			}
+			decoded = append(decoded, buf[:m]...)
+			raw = raw[end+1:]
+		}
+		r = bytes.NewReader(decoded)
+	}
+
+	flags, frames := readFrames(t, r)
+	if len(frames) == 0 || flags[len(flags)-1] != flagTrailer {
+		t.Fatalf("flags = %v, want a final trailer frame", flags)
+	}
+	trailer := map[string]string{}
+	sc := bufio.NewScanner(bytes.NewReader(frames[len(frames)-1]))
+	for sc.Scan() {
+		k, v, _ := strings.Cut(sc.Text(), ": ")
+		trailer[k] = strings.TrimSpace(v)
+	}
+	for _, f := range flags[:len(flags)-1] {
+		if f != 0 {
+			t.Errorf("data frame flags %#x", f)
+		}
+	}
+	return frames[:len(frames)-1], trailer
+}
+
+func TestGRPCWeb(t *testing.T) {
+	ts := newTestServer(t)
+
+	t.Run("unary", func(t *testing.T) {
+		msgs, trailer := grpcWebCall(t, ts, methodGreet, &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "Ann"}}, false)
+		if len(msgs) != 1 {
+			t.Fatalf("got %d messages, want 1", len(msgs))
+		}
+		res := &greetpb.GreetResponse{}
+		if err := protov1.Unmarshal(msgs[0], res); err != nil || res.GetResult() != "Hello Ann" {
+			t.Errorf("response = %v, %v", res, err)
+		}
+		if trailer["grpc-status"] != "0" || trailer["x-greeted"] != "Ann" {
+			t.Errorf("trailer = %v", trailer)
+		}
+	})
+
+	t.Run("unary error", func(t *testing.T) {
+		msgs, trailer := grpcWebCall(t, ts, methodGreet, &greetpb.GreetRequest{}, false)
+		if len(msgs) != 0 {
+			t.Errorf("got %d messages, want none", len(msgs))
+		}
+		want := map[string]string{
+			"grpc-status":  fmt.Sprint(int(codes.InvalidArgument)),
+			"grpc-message": "first_name is required",
+			"x-reason":     "empty name",
+		}
+		for k, v := range want {
+			if trailer[k] != v {
+				t.Errorf("trailer %s = %q, want %q", k, trailer[k], v)
+			}
+		}
+	})
+
+	t.Run("GreetManyTimes", func(t *testing.T) {
+		msgs, trailer := grpcWebCall(t, ts, methodGreetManyTimes, &greetpb.GreetManyTimesRequest{Greeting: &greetpb.Greeting{FirstName: "Ann"}}, false)
+		if len(msgs) != 3 {
+			t.Fatalf("got %d messages, want 3", len(msgs))
+		}
+		for i, msg := range msgs {
+			res := &greetpb.GreetManyTimesResponse{}
+			want := fmt.Sprintf("Hello Ann number %d", i+1)
+			if err := protov1.Unmarshal(msg, res); err != nil || res.GetResult() != want {
+				t.Errorf("message %d = %v, %v, want %q", i, res, err, want)
+			}
+		}
+		if trailer["grpc-status"] != "0" || trailer["x-sent"] != "3" {
+			t.Errorf("trailer = %v", trailer)
+		}
+	})
+
+	t.Run("ListBlog text", func(t *testing.T) {
+		msgs, trailer := grpcWebCall(t, ts, methodListBlog, &blogpb.ListBlogRequest{AuthorId: "ann"}, true)
+		if len(msgs) != 2 {
+			t.Fatalf("got %d messages, want 2", len(msgs))
+		}
+		res := &blogpb.ListBlogResponse{}
+		if err := protov1.Unmarshal(msgs[1], res); err != nil || res.GetBlog().GetId() != "b2" || res.GetBlog().GetAuthorId() != "ann" {
+			t.Errorf("second blog = %v, %v", res, err)
+		}
+		if trailer["grpc-status"] != "0" || trailer["x-listed"] != "2" {
+			t.Errorf("trailer = %v", trailer)
+		}
+	})
+
+	t.Run("ListBlog error after messages", func(t *testing.T) {
+		msgs, trailer := grpcWebCall(t, ts, methodListBlog, &blogpb.ListBlogRequest{AuthorId: "broken"}, false)
+		if len(msgs) != 2 {
+			t.Errorf("got %d messages, want 2", len(msgs))
+		}
+		if trailer["grpc-status"] != fmt.Sprint(int(codes.Unavailable)) || trailer["x-listed"] != "2" {
+			t.Errorf("trailer = %v", trailer)
+		}
+	})
+}
+
+func TestConnectUnary(t *testing.T) {
+	ts := newTestServer(t)
+	ann := &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "Ann"}}
+
+	tests := []struct {
+		name       string
+		method     string
+		ct         string
+		body       []byte
+		header     http.Header
+		wantStatus int
+		//wantResult is the greeting expected on success.
+		wantResult string
+		//wantCode is the Connect error code expected on failure.
+		wantCode string
+	}{
+		{name: "json", method: methodGreet, ct: "application/json", body: []byte(`{"greeting":{"firstName":"Ann"}}`), wantStatus: http.StatusOK, wantResult: "Hello Ann"},
+		{name: "json with charset", method: methodGreet, ct: "application/json; charset=utf-8", body: []byte(`{"greeting":{"first_name":"Bo"}}`), wantStatus: http.StatusOK, wantResult: "Hello Bo"},
+		{name: "proto", method: methodGreet, ct: "application/proto", body: marshal(t, ann), wantStatus: http.StatusOK, wantResult: "Hello Ann"},
+		{name: "json error", method: methodGreet, ct: "application/json", body: []byte(`{}`), wantStatus: http.StatusBadRequest, wantCode: "invalid_argument"},
+		{name: "proto error", method: methodGreet, ct: "application/proto", body: nil, wantStatus: http.StatusBadRequest, wantCode: "invalid_argument"},
+		{name: "invalid json", method: methodGreet, ct: "application/json", body: []byte(`{"greeting":`), wantStatus: http.StatusBadRequest, wantCode: "invalid_argument"},
+		{name: "unknown method", method: "/greet.GreetService/Missing", ct: "application/json", body: []byte(`{}`), wantStatus: http.StatusNotImplemented, wantCode: "unimplemented"},
+		{name: "compressed", method: methodGreet, ct: "application/json", body: []byte(`{}`), header: http.Header{"Content-Encoding": {"gzip"}}, wantStatus: http.StatusNotImplemented, wantCode: "unimplemented"},
+		{name: "bad timeout", method: methodGreet, ct: "application/json", body: []byte(`{}`), header: http.Header{"Connect-Timeout-Ms": {"soon"}}, wantStatus: http.StatusBadRequest, wantCode: "invalid_argument"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := post(t, ts, tc.method, tc.ct, tc.body, tc.header)
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("HTTP status %d, want %d: %s", resp.StatusCode, tc.wantStatus, body)
+			}
+			if tc.wantCode != "" {
+				var e connectError
+				if err := json.Unmarshal(body, &e); err != nil || e.Code != tc.wantCode {
+					t.Errorf("error body %s, want code %s", body, tc.wantCode)
+				}
+				return
+			}
+
+			if got := resp.Header.Get("Content-Type"); got != tc.ct {
+				t.Errorf("Content-Type = %q, want %q", got, tc.ct)
+			}
+			res := &greetpb.GreetResponse{}
+			if strings.HasPrefix(tc.ct, "application/json") {
+				var out map[string]string
+				if err := json.Unmarshal(body, &out); err != nil {
+					t.Fatalf("response %s: %v", body, err)
+				}
+				res.Result = out["result"]
+			} else if err := protov1.Unmarshal(body, res); err != nil {
+				t.Fatalf("response: %v", err)
+			}
+			if res.GetResult() != tc.wantResult {
+				t.Errorf("result = %q, want %q", res.GetResult(), tc.wantResult)
+			}
+			if got := resp.Header.Get("X-Greeter"); got != "test" {
+				t.Errorf("header x-greeter = %q, want test", got)
+			}
+			if got := resp.Header.Get("Trailer-X-Greeted"); got == "" {
+				t.Error("trailer x-greeted not sent as a Trailer- header")
+			}
+		})
+	}
+}
+
+// endOfStream is the decoded Connect end-of-stream message.
+type endOfStream struct {
+	Error    *connectError       `json:"error"`
+	Metadata map[string][]string `json:"metadata"`
+}
+
+func TestConnectStream(t *testing.T) {
+	ts := newTestServer(t)
+
+	tests := []struct {
+		name     string
+		method   string
+		ct       string
+		req      []byte
+		//truncate cuts bytes off the end of the request body.
+		truncate int
+		wantMsgs []string
+		wantCode string
+		wantMeta map[string]string
+	}{
+		{
+			name:     "GreetManyTimes json",
+			method:   methodGreetManyTimes,
+			ct:       "application/connect+json",
+			req:      []byte(`{"greeting":{"firstName":"Ann"}}`),
+			wantMsgs: []string{"Hello Ann number 1", "Hello Ann number 2", "Hello Ann number 3"},
+			wantMeta: map[string]string{"x-sent": "3"},
+		},
+		{
+			name:     "GreetManyTimes proto",
+			method:   methodGreetManyTimes,
+			ct:       "application/connect+proto",
+			req:      marshal(t, &greetpb.GreetManyTimesRequest{Greeting: &greetpb.Greeting{FirstName: "Bo"}}),
+			wantMsgs: []string{"Hello Bo number 1", "Hello Bo number 2", "Hello Bo number 3"},
+			wantMeta: map[string]string{"x-sent": "3"},
+		},
+		{
+			name:     "ListBlog json",
+			method:   methodListBlog,
+			ct:       "application/connect+json",
+			req:      []byte(`{"authorId":"ann"}`),
+			wantMsgs: []string{"b1", "b2"},
+			wantMeta: map[string]string{"x-listed": "2"},
+		},
+		{
+			name:     "ListBlog error",
+			method:   methodListBlog,
+			ct:       "application/connect+proto",
+			req:      marshal(t, &blogpb.ListBlogRequest{AuthorId: "broken"}),
+			wantMsgs: []string{"b1", "b2"},
+			wantCode: "unavailable",
+			wantMeta: map[string]string{"x-listed": "2"},
+		},
+		{
+			name:     "invalid json",
+			method:   methodListBlog,
+			ct:       "application/connect+json",
+			req:      []byte(`{"authorId":`),
+			wantCode: "invalid_argument",
+		},
+		{
+			name:     "truncated message",
+			method:   methodListBlog,
+			ct:       "application/connect+json",
+			req:      []byte(`{"authorId":"ann"}`),
+			truncate: 3,
+			wantCode: "invalid_argument",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			body := envelope(0, tc.req)
+			resp := post(t, ts, tc.method, tc.ct, body[:len(body)-tc.truncate], nil)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("HTTP status %d", resp.StatusCode)
+			}
+			if got := resp.Header.Get("Content-Type"); got != tc.ct {
+				t.Errorf("Content-Type = %q, want %q", got, tc.ct)
+			}
+			flags, frames := readFrames(t, resp.Body)
+			if len(frames) == 0 || flags[len(flags)-1] != flagEndStream {
+				t.Fatalf("flags = %v, want a final end-of-stream message", flags)
+			}
+
+			var got []string
+			for _, msg := range frames[:len(frames)-1] {
+				got = append(got, decodeStreamMessage(t, tc.method, tc.ct, msg))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.wantMsgs) {
+				t.Errorf("messages = %q, want %q", got, tc.wantMsgs)
+			}
+
+			var end endOfStream
+			if err := json.Unmarshal(frames[len(frames)-1], &end); err != nil {
+				t.Fatalf("end-of-stream %s: %v", frames[len(frames)-1], err)
+			}
+			code := ""
+			if end.Error != nil {
+				code = end.Error.Code
+			}
+			if code != tc.wantCode {
+				t.Errorf("end-of-stream error %+v, want code %q", end.Error, tc.wantCode)
+			}
+			for k, v := range tc.wantMeta {
+				if fmt.Sprint(end.Metadata[k]) != fmt.Sprint([]string{v}) {
+					t.Errorf("end-of-stream metadata %s = %v, want %s", k, end.Metadata[k], v)
+				}
+			}
+		})
+	}
+}
+
+// decodeStreamMessage returns the greeting or blog id in one streamed
+// response message.
+func decodeStreamMessage(t *testing.T, method, ct string, msg []byte) string {
+	t.Helper()
+	if strings.HasSuffix(ct, "json") {
+		var out struct {
+			Result string `json:"result"`
+			Blog   struct {
+				ID string `json:"id"`
+			} `json:"blog"`
+		}
+		if err := json.Unmarshal(msg, &out); err != nil {
+			t.Fatalf("message %s: %v", msg, err)
+		}
+		return out.Result + out.Blog.ID
+	}
+	if method == methodListBlog {
+		res := &blogpb.ListBlogResponse{}
+		if err := protov1.Unmarshal(msg, res); err != nil {
+			t.Fatal(err)
+		}
+		return res.GetBlog().GetId()
+	}
+	res := &greetpb.GreetManyTimesResponse{}
+	if err := protov1.Unmarshal(msg, res); err != nil {
+		t.Fatal(err)
+	}
+	return res.GetResult()
+}
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins []string
+		method  string
+		origin  string
+		//preflight sends Access-Control-Request-Method.
+		preflight  bool
+		wantStatus int
+		wantAllow  string
+	}{
+		{name: "no origin", method: http.MethodPost, wantStatus: http.StatusOK},
+		{name: "allowed origin", origins: []string{allowedOrigin}, method: http.MethodPost, origin: allowedOrigin, wantStatus: http.StatusOK, wantAllow: allowedOrigin},
+		{name: "rejected origin", origins: []string{allowedOrigin}, method: http.MethodPost, origin: "https://evil.example", wantStatus: http.StatusForbidden},
+		{name: "no origins configured", method: http.MethodPost, origin: allowedOrigin, wantStatus: http.StatusForbidden},
+		{name: "wildcard", origins: []string{"*"}, method: http.MethodPost, origin: "https://any.example", wantStatus: http.StatusOK, wantAllow: "https://any.example"},
+		{name: "allowed preflight", origins: []string{allowedOrigin}, method: http.MethodOptions, origin: allowedOrigin, preflight: true, wantStatus: http.StatusNoContent, wantAllow: allowedOrigin},
+		{name: "rejected preflight", origins: []string{allowedOrigin}, method: http.MethodOptions, origin: "https://evil.example", preflight: true, wantStatus: http.StatusForbidden},
+		{name: "options without preflight", origins: []string{allowedOrigin}, method: http.MethodOptions, origin: allowedOrigin, wantStatus: http.StatusMethodNotAllowed, wantAllow: allowedOrigin},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newTestServer(t, tc.origins...)
+			req, err := http.NewRequest(tc.method, ts.URL+methodGreet, strings.NewReader(`{"greeting":{"firstName":"Ann"}}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			if tc.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				req.Header.Set("Access-Control-Request-Headers", "content-type, authorization")
+			}
+			resp, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("HTTP status %d, want %d", resp.StatusCode, tc.wantStatus)
+			}
+			if got := resp.Header.Get("Access-Control-Allow-Origin"); got != tc.wantAllow {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tc.wantAllow)
+			}
+			if tc.wantAllow != "" && !strings.Contains(resp.Header.Get("Access-Control-Expose-Headers"), "Grpc-Status") {
+				t.Errorf("Access-Control-Expose-Headers = %q", resp.Header.Get("Access-Control-Expose-Headers"))
+			}
+			if tc.preflight && tc.wantStatus == http.StatusNoContent {
+				if got := resp.Header.Get("Access-Control-Allow-Methods"); !strings.Contains(got, "POST") {
+					t.Errorf("Access-Control-Allow-Methods = %q", got)
+				}
+				if got := resp.Header.Get("Access-Control-Allow-Headers"); !strings.Contains(got, "Authorization") {
+					t.Errorf("Access-Control-Allow-Headers = %q", got)
+				}
+			}
+			if tc.origin != "" && resp.Header.Get("Vary") != "Origin" {
+				t.Errorf("Vary = %q, want Origin", resp.Header.Get("Vary"))
+			}
+		})
+	}
+}